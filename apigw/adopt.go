@@ -0,0 +1,111 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "net/http"
+)
+
+// isAmbiguousError reports whether a create which failed with err may
+// nevertheless have been carried out by the gateway, because the request
+// was sent but its response was lost, timed out or was a server error.
+func isAmbiguousError(err error) bool {
+    if code := statusCode(err); code != 0 {
+        return code >= http.StatusInternalServerError
+    }
+
+    return !requestNotSent(err)
+}
+
+// orphanLookup finds the object left behind by a create which failed
+// ambiguously. It is made before the create, so that it knows which objects
+// already matched and adopts only one which appeared since. Adoption is part
+// of retry_idempotent_creates, without it nothing is looked up.
+type orphanLookup struct {
+    resource	string
+    name	string
+    platform	string
+    // find returns the IDs of the objects named name.
+    find	func(ctx context.Context) ([]string, error)
+    // existing are the IDs which matched before the create, nil if they
+    // could not be looked up.
+    existing	map[string]bool
+}
+
+// newOrphanLookup looks up the objects named name on platform which exist
+// before the create of resource, if retry_idempotent_creates is set.
+func newOrphanLookup(
+        ctx context.Context,
+        config *PConfig,
+        resource string,
+        name string,
+        platform string,
+        find func(ctx context.Context) ([]string, error)) *orphanLookup {
+    l := &orphanLookup{
+        resource:	resource,
+        name:		name,
+        platform:	platform,
+        find:		find,
+    }
+
+    if !config.RetryIdempotentCreates {
+        return l
+    }
+
+    ids, err := find(ctx)
+    if err != nil {
+        log.Printf("[WARN] Unable to look for %s %s on %s before creating it, it will not be adopted after a failed create: %v",
+            resource, name, platform, err)
+        return l
+    }
+
+    l.existing = make(map[string]bool)
+    for _, id := range ids {
+        l.existing[id] = true
+    }
+    return l
+}
+
+// adopt is called when the create failed with err. If the gateway may have
+// created the object anyway, the single object named name which did not
+// exist before the create is returned to be adopted into the state instead of
+// being leaked; otherwise err is returned. Once ctx is done, e.g. after an
+// interrupt, nothing is looked up and a possible orphan is only logged.
+func (l *orphanLookup) adopt(ctx context.Context, err error) (string, error) {
+    if !isAmbiguousError(err) || l.existing == nil {
+        return "", err
+    }
+
+    if ctx.Err() != nil {
+        log.Printf("[WARN] %s %s on %s may have been created although its create was stopped, import it or remove it from the gateway: %v",
+            l.resource, l.name, l.platform, err)
+        return "", err
+    }
+
+    found, findErr := l.find(ctx)
+    if findErr != nil {
+        log.Printf("[WARN] Unable to look for %s %s on %s after a failed create: %v",
+            l.resource, l.name, l.platform, findErr)
+        return "", err
+    }
+
+    var ids []string
+    for _, id := range found {
+        if !l.existing[id] {
+            ids = append(ids, id)
+        }
+    }
+
+    switch len(ids) {
+    case 0:
+        return "", err
+    case 1:
+        log.Printf("[WARN] Adopting %s %s on %s as %s, it was created although the create failed: %v",
+            l.resource, l.name, l.platform, ids[0], err)
+        return ids[0], nil
+    }
+
+    return "", fmt.Errorf("%v (found %d new %s named %s, unable to tell which one was created)",
+        err, len(ids), l.resource, l.name)
+}
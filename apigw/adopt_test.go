@@ -0,0 +1,195 @@
+package apigw
+
+import (
+    "context"
+    "errors"
+    "net"
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+    "apigw_plugin/terraform-provider-apigw/apigw/mock"
+)
+
+func TestIsAmbiguousError(t *testing.T) {
+    response := func(code int) error {
+        return ErrUnexpectedResponseCode{Actual: code}
+    }
+
+    cases := []struct {
+        name		string
+        err		error
+        ambiguous	bool
+    }{
+        {"timeout", context.DeadlineExceeded, true},
+        {"reset", &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, true},
+        {"dial", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, false},
+        {"dns", &net.DNSError{Err: "no such host"}, false},
+        {"500", ErrDefault500{ErrUnexpectedResponseCode{Actual: http.StatusInternalServerError}}, true},
+        {"504", response(http.StatusGatewayTimeout), true},
+        {"400", ErrDefault400{ErrUnexpectedResponseCode{Actual: http.StatusBadRequest}}, false},
+        {"409", response(http.StatusConflict), false},
+    }
+
+    for _, c := range cases {
+        if got := isAmbiguousError(c.err); got != c.ambiguous {
+            t.Errorf("%s: isAmbiguousError = %v, want %v", c.name, got, c.ambiguous)
+        }
+    }
+}
+
+func newMockProviderConfig(t *testing.T, server *mock.Server) *PConfig {
+    config := &PConfig{
+        Config: Config{
+            APIGW_APIKEY:	"secret",
+            APIGW_URL:		server.URL + "/",
+            RetryWaitMin:	time.Millisecond,
+            RetryWaitMax:	time.Millisecond,
+            PollInterval:	time.Millisecond,
+        },
+    }
+    if err := config.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+    return config
+}
+
+func TestNetworkCreateAdoptsOrphan(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    config := newMockProviderConfig(t, server)
+    config.RetryIdempotentCreates = true
+
+    // Every response to the create is lost, after the network was created.
+    server.AddFault(mock.Fault{Method: "POST", Path: "/networks/", Status: http.StatusGatewayTimeout, Served: true})
+    d := schema.TestResourceDataRaw(t, resourceNetwork().Schema, map[string]interface{}{
+        "cidr":		"10.0.0.0/24",
+        "gateway":	"10.0.0.1",
+        "name":		"net",
+        "platform":	"openstack-mock",
+        "project":	mock.DefaultProject,
+    })
+    if err := resourceNetworkCreate(d, config); err != nil {
+        t.Fatal(err)
+    }
+
+    networks, err := config.API.Networks.List(context.Background(), "openstack-mock", client.NetworkListOpts{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(networks) != 1 || networks[0].ID.String() != d.Id() {
+        t.Errorf("networks = %v, want only the adopted %s", networks, d.Id())
+    }
+
+    // A failure which did not create anything is reported, even though a
+    // network of the same name already exists.
+    server.ClearFaults()
+    server.AddFault(mock.Fault{Method: "POST", Path: "/networks/", Status: http.StatusGatewayTimeout})
+    d = schema.TestResourceDataRaw(t, resourceNetwork().Schema, map[string]interface{}{
+        "cidr":		"10.0.1.0/24",
+        "gateway":	"10.0.1.1",
+        "name":		"net",
+        "platform":	"openstack-mock",
+        "project":	mock.DefaultProject,
+    })
+    if err := resourceNetworkCreate(d, config); err == nil {
+        t.Error("expected an error")
+    }
+    if d.Id() != "" {
+        t.Errorf("adopted %s, want nothing", d.Id())
+    }
+
+    // Without retry_idempotent_creates, nothing is adopted.
+    config.RetryIdempotentCreates = false
+    server.ClearFaults()
+    server.AddFault(mock.Fault{Method: "POST", Path: "/networks/", Status: http.StatusGatewayTimeout, Served: true})
+    d = schema.TestResourceDataRaw(t, resourceNetwork().Schema, map[string]interface{}{
+        "cidr":		"10.0.2.0/24",
+        "gateway":	"10.0.2.1",
+        "name":		"other",
+        "platform":	"openstack-mock",
+        "project":	mock.DefaultProject,
+    })
+    if err := resourceNetworkCreate(d, config); err == nil {
+        t.Error("expected an error")
+    }
+    if d.Id() != "" {
+        t.Errorf("adopted %s without retry_idempotent_creates, want nothing", d.Id())
+    }
+}
+
+func TestOrphanLookupAdopt(t *testing.T) {
+    createErr := ErrDefault502{ErrUnexpectedResponseCode{Actual: http.StatusBadGateway}}
+    find := func(ids ...string) func(context.Context) ([]string, error) {
+        return func(ctx context.Context) ([]string, error) {
+            if ctx.Err() != nil {
+                t.Error("lookup with a done context")
+            }
+            return ids, nil
+        }
+    }
+    config := &PConfig{Config: Config{RetryIdempotentCreates: true}}
+    lookup := func(existing ...string) *orphanLookup {
+        return newOrphanLookup(context.Background(), config, "apigw_network", "net", "platform", find(existing...))
+    }
+    ctx := context.Background()
+
+    l := lookup("1")
+    l.find = find("1", "2")
+    if id, err := l.adopt(ctx, createErr); err != nil || id != "2" {
+        t.Errorf("adopt = %q, %v, want the new network 2", id, err)
+    }
+
+    l = lookup("1")
+    if id, err := l.adopt(ctx, createErr); statusCode(err) != http.StatusBadGateway || id != "" {
+        t.Errorf("adopt = %q, %v, want the create error for a network which existed before", id, err)
+    }
+
+    l = lookup()
+    l.find = find("1", "2")
+    if _, err := l.adopt(ctx, createErr); err == nil {
+        t.Error("expected an error for two new networks of the same name")
+    }
+
+    // Nothing is adopted if the networks before the create are unknown.
+    l = newOrphanLookup(context.Background(), config, "apigw_network", "net", "platform",
+        func(context.Context) ([]string, error) {
+            return nil, createErr
+        })
+    l.find = find("1")
+    if id, err := l.adopt(ctx, createErr); statusCode(err) != http.StatusBadGateway || id != "" {
+        t.Errorf("adopt = %q, %v, want the create error without a lookup before the create", id, err)
+    }
+
+    conflict := ErrDefault409{ErrUnexpectedResponseCode{Actual: http.StatusConflict}}
+    l = lookup()
+    l.find = find("1")
+    if _, err := l.adopt(ctx, conflict); !IsConflict(err) {
+        t.Errorf("adopt = %v, want the conflict", err)
+    }
+
+    // Nothing is looked up once the create was stopped.
+    stopped, cancel := context.WithCancel(context.Background())
+    cancel()
+    l = lookup("1")
+    l.find = func(context.Context) ([]string, error) {
+        t.Error("lookup after the create was stopped")
+        return nil, nil
+    }
+    if id, err := l.adopt(stopped, createErr); statusCode(err) != http.StatusBadGateway || id != "" {
+        t.Errorf("adopt = %q, %v, want the create error after an interrupt", id, err)
+    }
+
+    // Nor without retry_idempotent_creates.
+    l = newOrphanLookup(context.Background(), &PConfig{}, "apigw_network", "net", "platform",
+        func(context.Context) ([]string, error) {
+            t.Error("lookup without retry_idempotent_creates")
+            return nil, nil
+        })
+    if id, err := l.adopt(ctx, createErr); statusCode(err) != http.StatusBadGateway || id != "" {
+        t.Errorf("adopt = %q, %v, want the create error without retry_idempotent_creates", id, err)
+    }
+}
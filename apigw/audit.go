@@ -0,0 +1,190 @@
+package apigw
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "os"
+    "sync"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// auditLog appends a JSON line per mutating request to the file set by
+// audit_log_path. Requests of resources applied in parallel are written
+// one line at a time.
+type auditLog struct {
+    path	string
+
+    mu		sync.Mutex
+    file	*os.File
+}
+
+// auditEntry is a line of the audit log. Resource type and ID are those of
+// the Terraform resource which sent the request, if any: Terraform does not
+// tell providers the address of a resource.
+type auditEntry struct {
+    Time		time.Time	`json:"time"`
+    Method		string		`json:"method"`
+    Platform		string		`json:"platform"`
+    Path		string		`json:"path"`
+    ResourceType	string		`json:"resource_type,omitempty"`
+    ResourceID		string		`json:"resource_id,omitempty"`
+    Status		int		`json:"status,omitempty"`
+    Error		string		`json:"error,omitempty"`
+    Attempts		int		`json:"attempts"`
+    LatencyMS		int64		`json:"latency_ms"`
+    RequestBody		json.RawMessage	`json:"request_body,omitempty"`
+}
+
+var auditLogsMu sync.Mutex
+
+// auditLogs are the audit logs opened by this process, so that all providers
+// configured in the process write a file through the same lock.
+var auditLogs = make(map[string]*auditLog)
+
+// openAuditLog returns the audit log at path, which is created if missing
+// and appended to otherwise.
+func openAuditLog(path string) (*auditLog, error) {
+    auditLogsMu.Lock()
+    defer auditLogsMu.Unlock()
+
+    if l, ok := auditLogs[path]; ok {
+        return l, nil
+    }
+
+    file, err := os.OpenFile(path, os.O_WRONLY | os.O_APPEND | os.O_CREATE, 0600)
+    if err != nil {
+        return nil, fmt.Errorf("Unable to open 'audit_log_path' %s: %v", path, err)
+    }
+
+    l := &auditLog{path: path, file: file}
+    auditLogs[path] = l
+    return l, nil
+}
+
+// write appends entry as a single line. The log is best effort: the request
+// has been sent already, so a failure is only logged.
+func (l *auditLog) write(entry auditEntry) {
+    data, err := json.Marshal(entry)
+    if err != nil {
+        log.Printf("[ERROR] Unable to encode audit log entry of %s %s: %v", entry.Method, entry.Path, err)
+        return
+    }
+
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    if _, err := l.file.Write(append(data, '\n')); err != nil {
+        log.Printf("[ERROR] Unable to write audit log %s: %v", l.path, err)
+    }
+}
+
+// isMutatingMethod reports whether a request with the given method changes
+// objects on the gateway and is thus audited.
+func isMutatingMethod(method string) bool {
+    switch method {
+    case "POST", "PUT", "PATCH", "DELETE":
+        return true
+    }
+
+    return false
+}
+
+// auditResource is the Terraform resource on whose behalf requests are
+// sent, carried by the context of its operation.
+type auditResource struct {
+    Type	string
+    ID		string
+}
+
+type auditResourceKey struct{}
+
+func withAuditResource(ctx context.Context, r auditResource) context.Context {
+    return context.WithValue(ctx, auditResourceKey{}, r)
+}
+
+func auditResourceFrom(ctx context.Context) auditResource {
+    r, _ := ctx.Value(auditResourceKey{}).(auditResource)
+    return r
+}
+
+// responseStatus returns the response code of resp, or 0 if there is none.
+func responseStatus(resp *http.Response) int {
+    if resp == nil {
+        return 0
+    }
+    return resp.StatusCode
+}
+
+// audit writes a mutating request, which ended with status or err after
+// attempts attempts taking latency in total, to the audit log, if any.
+func (pc *ProviderClient) audit(
+        ctx context.Context,
+        platform string,
+        path string,
+        method string,
+        payload []byte,
+        status int,
+        attempts int,
+        latency time.Duration,
+        err error) {
+    if pc.auditLog == nil || !isMutatingMethod(method) {
+        return
+    }
+
+    resource := auditResourceFrom(ctx)
+    entry := auditEntry{
+        Time:		time.Now().UTC(),
+        Method:		method,
+        Platform:	platform,
+        Path:		path,
+        ResourceType:	resource.Type,
+        ResourceID:	resource.ID,
+        Status:		status,
+        Attempts:	attempts,
+        LatencyMS:	latency.Milliseconds(),
+    }
+    if status == 0 && err != nil {
+        entry.Error = err.Error()
+    }
+    if body := redactBody(payload); json.Valid(body) {
+        entry.RequestBody = body
+    }
+
+    pc.auditLog.write(entry)
+}
+
+// setAuditResourceTypes makes the CRUD operations of every resource record
+// its type, see PConfig.operationContext. Each operation gets a copy of the
+// provider configuration, which is otherwise shared by all resources.
+func setAuditResourceTypes(p *schema.Provider) {
+    for name, r := range p.ResourcesMap {
+        r.Create = auditResourceType(name, r.Create)
+        r.Read = auditResourceType(name, r.Read)
+        r.Update = auditResourceType(name, r.Update)
+        r.Delete = auditResourceType(name, r.Delete)
+    }
+}
+
+func auditResourceType(
+        name string,
+        f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+    if f == nil {
+        return nil
+    }
+
+    return func(d *schema.ResourceData, meta interface{}) error {
+        config, ok := meta.(*PConfig)
+        if !ok {
+            return f(d, meta)
+        }
+
+        resourceConfig := *config
+        resourceConfig.resourceType = name
+        return f(d, &resourceConfig)
+    }
+}
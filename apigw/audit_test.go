@@ -0,0 +1,141 @@
+package apigw
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/mock"
+)
+
+func readAuditLog(t *testing.T, path string) []auditEntry {
+    file, err := os.Open(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer file.Close()
+
+    var entries []auditEntry
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        var entry auditEntry
+        if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+            t.Fatalf("Unable to decode audit log line %q: %v", scanner.Text(), err)
+        }
+        entries = append(entries, entry)
+    }
+    if err := scanner.Err(); err != nil {
+        t.Fatal(err)
+    }
+    return entries
+}
+
+func TestAuditLog(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    path := filepath.Join(t.TempDir(), "audit.jsonl")
+    config := &Config{
+        APIGW_APIKEY:	"secret",
+        APIGW_URL:	server.URL + "/",
+        AuditLogPath:	path,
+    }
+    if err := config.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+
+    // Parallel requests are written as whole lines.
+    var wg sync.WaitGroup
+    for i := 0; i < 20; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            ctx := withAuditResource(context.Background(), auditResource{Type: "apigw_vpn", ID: fmt.Sprint(i)})
+            body := fmt.Sprintf(`{"name": "vpn-%d", "psk": "topsecret"}`, i)
+            config.APIGWClient.Request(ctx, "openstack-mock", "api/v4/openstack-mock/vpn_services/", "POST", []byte(body), nil)
+        }(i)
+    }
+    wg.Wait()
+
+    if _, err := config.APIGWClient.Request(context.Background(), "openstack-mock",
+            "api/v4/openstack-mock/vpn_services/", "GET", nil, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if strings.Contains(string(data), "topsecret") {
+        t.Errorf("audit log contains the psk:\n%s", data)
+    }
+
+    entries := readAuditLog(t, path)
+    if len(entries) != 20 {
+        t.Fatalf("audit log has %d entries, want 20 without the GET", len(entries))
+    }
+    for _, entry := range entries {
+        if entry.Method != "POST" || entry.Platform != "openstack-mock" || entry.ResourceType != "apigw_vpn" ||
+                entry.ResourceID == "" || entry.Attempts != 1 || entry.Time.IsZero() {
+            t.Errorf("unexpected audit log entry %+v", entry)
+        }
+        if entry.Status == 0 && entry.Error == "" {
+            t.Errorf("audit log entry %+v has neither status nor error", entry)
+        }
+    }
+}
+
+func TestAuditLogResourceType(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    path := filepath.Join(t.TempDir(), "audit.jsonl")
+    config := &PConfig{
+        Config: Config{
+            APIGW_APIKEY:	"secret",
+            APIGW_URL:		server.URL + "/",
+            AuditLogPath:	path,
+        },
+    }
+    if err := config.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+
+    resource := Provider().(*schema.Provider).ResourcesMap["apigw_network"]
+    d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+        "cidr":		"10.0.0.0/24",
+        "gateway":	"10.0.0.1",
+        "name":		"net",
+        "platform":	"openstack-mock",
+        "project":	mock.DefaultProject,
+    })
+    if err := resource.Create(d, config); err != nil {
+        t.Fatal(err)
+    }
+    id := d.Id()
+    if err := resource.Delete(d, config); err != nil {
+        t.Fatal(err)
+    }
+
+    entries := readAuditLog(t, path)
+    if len(entries) != 2 {
+        t.Fatalf("audit log has %d entries, want 2", len(entries))
+    }
+    create, remove := entries[0], entries[1]
+    if create.Method != "POST" || create.ResourceType != "apigw_network" || create.Status != 201 {
+        t.Errorf("unexpected create entry %+v", create)
+    }
+    if remove.Method != "DELETE" || remove.ResourceType != "apigw_network" || remove.ResourceID != id {
+        t.Errorf("unexpected delete entry %+v", remove)
+    }
+    if config.resourceType != "" {
+        t.Errorf("resource type %q leaked into the provider configuration", config.resourceType)
+    }
+}
@@ -1,23 +1,15 @@
 package apigw
 
 import (
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
 )
 
-func policyStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        } else {
-            return response, "DELETING", nil
-        }
-    }
+// policyWaiter waits for the auto scaling policy policyID on platform, which has no status and is
+// ACTIVE until it is gone.
+func policyWaiter(ctx context.Context, config *PConfig, platform string, policyID string) *waiter {
+    return newWaiter(ctx, config, "auto scaling policy", policyID,
+        func() (interface{}, error) {
+            return config.API.AutoScalingPolicies.Get(ctx, platform, policyID)
+        },
+        existsStatus)
 }
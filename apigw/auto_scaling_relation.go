@@ -1,62 +1,22 @@
 package apigw
 
 import (
-    "encoding/json"
-
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func relationStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        relationData := data["auto_scaling_policy"]
-        if relationData != nil {
-            relationDict := relationData.(map[string]interface{})
-            status := relationDict["status"].(string)
-            return relationDict, status, nil
-        } else {
-            return data, "ASSOCIATING", nil
-        }
-    }
-}
-
-func relationStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        relationData := data["auto_scaling_policy"]
-        if relationData == nil {
-            return data, "DELETED", nil
-        } else {
-            return relationData, "DISASSOCIATING", nil
-        }
-    }
+// relationWaiter waits for the auto scaling relation of the server serverID
+// on platform. The relation is DISASSOCIATED while it does not exist.
+func relationWaiter(ctx context.Context, config *PConfig, platform string, serverID string) *waiter {
+    return newWaiter(ctx, config, "auto scaling relation", serverID,
+        func() (interface{}, error) {
+            return config.API.Servers.Get(ctx, platform, serverID)
+        },
+        func(v interface{}) (string, string) {
+            relation := v.(*client.Server).AutoScalingPolicy
+            if relation == nil {
+                return "DISASSOCIATED", ""
+            }
+            return relation.Status, relation.StatusReason
+        })
 }
@@ -0,0 +1,220 @@
+package apigw
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "net/http"
+    "strings"
+    "sync"
+)
+
+// cassette is a file of the interactions with the gateway, recorded with
+// APIGW_RECORD and replayed with APIGW_REPLAY. Secrets are redacted the same
+// way as in the debug log.
+type cassette struct {
+    path		string
+    Interactions	[]*interaction	`json:"interactions"`
+
+    mu			sync.Mutex
+}
+
+// interaction is a request sent to the gateway and its response. URL is
+// relative to the APIGW_URL of the provider, so that a cassette can be
+// replayed against any URL.
+type interaction struct {
+    Method		string		`json:"method"`
+    URL			string		`json:"url"`
+    Host		string		`json:"host"`
+    RequestHeader	http.Header	`json:"request_header"`
+    RequestBody		string		`json:"request_body"`
+    Status		int		`json:"status"`
+    ResponseHeader	http.Header	`json:"response_header"`
+    ResponseBody	string		`json:"response_body"`
+
+    replayed		bool
+}
+
+type cassetteKey struct {
+    path	string
+    replay	bool
+}
+
+var cassettesMu sync.Mutex
+
+// cassettes are the cassettes opened by this process. Every provider
+// configured in the process, e.g. by each acceptance test, shares the
+// cassette of its path.
+var cassettes = make(map[cassetteKey]*cassette)
+
+// openCassette returns the cassette at path, loading it to be replayed or
+// starting it empty to be recorded.
+func openCassette(path string, replay bool) (*cassette, error) {
+    cassettesMu.Lock()
+    defer cassettesMu.Unlock()
+
+    key := cassetteKey{path, replay}
+    if c, ok := cassettes[key]; ok {
+        return c, nil
+    }
+
+    c := &cassette{path: path, Interactions: []*interaction{}}
+    if replay {
+        data, err := ioutil.ReadFile(path)
+        if err != nil {
+            return nil, fmt.Errorf("Unable to read cassette %s: %v", path, err)
+        }
+        if err := json.Unmarshal(data, c); err != nil {
+            return nil, fmt.Errorf("Unable to decode cassette %s: %v", path, err)
+        }
+    } else if err := c.save(); err != nil {
+        return nil, err
+    }
+
+    cassettes[key] = c
+    return c, nil
+}
+
+// record appends i to the cassette. The file is written after every
+// interaction, so that it is complete even if terraform is interrupted.
+func (c *cassette) record(i *interaction) error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.Interactions = append(c.Interactions, i)
+    return c.save()
+}
+
+func (c *cassette) save() error {
+    data, err := json.MarshalIndent(c, "", "  ")
+    if err != nil {
+        return fmt.Errorf("Unable to encode cassette %s: %v", c.path, err)
+    }
+    if err := ioutil.WriteFile(c.path, data, 0600); err != nil {
+        return fmt.Errorf("Unable to write cassette %s: %v", c.path, err)
+    }
+    return nil
+}
+
+// replay returns the first interaction not replayed yet which matches the
+// method, URL, platform and body of i. Repeated requests, such as polls, are
+// thus answered in the order they were recorded.
+func (c *cassette) replay(i *interaction) (*interaction, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    for _, recorded := range c.Interactions {
+        if !recorded.replayed && recorded.Method == i.Method && recorded.URL == i.URL &&
+                recorded.Host == i.Host && recorded.RequestBody == i.RequestBody {
+            recorded.replayed = true
+            return recorded, nil
+        }
+    }
+    return nil, fmt.Errorf("No interaction of cassette %s left for %s %s on %s",
+        c.path, i.Method, i.URL, i.Host)
+}
+
+// newInteraction records the request req to the gateway at baseURL.
+func newInteraction(req *http.Request, baseURL string) (*interaction, error) {
+    var body []byte
+    if req.GetBody != nil {
+        reader, err := req.GetBody()
+        if err != nil {
+            return nil, err
+        }
+        defer reader.Close()
+        if body, err = ioutil.ReadAll(reader); err != nil {
+            return nil, err
+        }
+    }
+
+    return &interaction{
+        Method:		req.Method,
+        URL:		strings.TrimPrefix(req.URL.String(), baseURL),
+        Host:		req.Header.Get("x-api-host"),
+        RequestHeader:	redactHeaders(req.Header),
+        RequestBody:	string(redactBody(body)),
+    }, nil
+}
+
+// recordingTransport sends requests with next and records them to a
+// cassette.
+type recordingTransport struct {
+    next	http.RoundTripper
+    cassette	*cassette
+    baseURL	string
+}
+
+func newRecordingTransport(next http.RoundTripper, path string, baseURL string) (*recordingTransport, error) {
+    c, err := openCassette(path, false)
+    if err != nil {
+        return nil, err
+    }
+    return &recordingTransport{next: next, cassette: c, baseURL: baseURL}, nil
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+    i, err := newInteraction(req, t.baseURL)
+    if err != nil {
+        return nil, err
+    }
+
+    resp, err := t.next.RoundTrip(req)
+    if err != nil {
+        return nil, err
+    }
+
+    body, err := ioutil.ReadAll(resp.Body)
+    resp.Body.Close()
+    if err != nil {
+        return nil, err
+    }
+    resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+    i.Status = resp.StatusCode
+    i.ResponseHeader = redactHeaders(resp.Header)
+    i.ResponseBody = string(redactBody(body))
+    if err := t.cassette.record(i); err != nil {
+        return nil, err
+    }
+    return resp, nil
+}
+
+// replayingTransport answers requests from a cassette, without network.
+type replayingTransport struct {
+    cassette	*cassette
+    baseURL	string
+}
+
+func newReplayingTransport(path string, baseURL string) (*replayingTransport, error) {
+    c, err := openCassette(path, true)
+    if err != nil {
+        return nil, err
+    }
+    return &replayingTransport{cassette: c, baseURL: baseURL}, nil
+}
+
+func (t *replayingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+    i, err := newInteraction(req, t.baseURL)
+    if err != nil {
+        return nil, err
+    }
+
+    recorded, err := t.cassette.replay(i)
+    if err != nil {
+        return nil, err
+    }
+
+    return &http.Response{
+        Status:		fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+        StatusCode:	recorded.Status,
+        Proto:		"HTTP/1.1",
+        ProtoMajor:	1,
+        ProtoMinor:	1,
+        Header:		recorded.ResponseHeader.Clone(),
+        Body:		ioutil.NopCloser(strings.NewReader(recorded.ResponseBody)),
+        ContentLength:	int64(len(recorded.ResponseBody)),
+        Request:	req,
+    }, nil
+}
@@ -0,0 +1,98 @@
+package apigw
+
+import (
+    "context"
+    "io/ioutil"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+    "apigw_plugin/terraform-provider-apigw/apigw/mock"
+)
+
+func newCassetteConfig(t *testing.T, url, record, replay string) *Config {
+    config := &Config{
+        APIGW_APIKEY:	"secret",
+        APIGW_URL:	url,
+        RecordFile:	record,
+        ReplayFile:	replay,
+    }
+    if err := config.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+    return config
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+    ctx := context.Background()
+    path := filepath.Join(t.TempDir(), "cassette.json")
+    server := mock.NewServer("secret")
+    opts := client.NetworkCreateOpts{
+        Name:		"net",
+        CIDR:		"10.0.0.0/24",
+        Gateway:	"10.0.0.1",
+        Project:	mock.DefaultProject,
+    }
+
+    api := newCassetteConfig(t, server.URL + "/", path, "").API
+    created, err := api.Networks.Create(ctx, "openstack-mock", opts)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var recorded []string
+    for i := 0; i < 2; i++ {
+        network, err := api.Networks.Get(ctx, "openstack-mock", created.ID.String())
+        if err != nil {
+            t.Fatal(err)
+        }
+        recorded = append(recorded, network.Status)
+    }
+    server.Close()
+
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if strings.Contains(string(data), "secret") {
+        t.Errorf("cassette contains the API key:\n%s", data)
+    }
+
+    api = newCassetteConfig(t, "http://replay.invalid/", "", path).API
+    replayed, err := api.Networks.Create(ctx, "openstack-mock", opts)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if replayed.ID != created.ID {
+        t.Errorf("replayed network %s, want %s", replayed.ID, created.ID)
+    }
+    for i, status := range recorded {
+        network, err := api.Networks.Get(ctx, "openstack-mock", created.ID.String())
+        if err != nil {
+            t.Fatal(err)
+        }
+        if network.Status != status {
+            t.Errorf("replayed status %d = %s, want %s", i, network.Status, status)
+        }
+    }
+
+    if _, err := api.Networks.Get(ctx, "openstack-mock", created.ID.String()); err == nil {
+        t.Error("expected an error once the cassette is used up")
+    }
+    opts.Name = "other"
+    if _, err := api.Networks.Create(ctx, "openstack-mock", opts); err == nil {
+        t.Error("expected an error for a request which was not recorded")
+    }
+}
+
+func TestCassetteRecordAndReplayExclusive(t *testing.T) {
+    config := &Config{
+        APIGW_APIKEY:	"secret",
+        APIGW_URL:	"http://replay.invalid/",
+        RecordFile:	"record.json",
+        ReplayFile:	"replay.json",
+    }
+    if err := config.LoadAndValidate(); err == nil {
+        t.Fatal("expected an error")
+    }
+}
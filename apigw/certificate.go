@@ -0,0 +1,184 @@
+package apigw
+
+import (
+    "bytes"
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "encoding/pem"
+    "fmt"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// certificateExpiryWarning is how long before its expiry a certificate is
+// warned about when planning.
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// terminatedHTTPS is the protocol of loadbalancers and listeners which
+// terminate TLS with a certificate.
+const terminatedHTTPS = "TERMINATED_HTTPS"
+
+// parseCertificates returns the certificates of the PEM blocks in data, which
+// must all be certificates.
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+    var certs []*x509.Certificate
+    rest := []byte(data)
+    for {
+        var block *pem.Block
+        block, rest = pem.Decode(rest)
+        if block == nil {
+            break
+        }
+        if block.Type != "CERTIFICATE" {
+            return nil, fmt.Errorf("Unexpected PEM block %s, want CERTIFICATE", block.Type)
+        }
+        cert, err := x509.ParseCertificate(block.Bytes)
+        if err != nil {
+            return nil, err
+        }
+        certs = append(certs, cert)
+    }
+
+    if len(certs) == 0 {
+        return nil, fmt.Errorf("No PEM encoded certificate found")
+    }
+    return certs, nil
+}
+
+// validateCertificate is the ValidateFunc of a PEM certificate, which warns
+// about a certificate expiring within certificateExpiryWarning.
+func validateCertificate(v interface{}, k string) ([]string, []error) {
+    certs, err := parseCertificates(v.(string))
+    if err != nil {
+        return nil, []error{fmt.Errorf("%s is not a valid certificate: %v", k, err)}
+    }
+    if len(certs) > 1 {
+        return nil, []error{fmt.Errorf("%s holds %d certificates, put the intermediate ones into chain", k, len(certs))}
+    }
+
+    if warning := certificateExpiry(certs[0], time.Now()); warning != "" {
+        return []string{fmt.Sprintf("%s %s", k, warning)}, nil
+    }
+    return nil, nil
+}
+
+// validateCertificateChain is the ValidateFunc of a chain of PEM
+// certificates.
+func validateCertificateChain(v interface{}, k string) ([]string, []error) {
+    if v.(string) == "" {
+        return nil, nil
+    }
+
+    certs, err := parseCertificates(v.(string))
+    if err != nil {
+        return nil, []error{fmt.Errorf("%s is not a valid certificate chain: %v", k, err)}
+    }
+
+    var warnings []string
+    for _, cert := range certs {
+        if warning := certificateExpiry(cert, time.Now()); warning != "" {
+            warnings = append(warnings, fmt.Sprintf("%s certificate %s %s", k, cert.Subject, warning))
+        }
+    }
+    return warnings, nil
+}
+
+// certificateExpiry returns a warning if cert has expired at now or expires
+// within certificateExpiryWarning, or an empty string.
+func certificateExpiry(cert *x509.Certificate, now time.Time) string {
+    notAfter := cert.NotAfter.UTC().Format(time.RFC3339)
+    switch left := cert.NotAfter.Sub(now); {
+    case left <= 0:
+        return fmt.Sprintf("expired on %s", notAfter)
+    case left < certificateExpiryWarning:
+        return fmt.Sprintf("expires on %s, in %d days", notAfter, int(left.Hours() / 24))
+    }
+    return ""
+}
+
+// checkCertificateKeyPair returns an error if the PEM private key does not
+// belong to the PEM certificate.
+func checkCertificateKeyPair(certificate, privateKey string) error {
+    if _, err := tls.X509KeyPair([]byte(certificate), []byte(privateKey)); err != nil {
+        return fmt.Errorf("private_key does not match certificate: %v", err)
+    }
+    return nil
+}
+
+// pemBlocks returns the PEM blocks of data, or nil if data holds anything
+// else than PEM blocks and whitespace.
+func pemBlocks(data string) []*pem.Block {
+    var blocks []*pem.Block
+    rest := []byte(data)
+    for {
+        var block *pem.Block
+        block, rest = pem.Decode(rest)
+        if block == nil {
+            break
+        }
+        blocks = append(blocks, block)
+    }
+
+    if len(bytes.TrimSpace(rest)) > 0 {
+        return nil
+    }
+    return blocks
+}
+
+// suppressEquivalentPEM suppresses the diff of PEM encoded certificates
+// which only differ in their encoding, e.g. in line endings or line
+// lengths, as the gateway may return them reformatted.
+func suppressEquivalentPEM(k, old, new string, d *schema.ResourceData) bool {
+    oldBlocks, newBlocks := pemBlocks(old), pemBlocks(new)
+    if len(oldBlocks) == 0 || len(oldBlocks) != len(newBlocks) {
+        return false
+    }
+
+    for i, block := range oldBlocks {
+        if block.Type != newBlocks[i].Type || !bytes.Equal(block.Bytes, newBlocks[i].Bytes) {
+            return false
+        }
+    }
+    return true
+}
+
+// certificateCustomizeDiff checks when planning that the private key of a
+// certificate matches it. Values which are not known yet are checked by the
+// gateway.
+func certificateCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    if !d.NewValueKnown("certificate") || !d.NewValueKnown("private_key") {
+        return nil
+    }
+
+    return checkCertificateKeyPair(d.Get("certificate").(string), d.Get("private_key").(string))
+}
+
+// lbCertificateCustomizeDiff checks when planning that a loadbalancer or a
+// listener has a certificate if and only if it terminates HTTPS.
+func lbCertificateCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    if !d.NewValueKnown("protocol") || !d.NewValueKnown("certificate") {
+        return nil
+    }
+
+    protocol := d.Get("protocol").(string)
+    certificate := d.Get("certificate").(string)
+    if protocol == terminatedHTTPS && certificate == "" {
+        return fmt.Errorf("certificate is required with protocol %s", terminatedHTTPS)
+    }
+    if protocol != terminatedHTTPS && certificate != "" {
+        return fmt.Errorf("certificate is only supported with protocol %s, not %s", terminatedHTTPS, protocol)
+    }
+    return nil
+}
+
+// certificateWaiter waits for the certificate certID on platform, which has
+// no status and is ACTIVE until it is gone.
+func certificateWaiter(ctx context.Context, config *PConfig, platform string, certID string) *waiter {
+    return newWaiter(ctx, config, "certificate", certID,
+        func() (interface{}, error) {
+            return config.API.Certificates.Get(ctx, platform, certID)
+        },
+        existsStatus)
+}
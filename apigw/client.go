@@ -2,53 +2,145 @@ package apigw
 
 import (
     "bytes"
+    "context"
     "crypto/tls"
     "io/ioutil"
+    "log"
     "net/http"
-    "strings"
+    "time"
 )
 
 type ProviderClient struct {
-    HTTPClient	http.Client
-    Key		string
-    Url 	string
+    HTTPClient		http.Client
+    Key			string
+    Url 		string
+
+    // MaxRetries is the number of times a failed request is retried, see
+    // shouldRetry for the failures which qualify.
+    MaxRetries		int
+    RetryWaitMin	time.Duration
+    RetryWaitMax	time.Duration
+
+    // RetryIdempotentCreates retries POST requests carrying an
+    // Idempotency-Key like PUT requests. Only safe if the gateway
+    // deduplicates them on that header.
+    RetryIdempotentCreates	bool
+
+    // RequestTimeout limits each attempt of a request, 0 means it is only
+    // limited by the context of the request.
+    RequestTimeout	time.Duration
+
+    // UserAgent is sent as User-Agent header of every request.
+    UserAgent		string
+
+    // auditLog, if set, records every mutating request.
+    auditLog		*auditLog
+
+    // Debug enables logging of every request and response, with secrets
+    // redacted, at the DEBUG level.
+    Debug		bool
+
+    limiter		*requestLimiter
 }
 
-func newProviderClient(key string, url string) *ProviderClient {
+func newProviderClient(key string, url string, tlsConfig *tls.Config) *ProviderClient {
     pc := ProviderClient{}
     pc.Key = key
     pc.Url = url
-    is_https := strings.Contains(url, "https")
 
-    if is_https {
-        tr := &http.Transport{
-            TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-        }
-        client := http.Client{Transport: tr}
-        pc.HTTPClient = client
-    } else{
-        client := http.Client{}
-        pc.HTTPClient = client
-    }
+    tr := http.DefaultTransport.(*http.Transport).Clone()
+    tr.TLSClientConfig = tlsConfig
+    pc.HTTPClient = http.Client{Transport: tr}
 
     return &pc
 }
 
 func (pc *ProviderClient) doRequest(
+        ctx context.Context,
         resourceHost string,
         resourcePath string,
         method string,
         body *bytes.Buffer,
         headers map[string]string) (string, error) {
+    var payload []byte
+    if body != nil {
+        payload = body.Bytes()
+    }
+
+    idempotent := pc.isIdempotentRequest(method, headers)
+    start := time.Now()
+    for attempt := 0; ; attempt++ {
+        response, resp, sent, err := pc.doRequestOnce(
+            ctx, resourceHost, resourcePath, method, payload, headers)
+        if attempt >= pc.MaxRetries || ctx.Err() != nil || !shouldRetry(idempotent, resp, sent, err) {
+            pc.audit(ctx, resourceHost, resourcePath, method, payload,
+                responseStatus(resp), attempt + 1, time.Since(start), err)
+            return response, err
+        }
+
+        wait := pc.retryWait(attempt, resp)
+        log.Printf("[DEBUG] Retrying %s %s in %s (%d/%d): %v",
+            method, resourcePath, wait, attempt + 1, pc.MaxRetries, err)
+        timer := time.NewTimer(wait)
+        select {
+        case <-timer.C:
+        case <-ctx.Done():
+            timer.Stop()
+            pc.audit(ctx, resourceHost, resourcePath, method, payload,
+                responseStatus(resp), attempt + 1, time.Since(start), err)
+            return response, err
+        }
+    }
+}
+
+// Request sends a request to the gateway on behalf of the typed API client,
+// see client.Requester.
+func (pc *ProviderClient) Request(
+        ctx context.Context,
+        platform string,
+        path string,
+        method string,
+        body []byte,
+        headers map[string]string) ([]byte, error) {
+    var buf *bytes.Buffer
+    if body != nil {
+        buf = bytes.NewBuffer(body)
+    }
+
+    response, err := pc.doRequest(ctx, platform, path, method, buf, headers)
+    if err != nil {
+        return nil, err
+    }
+
+    return []byte(response), nil
+}
+
+// doRequestOnce performs a single attempt of a request. Besides the response
+// body and error it returns the raw response, if any, and whether the request
+// reached the gateway so that the caller can decide whether to retry.
+func (pc *ProviderClient) doRequestOnce(
+        ctx context.Context,
+        resourceHost string,
+        resourcePath string,
+        method string,
+        payload []byte,
+        headers map[string]string) (string, *http.Response, bool, error) {
     url :=  pc.Url + resourcePath
     var req *http.Request
     var err error
 
-    if body != nil {
-        req, err = http.NewRequest(method, url, body)
-        req.Header.Set("Content-Type", "application/json")
+    if payload != nil {
+        req, err = http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
     } else {
-        req, err = http.NewRequest(method, url, nil)
+        req, err = http.NewRequestWithContext(ctx, method, url, nil)
+    }
+
+    if err != nil {
+        return "Initial HTTP request failed", nil, false, err
+    }
+
+    if payload != nil {
+        req.Header.Set("Content-Type", "application/json")
     }
 
     if headers != nil {
@@ -57,15 +149,30 @@ func (pc *ProviderClient) doRequest(
         }
     }
 
+    if pc.UserAgent != "" {
+        req.Header.Set("User-Agent", pc.UserAgent)
+    }
     req.Header.Set("x-api-host", resourceHost)
     req.Header.Set("x-api-key", pc.Key)
+
+    release, err := pc.limiter.acquire(ctx, resourceHost)
     if err != nil {
-        return "Initial HTTP request failed", err
+        return "Waiting to send request failed", nil, false, err
+    }
+    defer release()
+
+    if pc.RequestTimeout > 0 {
+        attemptCtx, cancel := context.WithTimeout(ctx, pc.RequestTimeout)
+        defer cancel()
+        req = req.WithContext(attemptCtx)
     }
 
+    pc.logRequest(req, payload)
+    start := time.Now()
     resp, err := pc.HTTPClient.Do(req)
     if err != nil {
-        return "Sending request failed", err
+        pc.logResponse(req, nil, nil, time.Since(start), err)
+        return "Sending request failed", nil, !requestNotSent(err), err
     }
 
     okc := defaultOkCodes(method)
@@ -79,9 +186,10 @@ func (pc *ProviderClient) doRequest(
 
     bodyBytes, err := ioutil.ReadAll(resp.Body)
     resp.Body.Close()
+    pc.logResponse(req, resp, bodyBytes, time.Since(start), err)
 
     if err != nil {
-        return "Read response body failed", err
+        return "Read response body failed", resp, true, err
     }
 
     if !ok {
@@ -93,42 +201,62 @@ func (pc *ProviderClient) doRequest(
             Body:           bodyBytes,
             ResponseHeader: resp.Header,
         }
+        if len(bodyBytes) > 0 {
+            respErr.APIError = parseAPIError(bodyBytes, resp.Header)
+        }
+
+        translator := errorTranslators[requestCollection(resourcePath)]
         switch resp.StatusCode {
         case http.StatusBadRequest:
             err = ErrDefault400{respErr}
-            if error400er, ok := err.(Err400er); ok {
+            if error400er, ok := translator.(Err400er); ok {
                 err = error400er.Error400(respErr)
             }
         case http.StatusUnauthorized:
             err = ErrDefault401{respErr}
-            if error401er, ok := err.(Err401er); ok {
+            if error401er, ok := translator.(Err401er); ok {
                 err = error401er.Error401(respErr)
             }
         case http.StatusForbidden:
             err = ErrDefault403{respErr}
-            if error403er, ok := err.(Err403er); ok {
+            if error403er, ok := translator.(Err403er); ok {
                 err = error403er.Error403(respErr)
             }
         case http.StatusNotFound:
             err = ErrDefault404{respErr}
-            if error404er, ok := err.(Err404er); ok {
+            if error404er, ok := translator.(Err404er); ok {
                 err = error404er.Error404(respErr)
             }
         case http.StatusConflict:
             err = ErrDefault409{respErr}
-            if error409er, ok := err.(Err409er); ok {
+            if error409er, ok := translator.(Err409er); ok {
                 err = error409er.Error409(respErr)
             }
+        case http.StatusTooManyRequests:
+            err = ErrDefault429{respErr}
+            if error429er, ok := translator.(Err429er); ok {
+                err = error429er.Error429(respErr)
+            }
         case http.StatusInternalServerError:
             err = ErrDefault500{respErr}
-            if error500er, ok := err.(Err500er); ok {
+            if error500er, ok := translator.(Err500er); ok {
                 err = error500er.Error500(respErr)
             }
+        case http.StatusBadGateway:
+            err = ErrDefault502{respErr}
+            if error502er, ok := translator.(Err502er); ok {
+                err = error502er.Error502(respErr)
+            }
         case http.StatusServiceUnavailable:
             err = ErrDefault503{respErr}
-            if error503er, ok := err.(Err503er); ok {
+            if error503er, ok := translator.(Err503er); ok {
                 err = error503er.Error503(respErr)
             }
+        case http.StatusGatewayTimeout:
+            err = ErrDefault504{respErr}
+            if error504er, ok := translator.(Err504er); ok {
+                err = error504er.Error504(respErr)
+            }
         }
 
         if err == nil {
@@ -136,7 +264,7 @@ func (pc *ProviderClient) doRequest(
         }
     }
 
-    return string(bodyBytes), err
+    return string(bodyBytes), resp, true, err
 }
 
 func defaultOkCodes(method string) []int {
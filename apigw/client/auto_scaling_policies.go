@@ -0,0 +1,72 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type AutoScalingPolicy struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    Description		string		`json:"description"`
+    MeterName		string		`json:"meter_name"`
+    ScaleMaxSize	int		`json:"scale_max_size"`
+    ScaledownThreshold	int		`json:"scaledown_threshold"`
+    ScaleupThreshold	int		`json:"scaleup_threshold"`
+    User		StringMap	`json:"user"`
+}
+
+type AutoScalingPolicyListOpts struct {
+    Name	string
+    Project	string
+}
+
+type AutoScalingPolicyCreateOpts struct {
+    Description		string	`json:"description,omitempty"`
+    MeterName		string	`json:"meter_name"`
+    Name		string	`json:"name"`
+    Project		string	`json:"project"`
+    ScaledownThreshold	int	`json:"scaledown_threshold"`
+    ScaleMaxSize	int	`json:"scale_max_size"`
+    ScaleupThreshold	int	`json:"scaleup_threshold"`
+}
+
+// AutoScalingPoliciesService manages auto scaling policies.
+type AutoScalingPoliciesService struct {
+    client	*Client
+}
+
+func autoScalingPoliciesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/auto_scaling_policies/", platform)
+}
+
+func autoScalingPolicyPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/auto_scaling_policies/%s/", platform, id)
+}
+
+func (s *AutoScalingPoliciesService) List(ctx context.Context, platform string, opts AutoScalingPolicyListOpts) ([]AutoScalingPolicy, error) {
+    var policies []AutoScalingPolicy
+    path := autoScalingPoliciesPath(platform) + query("project", opts.Project, "name", opts.Name)
+    err := s.client.get(ctx, platform, path, &policies)
+    return policies, err
+}
+
+func (s *AutoScalingPoliciesService) Get(ctx context.Context, platform, id string) (*AutoScalingPolicy, error) {
+    var policy AutoScalingPolicy
+    if err := s.client.get(ctx, platform, autoScalingPolicyPath(platform, id), &policy); err != nil {
+        return nil, err
+    }
+    return &policy, nil
+}
+
+func (s *AutoScalingPoliciesService) Create(ctx context.Context, platform string, opts AutoScalingPolicyCreateOpts) (*AutoScalingPolicy, error) {
+    var policy AutoScalingPolicy
+    if err := s.client.do(ctx, platform, autoScalingPoliciesPath(platform), "POST", opts, &policy, nil); err != nil {
+        return nil, err
+    }
+    return &policy, nil
+}
+
+func (s *AutoScalingPoliciesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, autoScalingPolicyPath(platform, id))
+}
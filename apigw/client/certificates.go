@@ -0,0 +1,73 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+// Certificate is a TLS certificate for load balancers and listeners
+// terminating HTTPS. The gateway never returns the private key.
+type Certificate struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    Certificate		string		`json:"certificate"`
+    Chain		string		`json:"chain"`
+    CreateTime		string		`json:"create_time"`
+    Expiration		string		`json:"expiration"`
+    Project		ID		`json:"project"`
+    User		StringMap	`json:"user"`
+}
+
+type CertificateListOpts struct {
+    Name	string
+    Project	string
+}
+
+type CertificateCreateOpts struct {
+    Certificate	string	`json:"certificate"`
+    Chain	string	`json:"chain,omitempty"`
+    Name	string	`json:"name"`
+    PrivateKey	string	`json:"private_key"`
+    Project	string	`json:"project"`
+}
+
+// CertificatesService manages TLS certificates. Certificates cannot be
+// changed, only replaced.
+type CertificatesService struct {
+    client	*Client
+}
+
+func certificatesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/certificates/", platform)
+}
+
+func certificatePath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/certificates/%s/", platform, id)
+}
+
+func (s *CertificatesService) List(ctx context.Context, platform string, opts CertificateListOpts) ([]Certificate, error) {
+    var certs []Certificate
+    path := certificatesPath(platform) + query("name", opts.Name, "project", opts.Project)
+    err := s.client.get(ctx, platform, path, &certs)
+    return certs, err
+}
+
+func (s *CertificatesService) Get(ctx context.Context, platform, id string) (*Certificate, error) {
+    var cert Certificate
+    if err := s.client.get(ctx, platform, certificatePath(platform, id), &cert); err != nil {
+        return nil, err
+    }
+    return &cert, nil
+}
+
+func (s *CertificatesService) Create(ctx context.Context, platform string, opts CertificateCreateOpts) (*Certificate, error) {
+    var cert Certificate
+    if err := s.client.do(ctx, platform, certificatesPath(platform), "POST", opts, &cert, nil); err != nil {
+        return nil, err
+    }
+    return &cert, nil
+}
+
+func (s *CertificatesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, certificatePath(platform, id))
+}
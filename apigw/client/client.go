@@ -0,0 +1,160 @@
+// Package client is a typed client for the APIGW v4 API.
+//
+// The client does not talk HTTP itself, it sends every request through a
+// Requester. The terraform provider uses its ProviderClient, which takes care
+// of authentication, TLS, retries and rate limiting:
+//
+//    config := apigw.Config{APIGW_APIKEY: key, APIGW_URL: url}
+//    if err := config.LoadAndValidate(); err != nil {
+//        return err
+//    }
+//    site, err := config.API.Sites.Get(ctx, "openstack-taichung-default-2", "1234")
+package client
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/json"
+    "fmt"
+    "net/url"
+)
+
+// Requester sends a request to the gateway. platform is sent as the
+// x-api-host header and path is relative to the gateway URL. It returns the
+// response body, or an error if the request failed, was cancelled by ctx or
+// the gateway answered with an unexpected response code.
+type Requester interface {
+    Request(
+        ctx context.Context,
+        platform, path, method string,
+        body []byte,
+        headers map[string]string) ([]byte, error)
+}
+
+// Client gives access to the APIGW services.
+type Client struct {
+    requester	Requester
+
+    AutoScalingPolicies	*AutoScalingPoliciesService
+    Certificates	*CertificatesService
+    FirewallRules	*FirewallRulesService
+    Firewalls		*FirewallsService
+    IKEPolicies		*IKEPoliciesService
+    IPSecPolicies	*IPSecPoliciesService
+    Images		*ImagesService
+    Keys		*KeysService
+    Listeners		*ListenersService
+    LoadBalancers	*LoadBalancersService
+    Networks		*NetworksService
+    Projects		*ProjectsService
+    SecurityGroups	*SecurityGroupsService
+    Servers		*ServersService
+    Sites		*SitesService
+    Snapshots		*SnapshotsService
+    Solutions		*SolutionsService
+    Volumes		*VolumesService
+    VPNServices		*VPNServicesService
+}
+
+// New returns a Client sending its requests through r.
+func New(r Requester) *Client {
+    c := &Client{requester: r}
+    c.AutoScalingPolicies = &AutoScalingPoliciesService{client: c}
+    c.Certificates = &CertificatesService{client: c}
+    c.FirewallRules = &FirewallRulesService{client: c}
+    c.Firewalls = &FirewallsService{client: c}
+    c.IKEPolicies = &IKEPoliciesService{client: c}
+    c.IPSecPolicies = &IPSecPoliciesService{client: c}
+    c.Images = &ImagesService{client: c}
+    c.Keys = &KeysService{client: c}
+    c.Listeners = &ListenersService{client: c}
+    c.LoadBalancers = &LoadBalancersService{client: c}
+    c.Networks = &NetworksService{client: c}
+    c.Projects = &ProjectsService{client: c}
+    c.SecurityGroups = &SecurityGroupsService{client: c}
+    c.Servers = &ServersService{client: c}
+    c.Sites = &SitesService{client: c}
+    c.Snapshots = &SnapshotsService{client: c}
+    c.Solutions = &SolutionsService{client: c}
+    c.Volumes = &VolumesService{client: c}
+    c.VPNServices = &VPNServicesService{client: c}
+    return c
+}
+
+// do sends a request with in encoded as JSON body, if not nil, and decodes
+// the response body into out, if not nil.
+func (c *Client) do(
+        ctx context.Context,
+        platform string,
+        path string,
+        method string,
+        in interface{},
+        out interface{},
+        headers map[string]string) error {
+    var body []byte
+    if in != nil {
+        var err error
+        body, err = json.Marshal(in)
+        if err != nil {
+            return fmt.Errorf("Unable to encode request body of %s %s: %v", method, path, err)
+        }
+    }
+
+    response, err := c.requester.Request(ctx, platform, path, method, body, headers)
+    if err != nil {
+        return err
+    }
+
+    if out != nil {
+        if err := json.Unmarshal(response, out); err != nil {
+            return fmt.Errorf("Unable to decode response of %s %s: %v", method, path, err)
+        }
+    }
+
+    return nil
+}
+
+func (c *Client) get(ctx context.Context, platform, path string, out interface{}) error {
+    return c.do(ctx, platform, path, "GET", nil, out, nil)
+}
+
+func (c *Client) delete(ctx context.Context, platform, path string) error {
+    return c.do(ctx, platform, path, "DELETE", nil, nil, nil)
+}
+
+// query encodes the non-empty values of params, given as name and value
+// pairs, as query string including the leading question mark.
+func query(params ...string) string {
+    values := url.Values{}
+    for i := 0; i + 1 < len(params); i += 2 {
+        if params[i + 1] != "" {
+            values.Set(params[i], params[i + 1])
+        }
+    }
+
+    if len(values) == 0 {
+        return ""
+    }
+    return "?" + values.Encode()
+}
+
+// NewIdempotencyKey returns a random key for the IdempotencyKey of create
+// options. The gateway answers repeated creates with the same key with the
+// object created first, so that they are safe to retry.
+func NewIdempotencyKey() string {
+    var b [16]byte
+    if _, err := rand.Read(b[:]); err != nil {
+        panic(fmt.Sprintf("Unable to generate idempotency key: %v", err))
+    }
+    b[6] = b[6] & 0x0f | 0x40
+    b[8] = b[8] & 0x3f | 0x80
+    return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// idempotencyHeaders returns the headers sending key, if not empty.
+func idempotencyHeaders(key string) map[string]string {
+    if key == "" {
+        return nil
+    }
+    return map[string]string{"Idempotency-Key": key}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type FirewallRule struct {
+    ID				ID		`json:"id"`
+    Name			string		`json:"name"`
+    Action			string		`json:"action"`
+    CreateTime			string		`json:"create_time"`
+    DestinationIPAddress	string		`json:"destination_ip_address"`
+    DestinationPort		string		`json:"destination_port"`
+    IPVersion			int		`json:"ip_version"`
+    Platform			string		`json:"platform"`
+    Project			ID		`json:"project"`
+    Protocol			string		`json:"protocol"`
+    SourceIPAddress		string		`json:"source_ip_address"`
+    SourcePort			string		`json:"source_port"`
+    User			StringMap	`json:"user"`
+}
+
+type FirewallRuleListOpts struct {
+    Project	string
+}
+
+type FirewallRuleCreateOpts struct {
+    Action			string	`json:"action,omitempty"`
+    DestinationIPAddress	string	`json:"destination_ip_address,omitempty"`
+    DestinationPort		string	`json:"destination_port,omitempty"`
+    Name			string	`json:"name"`
+    Project			string	`json:"project"`
+    Protocol			string	`json:"protocol,omitempty"`
+    SourceIPAddress		string	`json:"source_ip_address,omitempty"`
+    SourcePort			string	`json:"source_port,omitempty"`
+}
+
+// FirewallRuleUpdateOpts changes the fields which are set.
+type FirewallRuleUpdateOpts struct {
+    Action			string	`json:"action,omitempty"`
+    DestinationIPAddress	string	`json:"destination_ip_address,omitempty"`
+    DestinationPort		string	`json:"destination_port,omitempty"`
+    Protocol			string	`json:"protocol,omitempty"`
+    SourceIPAddress		string	`json:"source_ip_address,omitempty"`
+    SourcePort			string	`json:"source_port,omitempty"`
+}
+
+// FirewallRulesService manages firewall rules.
+type FirewallRulesService struct {
+    client	*Client
+}
+
+func firewallRulesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/firewall_rules/", platform)
+}
+
+func firewallRulePath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/firewall_rules/%s/", platform, id)
+}
+
+func (s *FirewallRulesService) List(ctx context.Context, platform string, opts FirewallRuleListOpts) ([]FirewallRule, error) {
+    var rules []FirewallRule
+    path := firewallRulesPath(platform) + query("project", opts.Project)
+    err := s.client.get(ctx, platform, path, &rules)
+    return rules, err
+}
+
+func (s *FirewallRulesService) Get(ctx context.Context, platform, id string) (*FirewallRule, error) {
+    var rule FirewallRule
+    if err := s.client.get(ctx, platform, firewallRulePath(platform, id), &rule); err != nil {
+        return nil, err
+    }
+    return &rule, nil
+}
+
+func (s *FirewallRulesService) Create(ctx context.Context, platform string, opts FirewallRuleCreateOpts) (*FirewallRule, error) {
+    var rule FirewallRule
+    if err := s.client.do(ctx, platform, firewallRulesPath(platform), "POST", opts, &rule, nil); err != nil {
+        return nil, err
+    }
+    return &rule, nil
+}
+
+func (s *FirewallRulesService) Update(ctx context.Context, platform, id string, opts FirewallRuleUpdateOpts) error {
+    return s.client.do(ctx, platform, firewallRulePath(platform, id), "PATCH", opts, nil, nil)
+}
+
+func (s *FirewallRulesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, firewallRulePath(platform, id))
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type Firewall struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    Desc		string		`json:"desc"`
+    AssociateNetworks	[]ID		`json:"associate_networks"`
+    CreateTime		string		`json:"create_time"`
+    Platform		string		`json:"platform"`
+    Project		ID		`json:"project"`
+    Rules		[]ID		`json:"rules"`
+    Status		string		`json:"status"`
+    StatusReason	string		`json:"status_reason"`
+    User		StringMap	`json:"user"`
+}
+
+type FirewallListOpts struct {
+    Project	string
+}
+
+type FirewallCreateOpts struct {
+    AssociateNetworks	[]int	`json:"associate_networks,omitempty"`
+    Desc		string	`json:"desc,omitempty"`
+    Name		string	`json:"name"`
+    Project		string	`json:"project"`
+    Rules		[]int	`json:"rules,omitempty"`
+}
+
+// FirewallUpdateOpts changes the fields which are set. A non-nil
+// AssociateNetworks or Rules replaces the whole list.
+type FirewallUpdateOpts struct {
+    AssociateNetworks	*[]int	`json:"associate_networks,omitempty"`
+    Desc		string	`json:"desc,omitempty"`
+    Rules		*[]int	`json:"rules,omitempty"`
+}
+
+// FirewallsService manages firewalls.
+type FirewallsService struct {
+    client	*Client
+}
+
+func firewallsPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/firewalls/", platform)
+}
+
+func firewallPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/firewalls/%s/", platform, id)
+}
+
+func (s *FirewallsService) List(ctx context.Context, platform string, opts FirewallListOpts) ([]Firewall, error) {
+    var firewalls []Firewall
+    path := firewallsPath(platform) + query("project", opts.Project)
+    err := s.client.get(ctx, platform, path, &firewalls)
+    return firewalls, err
+}
+
+func (s *FirewallsService) Get(ctx context.Context, platform, id string) (*Firewall, error) {
+    var firewall Firewall
+    if err := s.client.get(ctx, platform, firewallPath(platform, id), &firewall); err != nil {
+        return nil, err
+    }
+    return &firewall, nil
+}
+
+func (s *FirewallsService) Create(ctx context.Context, platform string, opts FirewallCreateOpts) (*Firewall, error) {
+    var firewall Firewall
+    if err := s.client.do(ctx, platform, firewallsPath(platform), "POST", opts, &firewall, nil); err != nil {
+        return nil, err
+    }
+    return &firewall, nil
+}
+
+func (s *FirewallsService) Update(ctx context.Context, platform, id string, opts FirewallUpdateOpts) error {
+    return s.client.do(ctx, platform, firewallPath(platform, id), "PATCH", opts, nil, nil)
+}
+
+func (s *FirewallsService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, firewallPath(platform, id))
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type IKEPolicy struct {
+    ID				ID		`json:"id"`
+    Name			string		`json:"name"`
+    AuthAlgorithm		string		`json:"auth_algorithm"`
+    EncryptionAlgorithm		string		`json:"encryption_algorithm"`
+    IKEVersion			string		`json:"ike_version"`
+    Lifetime			int		`json:"lifetime"`
+    PFS				string		`json:"pfs"`
+    User			StringMap	`json:"user"`
+}
+
+type IKEPolicyListOpts struct {
+    Project	string
+}
+
+type IKEPolicyCreateOpts struct {
+    AuthAlgorithm		string	`json:"auth_algorithm,omitempty"`
+    EncryptionAlgorithm		string	`json:"encryption_algorithm,omitempty"`
+    IKEVersion			string	`json:"ike_version,omitempty"`
+    Lifetime			int	`json:"lifetime,omitempty"`
+    Name			string	`json:"name"`
+    PFS				string	`json:"pfs,omitempty"`
+    Project			string	`json:"project"`
+}
+
+// IKEPoliciesService manages the IKE policies of VPN services.
+type IKEPoliciesService struct {
+    client	*Client
+}
+
+func ikePoliciesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/ike_policies/", platform)
+}
+
+func ikePolicyPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/ike_policies/%s/", platform, id)
+}
+
+func (s *IKEPoliciesService) List(ctx context.Context, platform string, opts IKEPolicyListOpts) ([]IKEPolicy, error) {
+    var policies []IKEPolicy
+    path := ikePoliciesPath(platform) + query("project", opts.Project)
+    err := s.client.get(ctx, platform, path, &policies)
+    return policies, err
+}
+
+func (s *IKEPoliciesService) Get(ctx context.Context, platform, id string) (*IKEPolicy, error) {
+    var policy IKEPolicy
+    if err := s.client.get(ctx, platform, ikePolicyPath(platform, id), &policy); err != nil {
+        return nil, err
+    }
+    return &policy, nil
+}
+
+func (s *IKEPoliciesService) Create(ctx context.Context, platform string, opts IKEPolicyCreateOpts) (*IKEPolicy, error) {
+    var policy IKEPolicy
+    if err := s.client.do(ctx, platform, ikePoliciesPath(platform), "POST", opts, &policy, nil); err != nil {
+        return nil, err
+    }
+    return &policy, nil
+}
+
+func (s *IKEPoliciesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, ikePolicyPath(platform, id))
+}
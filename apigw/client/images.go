@@ -0,0 +1,59 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+// Image is a server image saved from a VCS server.
+type Image struct {
+    ID			ID	`json:"id"`
+    Name		string	`json:"name"`
+    Desc		string	`json:"desc"`
+    OS			string	`json:"os"`
+    OSVersion		string	`json:"os_version"`
+    CreateTime		string	`json:"create_time"`
+    IsEnabled		bool	`json:"is_enabled"`
+    IsPublic		bool	`json:"is_public"`
+    RefImgID		string	`json:"ref_img_id"`
+    Status		string	`json:"status"`
+    StatusReason	string	`json:"status_reason"`
+}
+
+type ImageSaveOpts struct {
+    Desc	string	`json:"desc,omitempty"`
+    Name	string	`json:"name"`
+    OS		string	`json:"os"`
+    OSVersion	string	`json:"os_version"`
+}
+
+// ImagesService manages server images.
+type ImagesService struct {
+    client	*Client
+}
+
+func imagePath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/images/%s/", platform, id)
+}
+
+func (s *ImagesService) Get(ctx context.Context, platform, id string) (*Image, error) {
+    var image Image
+    if err := s.client.get(ctx, platform, imagePath(platform, id), &image); err != nil {
+        return nil, err
+    }
+    return &image, nil
+}
+
+// Save creates an image of a server.
+func (s *ImagesService) Save(ctx context.Context, platform, server string, opts ImageSaveOpts) (*Image, error) {
+    var image Image
+    path := imagePath(platform, server) + "save/"
+    if err := s.client.do(ctx, platform, path, "PUT", opts, &image, nil); err != nil {
+        return nil, err
+    }
+    return &image, nil
+}
+
+func (s *ImagesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, imagePath(platform, id))
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type IPSecPolicy struct {
+    ID				ID		`json:"id"`
+    Name			string		`json:"name"`
+    AuthAlgorithm		string		`json:"auth_algorithm"`
+    EncapsulationMode		string		`json:"encapsulation_mode"`
+    EncryptionAlgorithm		string		`json:"encryption_algorithm"`
+    Lifetime			int		`json:"lifetime"`
+    PFS				string		`json:"pfs"`
+    TransformProtocol		string		`json:"transform_protocol"`
+    User			StringMap	`json:"user"`
+}
+
+type IPSecPolicyListOpts struct {
+    Project	string
+}
+
+type IPSecPolicyCreateOpts struct {
+    AuthAlgorithm		string	`json:"auth_algorithm,omitempty"`
+    EncapsulationMode		string	`json:"encapsulation_mode,omitempty"`
+    EncryptionAlgorithm		string	`json:"encryption_algorithm,omitempty"`
+    TransformProtocol		string	`json:"transform_protocol,omitempty"`
+    Lifetime			int	`json:"lifetime,omitempty"`
+    Name			string	`json:"name"`
+    PFS				string	`json:"pfs,omitempty"`
+    Project			string	`json:"project"`
+}
+
+// IPSecPoliciesService manages the IPsec policies of VPN services.
+type IPSecPoliciesService struct {
+    client	*Client
+}
+
+func ipsecPoliciesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/ipsec_policies/", platform)
+}
+
+func ipsecPolicyPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/ipsec_policies/%s/", platform, id)
+}
+
+func (s *IPSecPoliciesService) List(ctx context.Context, platform string, opts IPSecPolicyListOpts) ([]IPSecPolicy, error) {
+    var policies []IPSecPolicy
+    path := ipsecPoliciesPath(platform) + query("project", opts.Project)
+    err := s.client.get(ctx, platform, path, &policies)
+    return policies, err
+}
+
+func (s *IPSecPoliciesService) Get(ctx context.Context, platform, id string) (*IPSecPolicy, error) {
+    var policy IPSecPolicy
+    if err := s.client.get(ctx, platform, ipsecPolicyPath(platform, id), &policy); err != nil {
+        return nil, err
+    }
+    return &policy, nil
+}
+
+func (s *IPSecPoliciesService) Create(ctx context.Context, platform string, opts IPSecPolicyCreateOpts) (*IPSecPolicy, error) {
+    var policy IPSecPolicy
+    if err := s.client.do(ctx, platform, ipsecPoliciesPath(platform), "POST", opts, &policy, nil); err != nil {
+        return nil, err
+    }
+    return &policy, nil
+}
+
+func (s *IPSecPoliciesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, ipsecPolicyPath(platform, id))
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+// Key is an S3 key pair. The public key of a project has no name.
+type Key struct {
+    Name	string	`json:"name"`
+    AccessKey	string	`json:"access_key"`
+    SecretKey	string	`json:"secret_key"`
+}
+
+// ProjectKeys are the S3 keys of a project.
+type ProjectKeys struct {
+    Public	*Key	`json:"public"`
+    Private	[]Key	`json:"private"`
+}
+
+type keyOpts struct {
+    Name	string	`json:"name"`
+}
+
+// KeysService manages the S3 keys of projects.
+type KeysService struct {
+    client	*Client
+}
+
+func keysPath(platform, project string) string {
+    return fmt.Sprintf("api/v4/%s/projects/%s/key/", platform, project)
+}
+
+func (s *KeysService) List(ctx context.Context, platform, project string) (*ProjectKeys, error) {
+    var keys ProjectKeys
+    if err := s.client.get(ctx, platform, keysPath(platform, project), &keys); err != nil {
+        return nil, err
+    }
+    return &keys, nil
+}
+
+// Create creates a private key of a project.
+func (s *KeysService) Create(ctx context.Context, platform, project, name string) error {
+    return s.client.do(ctx, platform, keysPath(platform, project), "POST", keyOpts{Name: name}, nil, nil)
+}
+
+func (s *KeysService) Delete(ctx context.Context, platform, project, name string) error {
+    return s.client.do(ctx, platform, keysPath(platform, project), "DELETE", keyOpts{Name: name}, nil, nil)
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+// Listener is an additional protocol and port of a load balancer, with its
+// own pool of members and health monitor.
+type Listener struct {
+    ID			ID			`json:"id"`
+    Certificate		ID			`json:"certificate"`
+    CreateTime		string			`json:"create_time"`
+    LBMethod		string			`json:"lb_method"`
+    LoadBalancer	ID			`json:"loadbalancer"`
+    Members		[]LoadBalancerMember	`json:"members"`
+    Monitor		*LoadBalancerMonitor	`json:"monitor"`
+    Protocol		string			`json:"protocol"`
+    ProtocolPort	int			`json:"protocol_port"`
+    Status		string			`json:"status"`
+    StatusReason	string			`json:"status_reason"`
+    User		StringMap		`json:"user"`
+}
+
+type ListenerListOpts struct {
+    LoadBalancer	string
+}
+
+// ListenerCreateOpts creates a listener, with a health monitor if
+// MonitorType is set. Certificate is the certificate of a listener
+// terminating HTTPS.
+type ListenerCreateOpts struct {
+    Certificate		string			`json:"certificate,omitempty"`
+    Delay		int			`json:"delay,omitempty"`
+    ExpectedCodes	string			`json:"expected_codes,omitempty"`
+    HTTPMethod		string			`json:"http_method,omitempty"`
+    LBMethod		string			`json:"lb_method"`
+    LoadBalancer	string			`json:"loadbalancer"`
+    MaxRetries		int			`json:"max_retries,omitempty"`
+    Members		[]LoadBalancerMember	`json:"members,omitempty"`
+    MonitorType		string			`json:"monitor_type,omitempty"`
+    Protocol		string			`json:"protocol"`
+    ProtocolPort	int			`json:"protocol_port"`
+    Timeout		int			`json:"timeout,omitempty"`
+    URLPath		string			`json:"url_path,omitempty"`
+
+    // IdempotencyKey is sent as Idempotency-Key header, see
+    // NewIdempotencyKey.
+    IdempotencyKey	string			`json:"-"`
+}
+
+// ListenerUpdateOpts changes the fields which are set, like
+// LoadBalancerUpdateOpts.
+type ListenerUpdateOpts struct {
+    LoadBalancerMonitorUpdateOpts
+    Certificate		string			`json:"certificate,omitempty"`
+    LBMethod		string			`json:"lb_method,omitempty"`
+    Members		*[]LoadBalancerMember	`json:"members,omitempty"`
+}
+
+// ListenersService manages the listeners of load balancers.
+type ListenersService struct {
+    client	*Client
+}
+
+func listenersPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/listeners/", platform)
+}
+
+func listenerPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/listeners/%s/", platform, id)
+}
+
+func (s *ListenersService) List(ctx context.Context, platform string, opts ListenerListOpts) ([]Listener, error) {
+    var listeners []Listener
+    path := listenersPath(platform) + query("loadbalancer", opts.LoadBalancer)
+    err := s.client.get(ctx, platform, path, &listeners)
+    return listeners, err
+}
+
+func (s *ListenersService) Get(ctx context.Context, platform, id string) (*Listener, error) {
+    var listener Listener
+    if err := s.client.get(ctx, platform, listenerPath(platform, id), &listener); err != nil {
+        return nil, err
+    }
+    return &listener, nil
+}
+
+func (s *ListenersService) Create(ctx context.Context, platform string, opts ListenerCreateOpts) (*Listener, error) {
+    var listener Listener
+    if err := s.client.do(ctx, platform, listenersPath(platform), "POST", opts, &listener,
+            idempotencyHeaders(opts.IdempotencyKey)); err != nil {
+        return nil, err
+    }
+    return &listener, nil
+}
+
+func (s *ListenersService) Update(ctx context.Context, platform, id string, opts ListenerUpdateOpts) error {
+    return s.client.do(ctx, platform, listenerPath(platform, id), "PATCH", opts, nil, nil)
+}
+
+// DeleteMonitor removes the health monitor of a listener.
+func (s *ListenersService) DeleteMonitor(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, listenerPath(platform, id) + "monitor/")
+}
+
+func (s *ListenersService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, listenerPath(platform, id))
+}
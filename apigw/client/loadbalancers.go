@@ -0,0 +1,150 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type LoadBalancer struct {
+    ID			ID			`json:"id"`
+    Name		string			`json:"name"`
+    Desc		string			`json:"desc"`
+    ActiveConnections	int			`json:"active_connections"`
+    Certificate		ID			`json:"certificate"`
+    CreateTime		string			`json:"create_time"`
+    LBMethod		string			`json:"lb_method"`
+    Members		[]LoadBalancerMember	`json:"members"`
+    Monitor		*LoadBalancerMonitor	`json:"monitor"`
+    PrivateNet		ID			`json:"private_net"`
+    Protocol		string			`json:"protocol"`
+    ProtocolPort	int			`json:"protocol_port"`
+    Status		string			`json:"status"`
+    StatusReason	string			`json:"status_reason"`
+    TotalConnections	int			`json:"total_connections"`
+    User		StringMap		`json:"user"`
+    VIP			string			`json:"vip"`
+    WAF			StringMap		`json:"waf"`
+}
+
+// LoadBalancerMember is a backend of a load balancer. Status is only set in
+// responses. Port and Weight are always sent, a weight of 0 drains the member.
+type LoadBalancerMember struct {
+    IP		string	`json:"ip,omitempty"`
+    Port	int	`json:"port"`
+    Weight	int	`json:"weight"`
+    Status	string	`json:"status,omitempty"`
+}
+
+// LoadBalancerMonitor is the health monitor of a load balancer.
+type LoadBalancerMonitor struct {
+    Delay		int	`json:"delay"`
+    ExpectedCodes	string	`json:"expected_codes"`
+    HTTPMethod		string	`json:"http_method"`
+    MaxRetries		int	`json:"max_retries"`
+    MonitorType		string	`json:"monitor_type"`
+    Timeout		int	`json:"timeout"`
+    URLPath		string	`json:"url_path"`
+}
+
+type LoadBalancerListOpts struct {
+    Name	string
+    Project	string
+    PrivateNet	string
+}
+
+// LoadBalancerCreateOpts creates a load balancer, with a health monitor if
+// MonitorType is set. Certificate is the certificate of a load balancer
+// terminating HTTPS.
+type LoadBalancerCreateOpts struct {
+    Certificate		string	`json:"certificate,omitempty"`
+    Delay		int	`json:"delay,omitempty"`
+    Desc		string	`json:"desc,omitempty"`
+    ExpectedCodes	string	`json:"expected_codes,omitempty"`
+    HTTPMethod		string	`json:"http_method,omitempty"`
+    LBMethod		string	`json:"lb_method"`
+    MaxRetries		int	`json:"max_retries,omitempty"`
+    MonitorType		string	`json:"monitor_type,omitempty"`
+    Name		string	`json:"name"`
+    PrivateNet		string	`json:"private_net"`
+    Protocol		string	`json:"protocol"`
+    ProtocolPort	int	`json:"protocol_port"`
+    Timeout		int	`json:"timeout,omitempty"`
+    URLPath		string	`json:"url_path,omitempty"`
+
+    // IdempotencyKey is sent as Idempotency-Key header, see
+    // NewIdempotencyKey.
+    IdempotencyKey	string	`json:"-"`
+}
+
+// LoadBalancerMonitorUpdateOpts changes the fields of the health monitor of a
+// load balancer or listener which are set. Adding a monitor requires
+// MonitorType. The fields are pointers, so that 0 and "" are sent as well.
+type LoadBalancerMonitorUpdateOpts struct {
+    Delay		*int	`json:"delay,omitempty"`
+    ExpectedCodes	*string	`json:"expected_codes,omitempty"`
+    HTTPMethod		*string	`json:"http_method,omitempty"`
+    MaxRetries		*int	`json:"max_retries,omitempty"`
+    MonitorType		*string	`json:"monitor_type,omitempty"`
+    Timeout		*int	`json:"timeout,omitempty"`
+    URLPath		*string	`json:"url_path,omitempty"`
+}
+
+// LoadBalancerUpdateOpts changes the fields which are set. A non-nil Members
+// replaces all members of the load balancer.
+type LoadBalancerUpdateOpts struct {
+    LoadBalancerMonitorUpdateOpts
+    Certificate		string			`json:"certificate,omitempty"`
+    LBMethod		string			`json:"lb_method,omitempty"`
+    Members		*[]LoadBalancerMember	`json:"members,omitempty"`
+}
+
+// LoadBalancersService manages load balancers.
+type LoadBalancersService struct {
+    client	*Client
+}
+
+func loadBalancersPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/loadbalancers/", platform)
+}
+
+func loadBalancerPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/loadbalancers/%s/", platform, id)
+}
+
+func (s *LoadBalancersService) List(ctx context.Context, platform string, opts LoadBalancerListOpts) ([]LoadBalancer, error) {
+    var lbs []LoadBalancer
+    path := loadBalancersPath(platform) +
+        query("name", opts.Name, "project", opts.Project, "private_net", opts.PrivateNet)
+    err := s.client.get(ctx, platform, path, &lbs)
+    return lbs, err
+}
+
+func (s *LoadBalancersService) Get(ctx context.Context, platform, id string) (*LoadBalancer, error) {
+    var lb LoadBalancer
+    if err := s.client.get(ctx, platform, loadBalancerPath(platform, id), &lb); err != nil {
+        return nil, err
+    }
+    return &lb, nil
+}
+
+func (s *LoadBalancersService) Create(ctx context.Context, platform string, opts LoadBalancerCreateOpts) (*LoadBalancer, error) {
+    var lb LoadBalancer
+    if err := s.client.do(ctx, platform, loadBalancersPath(platform), "POST", opts, &lb,
+            idempotencyHeaders(opts.IdempotencyKey)); err != nil {
+        return nil, err
+    }
+    return &lb, nil
+}
+
+func (s *LoadBalancersService) Update(ctx context.Context, platform, id string, opts LoadBalancerUpdateOpts) error {
+    return s.client.do(ctx, platform, loadBalancerPath(platform, id), "PATCH", opts, nil, nil)
+}
+
+// DeleteMonitor removes the health monitor of a load balancer.
+func (s *LoadBalancersService) DeleteMonitor(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, loadBalancerPath(platform, id) + "monitor/")
+}
+
+func (s *LoadBalancersService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, loadBalancerPath(platform, id))
+}
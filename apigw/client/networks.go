@@ -0,0 +1,84 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type Network struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    CIDR		string		`json:"cidr"`
+    CreateTime		string		`json:"create_time"`
+    DNSDomain		string		`json:"dns_domain"`
+    ExtNet		string		`json:"ext_net"`
+    Firewall		StringMap	`json:"firewall"`
+    Gateway		string		`json:"gateway"`
+    IPVersion		int		`json:"ip_version"`
+    Nameservers		[]string	`json:"nameservers"`
+    Platform		string		`json:"platform"`
+    Project		ID		`json:"project"`
+    Status		string		`json:"status"`
+    StatusReason	string		`json:"status_reason"`
+    User		StringMap	`json:"user"`
+    WithRouter		bool		`json:"with_router"`
+}
+
+type NetworkListOpts struct {
+    Project	string
+}
+
+type NetworkCreateOpts struct {
+    CIDR	string		`json:"cidr"`
+    DNSDomain	string		`json:"dns_domain,omitempty"`
+    Gateway	string		`json:"gateway"`
+    Name	string		`json:"name"`
+    Nameservers	[]string	`json:"nameservers,omitempty"`
+    Project	string		`json:"project"`
+    WithRouter	bool		`json:"with_router,omitempty"`
+
+    // IdempotencyKey is sent as Idempotency-Key header, see
+    // NewIdempotencyKey.
+    IdempotencyKey	string	`json:"-"`
+}
+
+// NetworksService manages private networks.
+type NetworksService struct {
+    client	*Client
+}
+
+func networksPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/networks/", platform)
+}
+
+func networkPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/networks/%s/", platform, id)
+}
+
+func (s *NetworksService) List(ctx context.Context, platform string, opts NetworkListOpts) ([]Network, error) {
+    var networks []Network
+    path := networksPath(platform) + query("project", opts.Project)
+    err := s.client.get(ctx, platform, path, &networks)
+    return networks, err
+}
+
+func (s *NetworksService) Get(ctx context.Context, platform, id string) (*Network, error) {
+    var network Network
+    if err := s.client.get(ctx, platform, networkPath(platform, id), &network); err != nil {
+        return nil, err
+    }
+    return &network, nil
+}
+
+func (s *NetworksService) Create(ctx context.Context, platform string, opts NetworkCreateOpts) (*Network, error) {
+    var network Network
+    if err := s.client.do(ctx, platform, networksPath(platform), "POST", opts, &network,
+            idempotencyHeaders(opts.IdempotencyKey)); err != nil {
+        return nil, err
+    }
+    return &network, nil
+}
+
+func (s *NetworksService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, networkPath(platform, id))
+}
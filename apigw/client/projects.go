@@ -0,0 +1,23 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type Project struct {
+    ID		ID	`json:"id"`
+    Name	string	`json:"name"`
+    Platform	string	`json:"platform"`
+}
+
+// ProjectsService lists the projects of the API key.
+type ProjectsService struct {
+    client	*Client
+}
+
+func (s *ProjectsService) List(ctx context.Context, platform string) ([]Project, error) {
+    var projects []Project
+    err := s.client.get(ctx, platform, fmt.Sprintf("api/v4/%s/projects/", platform), &projects)
+    return projects, err
+}
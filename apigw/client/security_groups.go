@@ -0,0 +1,75 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type SecurityGroup struct {
+    ID		ID			`json:"id"`
+    Name	string			`json:"name"`
+    Rules	[]SecurityGroupRule	`json:"security_group_rules"`
+}
+
+// SecurityGroupRule is a rule of a security group. The port range is nil for
+// rules matching all ports.
+type SecurityGroupRule struct {
+    ID			ID	`json:"id"`
+    Direction		string	`json:"direction"`
+    Ethertype		string	`json:"ethertype"`
+    PortRangeMax	*int	`json:"port_range_max"`
+    PortRangeMin	*int	`json:"port_range_min"`
+    Protocol		string	`json:"protocol"`
+    RemoteIPPrefix	string	`json:"remote_ip_prefix"`
+}
+
+type SecurityGroupListOpts struct {
+    Project	string
+    Server	string
+}
+
+type SecurityGroupRuleCreateOpts struct {
+    Direction		string	`json:"direction,omitempty"`
+    Protocol		string	`json:"protocol,omitempty"`
+    RemoteIPPrefix	string	`json:"remote_ip_prefix,omitempty"`
+    PortRangeMin	int	`json:"port_range_min,omitempty"`
+    PortRangeMax	int	`json:"port_range_max,omitempty"`
+    Project		string	`json:"project"`
+}
+
+// SecurityGroupsService manages the security groups of VCS servers.
+type SecurityGroupsService struct {
+    client	*Client
+}
+
+func securityGroupsPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/security_groups/", platform)
+}
+
+func (s *SecurityGroupsService) List(ctx context.Context, platform string, opts SecurityGroupListOpts) ([]SecurityGroup, error) {
+    var sgs []SecurityGroup
+    path := securityGroupsPath(platform) + query("project", opts.Project, "server", opts.Server)
+    err := s.client.get(ctx, platform, path, &sgs)
+    return sgs, err
+}
+
+func (s *SecurityGroupsService) Get(ctx context.Context, platform, project, id string) (*SecurityGroup, error) {
+    var sg SecurityGroup
+    path := securityGroupsPath(platform) + query("project", project, "sg", id)
+    if err := s.client.get(ctx, platform, path, &sg); err != nil {
+        return nil, err
+    }
+    return &sg, nil
+}
+
+// AddRule adds a rule to a security group. The gateway does not return the
+// new rule, it has to be looked up in the security group.
+func (s *SecurityGroupsService) AddRule(ctx context.Context, platform, id string, opts SecurityGroupRuleCreateOpts) error {
+    path := securityGroupsPath(platform) + id + "/"
+    return s.client.do(ctx, platform, path, "PATCH", opts, nil, nil)
+}
+
+func (s *SecurityGroupsService) DeleteRule(ctx context.Context, platform, project, ruleID string) error {
+    path := fmt.Sprintf("api/v4/%s/security_group_rules/%s/", platform, ruleID) + query("project", project)
+    return s.client.delete(ctx, platform, path)
+}
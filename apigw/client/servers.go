@@ -0,0 +1,55 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+// Server is a server of a VCS site.
+type Server struct {
+    ID			ID				`json:"id"`
+    AutoScalingPolicy	*ServerAutoScalingPolicy	`json:"auto_scaling_policy"`
+}
+
+// ServerAutoScalingPolicy is the association of a server with an auto
+// scaling policy.
+type ServerAutoScalingPolicy struct {
+    ID			ID	`json:"id"`
+    Status		string	`json:"status"`
+    StatusReason	string	`json:"status_reason"`
+}
+
+type AutoScalingPolicyAttachOpts struct {
+    AutoScalingPolicy	string	`json:"auto_scaling_policy"`
+    Loadbalancer	string	`json:"loadbalancer,omitempty"`
+    ProtocolPort	int	`json:"protocol_port,omitempty"`
+    ScaledownAction	string	`json:"scaledown_action,omitempty"`
+    ScaleupAction	string	`json:"scaleup_action,omitempty"`
+}
+
+// ServersService manages the servers of VCS sites.
+type ServersService struct {
+    client	*Client
+}
+
+func serverPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/servers/%s/", platform, id)
+}
+
+func (s *ServersService) Get(ctx context.Context, platform, id string) (*Server, error) {
+    var server Server
+    if err := s.client.get(ctx, platform, serverPath(platform, id), &server); err != nil {
+        return nil, err
+    }
+    return &server, nil
+}
+
+// AttachAutoScalingPolicy associates a server with an auto scaling policy.
+func (s *ServersService) AttachAutoScalingPolicy(ctx context.Context, platform, id string, opts AutoScalingPolicyAttachOpts) error {
+    path := serverPath(platform, id) + "auto_scaling_policy/"
+    return s.client.do(ctx, platform, path, "POST", opts, nil, nil)
+}
+
+func (s *ServersService) DetachAutoScalingPolicy(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, serverPath(platform, id) + "auto_scaling_policy/")
+}
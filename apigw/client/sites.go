@@ -0,0 +1,156 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+// Site is a VCS or container site.
+type Site struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    Desc		string		`json:"desc"`
+    CreateTime		string		`json:"create_time"`
+    ExtNet		string		`json:"ext_net"`
+    PublicIP		string		`json:"public_ip"`
+    Project		ID		`json:"project"`
+    Solution		ID		`json:"solution"`
+    Servers		[]SiteServer	`json:"servers"`
+    Status		string		`json:"status"`
+    StatusReason	string		`json:"status_reason"`
+    User		StringMap	`json:"user"`
+}
+
+// SiteServer is a server of a VCS site.
+type SiteServer struct {
+    ID		ID	`json:"id"`
+    FlavorID	ID	`json:"flavor_id"`
+    Hostname	string	`json:"hostname"`
+    Status	string	`json:"status"`
+}
+
+// Container describes the pods and services of a container site.
+type Container struct {
+    Pods	[]Pod		`json:"Pod"`
+    Services	[]Service	`json:"Service"`
+}
+
+type Pod struct {
+    Containers	[]PodContainer	`json:"container"`
+    Flavor	string		`json:"flavor"`
+    Message	string		`json:"message"`
+    Name	string		`json:"name"`
+    Reason	string		`json:"reason"`
+    Status	string		`json:"status"`
+}
+
+type PodContainer struct {
+    Image	string			`json:"image"`
+    Name	string			`json:"name"`
+    Ports	[]ContainerPort		`json:"ports"`
+    Volumes	[]ContainerVolume	`json:"volumes"`
+}
+
+type ContainerPort struct {
+    Name	string	`json:"name"`
+    Port	int	`json:"port"`
+    Protocol	string	`json:"protocol"`
+}
+
+type ContainerVolume struct {
+    MountPath	string	`json:"mountPath"`
+    Path	string	`json:"path"`
+    ReadOnly	bool	`json:"readOnly"`
+    Type	string	`json:"type"`
+}
+
+type Service struct {
+    Name	string		`json:"name"`
+    NetType	string		`json:"net_type"`
+    Ports	[]ServicePort	`json:"ports"`
+    PublicIP	[]string	`json:"public_ip"`
+}
+
+type ServicePort struct {
+    Port	int	`json:"port"`
+    Protocol	string	`json:"protocol"`
+    TargetPort	int	`json:"target_port"`
+}
+
+type SiteListOpts struct {
+    Name	string
+    Project	string
+}
+
+type SiteCreateOpts struct {
+    Desc	string	`json:"desc,omitempty"`
+    Name	string	`json:"name"`
+    Project	string	`json:"project"`
+    Solution	string	`json:"solution"`
+
+    // ExtraProperties are passed to the solution as x-extra-property-*
+    // headers.
+    ExtraProperties	map[string]string	`json:"-"`
+
+    // IdempotencyKey is sent as Idempotency-Key header, see
+    // NewIdempotencyKey.
+    IdempotencyKey	string	`json:"-"`
+}
+
+// SitesService manages VCS and container sites.
+type SitesService struct {
+    client	*Client
+}
+
+func sitesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/sites/", platform)
+}
+
+func sitePath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/sites/%s/", platform, id)
+}
+
+func (s *SitesService) List(ctx context.Context, platform string, opts SiteListOpts) ([]Site, error) {
+    var sites []Site
+    path := sitesPath(platform) + query("project", opts.Project, "name", opts.Name)
+    err := s.client.get(ctx, platform, path, &sites)
+    return sites, err
+}
+
+func (s *SitesService) Get(ctx context.Context, platform, id string) (*Site, error) {
+    var site Site
+    if err := s.client.get(ctx, platform, sitePath(platform, id), &site); err != nil {
+        return nil, err
+    }
+    return &site, nil
+}
+
+// GetContainer returns the pods and services of a container site.
+func (s *SitesService) GetContainer(ctx context.Context, platform, id string) (*Container, error) {
+    var container Container
+    path := sitePath(platform, id) + "container/"
+    if err := s.client.get(ctx, platform, path, &container); err != nil {
+        return nil, err
+    }
+    return &container, nil
+}
+
+func (s *SitesService) Create(ctx context.Context, platform string, opts SiteCreateOpts) (*Site, error) {
+    headers := make(map[string]string, len(opts.ExtraProperties))
+    for key, value := range opts.ExtraProperties {
+        headers["x-extra-property-" + key] = value
+    }
+    for key, value := range idempotencyHeaders(opts.IdempotencyKey) {
+        headers[key] = value
+    }
+
+    var site Site
+    if err := s.client.do(ctx, platform, sitesPath(platform), "POST", opts, &site, headers); err != nil {
+        return nil, err
+    }
+    return &site, nil
+}
+
+func (s *SitesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, sitePath(platform, id))
+}
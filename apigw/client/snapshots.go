@@ -0,0 +1,69 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type Snapshot struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    Desc		string		`json:"desc"`
+    CreateTime		string		`json:"create_time"`
+    RestoreVolume	ID		`json:"restore_volume"`
+    SnapshotUUID	string		`json:"snapshot_uuid"`
+    Status		string		`json:"status"`
+    StatusReason	string		`json:"status_reason"`
+    User		StringMap	`json:"user"`
+    Volume		ID		`json:"volume"`
+}
+
+type SnapshotListOpts struct {
+    Project	string
+}
+
+type SnapshotCreateOpts struct {
+    Desc	string	`json:"desc,omitempty"`
+    Name	string	`json:"name"`
+    Volume	string	`json:"volume"`
+}
+
+// SnapshotsService manages volume snapshots.
+type SnapshotsService struct {
+    client	*Client
+}
+
+func snapshotsPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/snapshots/", platform)
+}
+
+func snapshotPath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/snapshots/%s/", platform, id)
+}
+
+func (s *SnapshotsService) List(ctx context.Context, platform string, opts SnapshotListOpts) ([]Snapshot, error) {
+    var snapshots []Snapshot
+    path := snapshotsPath(platform) + query("project", opts.Project)
+    err := s.client.get(ctx, platform, path, &snapshots)
+    return snapshots, err
+}
+
+func (s *SnapshotsService) Get(ctx context.Context, platform, id string) (*Snapshot, error) {
+    var snapshot Snapshot
+    if err := s.client.get(ctx, platform, snapshotPath(platform, id), &snapshot); err != nil {
+        return nil, err
+    }
+    return &snapshot, nil
+}
+
+func (s *SnapshotsService) Create(ctx context.Context, platform string, opts SnapshotCreateOpts) (*Snapshot, error) {
+    var snapshot Snapshot
+    if err := s.client.do(ctx, platform, snapshotsPath(platform), "POST", opts, &snapshot, nil); err != nil {
+        return nil, err
+    }
+    return &snapshot, nil
+}
+
+func (s *SnapshotsService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, snapshotPath(platform, id))
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+// solutionsHost is the platform serving the solution catalog.
+const solutionsHost = "goc"
+
+type Solution struct {
+    ID			ID	`json:"id"`
+    Name		string	`json:"name"`
+    Category		string	`json:"category"`
+    CreateTime		string	`json:"create_time"`
+    Desc		string	`json:"desc"`
+    IsPublic		bool	`json:"is_public"`
+    IsTenantAdminOnly	bool	`json:"is_tenant_admin_only"`
+}
+
+// ProjectSolution is a solution as deployed to a project, with the extra
+// properties accepted when creating a site from it.
+type ProjectSolution struct {
+    ID			ID			`json:"id"`
+    SiteExtraProp	map[string]interface{}	`json:"site_extra_prop"`
+}
+
+type SolutionListOpts struct {
+    Category	string
+    Name	string
+    Project	string
+}
+
+// SolutionsService looks up solutions, the templates of sites.
+type SolutionsService struct {
+    client	*Client
+}
+
+func (s *SolutionsService) List(ctx context.Context, opts SolutionListOpts) ([]Solution, error) {
+    var solutions []Solution
+    path := "api/v4/solutions/" +
+        query("name", opts.Name, "project", opts.Project, "category", opts.Category)
+    err := s.client.get(ctx, solutionsHost, path, &solutions)
+    return solutions, err
+}
+
+func (s *SolutionsService) GetProjectSolution(ctx context.Context, platform, project, id string) (*ProjectSolution, error) {
+    var solution ProjectSolution
+    path := fmt.Sprintf("api/v4/%s/projects/%s/solutions/%s/", platform, project, id)
+    if err := s.client.get(ctx, platform, path, &solution); err != nil {
+        return nil, err
+    }
+    return &solution, nil
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "strconv"
+)
+
+// ID is the identifier of a gateway object. The gateway returns identifiers
+// as numbers or as strings, and references other objects either by their
+// identifier or by embedding the object. ID accepts all of these forms and
+// keeps the identifier as string, the way it is stored in terraform state.
+type ID string
+
+func (id *ID) UnmarshalJSON(b []byte) error {
+    value, err := decodeJSON(b)
+    if err != nil {
+        return err
+    }
+
+    switch v := value.(type) {
+    case nil:
+        *id = ""
+    case string:
+        *id = ID(v)
+    case json.Number:
+        *id = ID(v.String())
+    case map[string]interface{}:
+        var ref struct {
+            ID	ID	`json:"id"`
+        }
+        if err := json.Unmarshal(b, &ref); err != nil {
+            return err
+        }
+        *id = ref.ID
+    default:
+        return fmt.Errorf("Unable to decode %s as ID", b)
+    }
+
+    return nil
+}
+
+// String returns the identifier.
+func (id ID) String() string {
+    return string(id)
+}
+
+// StringMap is a JSON object of scalar values, such as the user owning an
+// object. All values are converted to strings.
+type StringMap map[string]string
+
+func (m *StringMap) UnmarshalJSON(b []byte) error {
+    value, err := decodeJSON(b)
+    if err != nil {
+        return err
+    }
+
+    if value == nil {
+        *m = nil
+        return nil
+    }
+
+    object, ok := value.(map[string]interface{})
+    if !ok {
+        return fmt.Errorf("Unable to decode %s as object", b)
+    }
+
+    result := make(StringMap, len(object))
+    for key, item := range object {
+        switch v := item.(type) {
+        case nil:
+            result[key] = ""
+        case string:
+            result[key] = v
+        case json.Number:
+            result[key] = v.String()
+        case bool:
+            result[key] = strconv.FormatBool(v)
+        default:
+            encoded, err := json.Marshal(v)
+            if err != nil {
+                return err
+            }
+            result[key] = string(encoded)
+        }
+    }
+
+    *m = result
+    return nil
+}
+
+func decodeJSON(b []byte) (interface{}, error) {
+    decoder := json.NewDecoder(bytes.NewReader(b))
+    decoder.UseNumber()
+
+    var value interface{}
+    if err := decoder.Decode(&value); err != nil {
+        return nil, err
+    }
+    return value, nil
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type Volume struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    Desc		string		`json:"desc"`
+    AttachedHost	*VolumeHost	`json:"attached_host"`
+    CreateTime		string		`json:"create_time"`
+    IsAttached		bool		`json:"is_attached"`
+    IsBootable		bool		`json:"is_bootable"`
+    IsPublic		bool		`json:"is_public"`
+    Mountpoint		[]string	`json:"mountpoint"`
+    Project		ID		`json:"project"`
+    Size		int		`json:"size"`
+    SnapshotList	[]ID		`json:"snapshot_list"`
+    SrcSnapshot		ID		`json:"src_snapshot"`
+    Status		string		`json:"status"`
+    StatusReason	string		`json:"status_reason"`
+    User		StringMap	`json:"user"`
+    VolumeType		string		`json:"volume_type"`
+    VolumeUUID		string		`json:"volume_uuid"`
+}
+
+// VolumeHost is the server a volume is attached to.
+type VolumeHost struct {
+    ID		ID	`json:"id"`
+    Hostname	string	`json:"hostname"`
+}
+
+type VolumeListOpts struct {
+    Project	string
+}
+
+type VolumeCreateOpts struct {
+    Desc		string	`json:"desc,omitempty"`
+    Name		string	`json:"name"`
+    Project		string	`json:"project,omitempty"`
+    Size		int	`json:"size,omitempty"`
+    SrcSnapshot		string	`json:"src_snapshot,omitempty"`
+    VolumeType		string	`json:"volume_type,omitempty"`
+}
+
+// VolumeActionOpts describes an action on a volume. Status is one of
+// "extend", "attach" or "detach".
+type VolumeActionOpts struct {
+    Mountpoint	string	`json:"mountpoint,omitempty"`
+    Server	string	`json:"server,omitempty"`
+    Size	int	`json:"size,omitempty"`
+    Status	string	`json:"status"`
+}
+
+// VolumesService manages block storage volumes.
+type VolumesService struct {
+    client	*Client
+}
+
+func volumesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/volumes/", platform)
+}
+
+func volumePath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/volumes/%s/", platform, id)
+}
+
+func (s *VolumesService) List(ctx context.Context, platform string, opts VolumeListOpts) ([]Volume, error) {
+    var volumes []Volume
+    path := volumesPath(platform) + query("project", opts.Project)
+    err := s.client.get(ctx, platform, path, &volumes)
+    return volumes, err
+}
+
+func (s *VolumesService) Get(ctx context.Context, platform, id string) (*Volume, error) {
+    var volume Volume
+    if err := s.client.get(ctx, platform, volumePath(platform, id), &volume); err != nil {
+        return nil, err
+    }
+    return &volume, nil
+}
+
+func (s *VolumesService) Create(ctx context.Context, platform string, opts VolumeCreateOpts) (*Volume, error) {
+    var volume Volume
+    if err := s.client.do(ctx, platform, volumesPath(platform), "POST", opts, &volume, nil); err != nil {
+        return nil, err
+    }
+    return &volume, nil
+}
+
+// Action extends, attaches or detaches a volume.
+func (s *VolumesService) Action(ctx context.Context, platform, id string, opts VolumeActionOpts) error {
+    path := volumePath(platform, id) + "action/"
+    return s.client.do(ctx, platform, path, "PUT", opts, nil, nil)
+}
+
+func (s *VolumesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, volumePath(platform, id))
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+    "context"
+    "fmt"
+)
+
+type VPNService struct {
+    ID			ID		`json:"id"`
+    Name		string		`json:"name"`
+    Connection		*VPNConnection	`json:"connection"`
+    IKEPolicy		ID		`json:"ike_policy"`
+    IPSecPolicy		ID		`json:"ipsec_policy"`
+    LocalAddress	string		`json:"local_address"`
+    LocalCIDR		string		`json:"local_cidr"`
+    PrivateNetwork	ID		`json:"private_network"`
+    Status		string		`json:"status"`
+    User		StringMap	`json:"user"`
+}
+
+// VPNConnection is the IPsec site connection of a VPN service.
+type VPNConnection struct {
+    DPDAction	string		`json:"dpd_action"`
+    DPDInterval	int		`json:"dpd_interval"`
+    DPDTimeout	int		`json:"dpd_timeout"`
+    Initiator	string		`json:"initiator"`
+    MTU		int		`json:"mtu"`
+    PeerAddress	string		`json:"peer_address"`
+    PeerCIDRs	[]string	`json:"peer_cidrs"`
+    PeerID	string		`json:"peer_id"`
+    Status	string		`json:"status"`
+}
+
+type VPNServiceListOpts struct {
+    Project		string
+    IKEPolicy		string
+    IPSecPolicy		string
+    PrivateNetwork	string
+}
+
+type VPNServiceCreateOpts struct {
+    IKEPolicy		string	`json:"ike_policy"`
+    IPSecPolicy		string	`json:"ipsec_policy"`
+    Name		string	`json:"name"`
+    PrivateNetwork	string	`json:"private_network"`
+}
+
+type VPNConnectionCreateOpts struct {
+    DPDAction	string		`json:"dpd_action,omitempty"`
+    DPDInterval	int		`json:"dpd_interval,omitempty"`
+    DPDTimeout	int		`json:"dpd_timeout,omitempty"`
+    Initiator	string		`json:"initiator,omitempty"`
+    MTU		int		`json:"mtu,omitempty"`
+    PeerAddress	string		`json:"peer_address"`
+    PeerCIDRs	[]string	`json:"peer_cidrs"`
+    PeerID	string		`json:"peer_id,omitempty"`
+    PSK		string		`json:"psk,omitempty"`
+}
+
+// VPNServicesService manages VPN services and their connections.
+type VPNServicesService struct {
+    client	*Client
+}
+
+func vpnServicesPath(platform string) string {
+    return fmt.Sprintf("api/v4/%s/vpn_services/", platform)
+}
+
+func vpnServicePath(platform, id string) string {
+    return fmt.Sprintf("api/v4/%s/vpn_services/%s/", platform, id)
+}
+
+func (s *VPNServicesService) List(ctx context.Context, platform string, opts VPNServiceListOpts) ([]VPNService, error) {
+    var vpns []VPNService
+    path := vpnServicesPath(platform) + query(
+        "project", opts.Project,
+        "ike_policy", opts.IKEPolicy,
+        "ipsec_policy", opts.IPSecPolicy,
+        "private_network", opts.PrivateNetwork)
+    err := s.client.get(ctx, platform, path, &vpns)
+    return vpns, err
+}
+
+func (s *VPNServicesService) Get(ctx context.Context, platform, id string) (*VPNService, error) {
+    var vpn VPNService
+    if err := s.client.get(ctx, platform, vpnServicePath(platform, id), &vpn); err != nil {
+        return nil, err
+    }
+    return &vpn, nil
+}
+
+func (s *VPNServicesService) Create(ctx context.Context, platform string, opts VPNServiceCreateOpts) (*VPNService, error) {
+    var vpn VPNService
+    if err := s.client.do(ctx, platform, vpnServicesPath(platform), "POST", opts, &vpn, nil); err != nil {
+        return nil, err
+    }
+    return &vpn, nil
+}
+
+func (s *VPNServicesService) Delete(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, vpnServicePath(platform, id))
+}
+
+// CreateConnection sets up the connection of a VPN service. The connection
+// shows up in the VPN service once it has been created.
+func (s *VPNServicesService) CreateConnection(ctx context.Context, platform, id string, opts VPNConnectionCreateOpts) error {
+    path := vpnServicePath(platform, id) + "connection/"
+    return s.client.do(ctx, platform, path, "POST", opts, nil, nil)
+}
+
+func (s *VPNServicesService) DeleteConnection(ctx context.Context, platform, id string) error {
+    return s.client.delete(ctx, platform, vpnServicePath(platform, id) + "connection/")
+}
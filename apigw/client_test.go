@@ -0,0 +1,149 @@
+package apigw
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+// newHungServer returns a server which never answers, until the request is
+// cancelled.
+func newHungServer(t *testing.T) *httptest.Server {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        <-r.Context().Done()
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func newTestProviderClient(t *testing.T, url string) *ProviderClient {
+    config := &Config{
+        APIGW_APIKEY:	"secret",
+        APIGW_URL:	url + "/",
+        MaxRetries:	3,
+        RetryWaitMin:	time.Minute,
+        RetryWaitMax:	time.Minute,
+    }
+    if err := config.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+    return config.APIGWClient
+}
+
+func TestRequestTimeout(t *testing.T) {
+    pc := newTestProviderClient(t, newHungServer(t).URL)
+    pc.MaxRetries = 0
+    pc.RequestTimeout = 50 * time.Millisecond
+
+    start := time.Now()
+    if _, err := pc.Request(context.Background(), "platform", "networks/", "GET", nil, nil); err == nil {
+        t.Fatal("expected the request to time out")
+    }
+    if elapsed := time.Since(start); elapsed > 5 * time.Second {
+        t.Errorf("request took %s to time out", elapsed)
+    }
+}
+
+func TestRequestCancelled(t *testing.T) {
+    pc := newTestProviderClient(t, newHungServer(t).URL)
+    ctx, cancel := context.WithTimeout(context.Background(), 50 * time.Millisecond)
+    defer cancel()
+
+    // A cancelled request is not retried, even though GET requests are.
+    start := time.Now()
+    if _, err := pc.Request(ctx, "platform", "networks/", "GET", nil, nil); err == nil {
+        t.Fatal("expected the request to be cancelled")
+    }
+    if elapsed := time.Since(start); elapsed > 5 * time.Second {
+        t.Errorf("request took %s to be cancelled", elapsed)
+    }
+}
+
+func TestRequestLimiterCancelled(t *testing.T) {
+    limiter := newRequestLimiter(0, 1)
+    release, err := limiter.acquire(context.Background(), "platform")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer release()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 50 * time.Millisecond)
+    defer cancel()
+    if _, err := limiter.acquire(ctx, "platform"); err != context.DeadlineExceeded {
+        t.Errorf("acquire = %v, want %v", err, context.DeadlineExceeded)
+    }
+}
+
+func TestRequestUserAgent(t *testing.T) {
+    var got string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        got = r.Header.Get("User-Agent")
+        w.Write([]byte("[]"))
+    }))
+    defer server.Close()
+
+    cases := []struct {
+        terraformVersion	string
+        extra			string
+        want			string
+    }{
+        {"0.13.5", "", "terraform-provider-apigw/dev terraform/0.13.5"},
+        {"0.13.5", " ci-pipeline/42 ", "terraform-provider-apigw/dev terraform/0.13.5 ci-pipeline/42"},
+        {"", "", "terraform-provider-apigw/dev"},
+    }
+
+    for _, c := range cases {
+        config := &Config{
+            APIGW_APIKEY:	"secret",
+            APIGW_URL:		server.URL + "/",
+            TerraformVersion:	c.terraformVersion,
+            UserAgentExtra:	c.extra,
+        }
+        if err := config.LoadAndValidate(); err != nil {
+            t.Fatal(err)
+        }
+        if _, err := config.APIGWClient.Request(context.Background(), "platform", "networks/", "GET", nil, nil); err != nil {
+            t.Fatal(err)
+        }
+        if got != c.want {
+            t.Errorf("User-Agent = %q, want %q", got, c.want)
+        }
+    }
+}
+
+func TestRequestRetriesIdempotentCreates(t *testing.T) {
+    var attempts int
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        attempts++
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    defer server.Close()
+
+    key := map[string]string{"Idempotency-Key": "key"}
+    cases := []struct {
+        name		string
+        retryCreates	bool
+        headers		map[string]string
+        attempts	int
+    }{
+        {"without key", true, nil, 1},
+        {"with key", false, key, 1},
+        {"with key retried", true, key, 4},
+    }
+
+    for _, c := range cases {
+        pc := newTestProviderClient(t, server.URL)
+        pc.RetryWaitMin = time.Millisecond
+        pc.RetryWaitMax = time.Millisecond
+        pc.RetryIdempotentCreates = c.retryCreates
+        attempts = 0
+        if _, err := pc.Request(context.Background(), "platform", "networks/", "POST", []byte("{}"), c.headers); err == nil {
+            t.Fatalf("%s: expected an error", c.name)
+        }
+        if attempts != c.attempts {
+            t.Errorf("%s: %d attempts, want %d", c.name, attempts, c.attempts)
+        }
+    }
+}
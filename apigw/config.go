@@ -1,47 +1,196 @@
 package apigw
 
 import (
-    "bytes"
+    "crypto/tls"
+    "crypto/x509"
     "fmt"
+    "io/ioutil"
+    "log"
+    "strings"
+    "time"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 type Config struct {
+    Profile		string
+    CredentialsFile	string
     APIGW_APIKEY	string
     APIGW_URL		string
+    CACertFile		string
+    ClientCertFile	string
+    ClientKeyFile	string
+    Insecure		bool
+    // InsecureSet is whether Insecure was set in the provider block or the
+    // environment, so that it takes precedence over the profile.
+    InsecureSet		bool
+    MaxRetries		int
+    RetryWaitMin	time.Duration
+    RetryWaitMax	time.Duration
+    RetryIdempotentCreates	bool
+    RequestTimeout	time.Duration
+    RequestsPerSecond	float64
+    MaxConcurrentRequests	int
+    HTTPDebug		bool
+    PollInterval	time.Duration
+    PollMinTimeout	time.Duration
+    DefaultPlatform	string
+    DefaultProject	string
+    TerraformVersion	string
+    UserAgentExtra	string
+    RecordFile		string
+    ReplayFile		string
+    AuditLogPath	string
 
     APIGWClient		*ProviderClient
+    API			*client.Client
 }
 
+// LoadAndValidate resolves each setting from, in order of precedence, the
+// provider block, the environment and the selected profile of the
+// credentials file, and builds the API client.
 func (c *Config) LoadAndValidate() error {
+    if err := c.loadProfile(); err != nil {
+        return err
+    }
+
     if c.APIGW_APIKEY == "" {
-        return fmt.Errorf("'APIGW_APIKEY' must be specified")
+        return fmt.Errorf("'APIGW_APIKEY' must be specified, in the provider, the environment or a profile")
     }
 
     if c.APIGW_URL == "" {
-        return fmt.Errorf("'APIGW_URL' must be specified")
+        return fmt.Errorf("'APIGW_URL' must be specified, in the provider, the environment or a profile")
+    }
+
+    if c.MaxRetries < 0 {
+        return fmt.Errorf("'max_retries' must not be negative")
+    }
+
+    if c.RetryWaitMin > c.RetryWaitMax {
+        return fmt.Errorf("'retry_wait_min' must not be greater than 'retry_wait_max'")
+    }
+
+    if c.RequestTimeout < 0 {
+        return fmt.Errorf("'request_timeout' must not be negative")
+    }
+
+    if c.RequestsPerSecond < 0 {
+        return fmt.Errorf("'requests_per_second' must not be negative")
+    }
+
+    if c.MaxConcurrentRequests < 0 {
+        return fmt.Errorf("'max_concurrent_requests' must not be negative")
+    }
+
+    if c.PollInterval < 0 {
+        return fmt.Errorf("'poll_interval' must not be negative")
     }
 
-    client := newProviderClient(c.APIGW_APIKEY, c.APIGW_URL)
-    c.APIGWClient = client
+    if c.PollMinTimeout < 0 {
+        return fmt.Errorf("'poll_min_timeout' must not be negative")
+    }
+
+    if c.RecordFile != "" && c.ReplayFile != "" {
+        return fmt.Errorf("'APIGW_RECORD' and 'APIGW_REPLAY' must not be set together")
+    }
+
+    tlsConfig, err := c.tlsConfig()
+    if err != nil {
+        return err
+    }
+
+    pc := newProviderClient(c.APIGW_APIKEY, c.APIGW_URL, tlsConfig)
+    pc.MaxRetries = c.MaxRetries
+    pc.RetryWaitMin = c.RetryWaitMin
+    pc.RetryWaitMax = c.RetryWaitMax
+    pc.RetryIdempotentCreates = c.RetryIdempotentCreates
+    pc.RequestTimeout = c.RequestTimeout
+    pc.Debug = c.HTTPDebug
+    pc.UserAgent = userAgent(c.TerraformVersion, c.UserAgentExtra)
+    if c.RequestsPerSecond > 0 || c.MaxConcurrentRequests > 0 {
+        pc.limiter = newRequestLimiter(c.RequestsPerSecond, c.MaxConcurrentRequests)
+    }
+    if c.RecordFile != "" {
+        log.Printf("[WARN] Recording the requests to %s in %s", c.APIGW_URL, c.RecordFile)
+        transport, err := newRecordingTransport(pc.HTTPClient.Transport, c.RecordFile, c.APIGW_URL)
+        if err != nil {
+            return err
+        }
+        pc.HTTPClient.Transport = transport
+    }
+    if c.ReplayFile != "" {
+        log.Printf("[WARN] Replaying the requests to %s from %s", c.APIGW_URL, c.ReplayFile)
+        transport, err := newReplayingTransport(c.ReplayFile, c.APIGW_URL)
+        if err != nil {
+            return err
+        }
+        pc.HTTPClient.Transport = transport
+    }
+    if c.AuditLogPath != "" {
+        if pc.auditLog, err = openAuditLog(c.AuditLogPath); err != nil {
+            return err
+        }
+    }
+    c.APIGWClient = pc
+    c.API = client.New(pc)
 
     return nil
 }
 
-func (c *Config) doNormalRequest (
-        resourceHost string,
-        resourcePath string,
-        method string,
-        body *bytes.Buffer) (string, error) {
-    response, err := c.APIGWClient.doRequest(resourceHost, resourcePath, method, body, nil)
-    return response, err
+// tlsConfig builds the TLS settings used to talk to the gateway. Server
+// certificates are verified against the system roots plus CACertFile unless
+// Insecure is set.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+    tlsConfig := &tls.Config{}
+
+    if c.CACertFile != "" {
+        caCert, err := ioutil.ReadFile(c.CACertFile)
+        if err != nil {
+            return nil, fmt.Errorf("Error reading 'cacert_file' %s: %v", c.CACertFile, err)
+        }
+
+        pool, err := x509.SystemCertPool()
+        if err != nil || pool == nil {
+            pool = x509.NewCertPool()
+        }
+
+        if ok := pool.AppendCertsFromPEM(caCert); !ok {
+            return nil, fmt.Errorf("No PEM certificates found in 'cacert_file' %s", c.CACertFile)
+        }
+
+        tlsConfig.RootCAs = pool
+    }
+
+    if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+        if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+            return nil, fmt.Errorf("'client_cert' and 'client_key' must be specified together")
+        }
+
+        cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+        if err != nil {
+            return nil, fmt.Errorf("Error loading client certificate: %v", err)
+        }
+
+        tlsConfig.Certificates = []tls.Certificate{cert}
+    }
+
+    if c.Insecure {
+        log.Printf("[WARN] TLS certificate verification of %s is disabled", c.APIGW_URL)
+        tlsConfig.InsecureSkipVerify = true
+    }
+
+    return tlsConfig, nil
 }
 
-func (c *Config) doCreateSiteRequest (
-        resourceHost string,
-        resourcePath string,
-        method string,
-        body *bytes.Buffer,
-        headers map[string]string) (string, error) {
-    response, err := c.APIGWClient.doRequest(resourceHost, resourcePath, method, body, headers)
-    return response, err
+// userAgent returns the User-Agent header identifying the provider and the
+// terraform running it, if known, followed by extra.
+func userAgent(terraformVersion string, extra string) string {
+    ua := fmt.Sprintf("terraform-provider-apigw/%s", ProviderVersion)
+    if terraformVersion != "" {
+        ua += fmt.Sprintf(" terraform/%s", terraformVersion)
+    }
+    if extra = strings.TrimSpace(extra); extra != "" {
+        ua += " " + extra
+    }
+    return ua
 }
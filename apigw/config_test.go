@@ -0,0 +1,109 @@
+package apigw
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "encoding/pem"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+
+// testTLSConfig returns a provider config for url which ignores the
+// credentials file of the user running the tests.
+func testTLSConfig(t *testing.T, url string) *Config {
+    t.Setenv("HOME", t.TempDir())
+    return &Config{
+        APIGW_APIKEY:	"key",
+        APIGW_URL:	url,
+    }
+}
+
+// testWriteFile writes content into a file of a temporary directory and
+// returns its path.
+func testWriteFile(t *testing.T, name string, content string) string {
+    path := filepath.Join(t.TempDir(), name)
+    if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestTLSConfigSelfSigned(t *testing.T) {
+    server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer server.Close()
+
+    c := testTLSConfig(t, server.URL)
+    if err := c.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+
+    _, err := c.APIGWClient.HTTPClient.Get(server.URL)
+    var unknownAuthority x509.UnknownAuthorityError
+    if !errors.As(err, &unknownAuthority) {
+        t.Fatalf("request to a self-signed server = %v, want an unknown authority error", err)
+    }
+}
+
+func TestTLSConfigCACertFile(t *testing.T) {
+    server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer server.Close()
+
+    c := testTLSConfig(t, server.URL)
+    c.CACertFile = testWriteFile(t, "ca.pem", string(pem.EncodeToMemory(&pem.Block{
+        Type:	"CERTIFICATE",
+        Bytes:	server.Certificate().Raw,
+    })))
+    if err := c.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+
+    resp, err := c.APIGWClient.HTTPClient.Get(server.URL)
+    if err != nil {
+        t.Fatalf("request with the server certificate as cacert_file = %v", err)
+    }
+    resp.Body.Close()
+}
+
+func TestTLSConfigClientCertificate(t *testing.T) {
+    certPEM, keyPEM := testCertificatePEM(t, "client", time.Now().Add(time.Hour))
+    _, otherKeyPEM := testCertificatePEM(t, "other", time.Now().Add(time.Hour))
+
+    var presented []*x509.Certificate
+    server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        presented = r.TLS.PeerCertificates
+    }))
+    server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+    server.StartTLS()
+    defer server.Close()
+
+    c := testTLSConfig(t, server.URL)
+    c.Insecure = true
+    c.ClientCertFile = testWriteFile(t, "client.crt", certPEM)
+    c.ClientKeyFile = testWriteFile(t, "client.key", keyPEM)
+    if err := c.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+
+    resp, err := c.APIGWClient.HTTPClient.Get(server.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    resp.Body.Close()
+    if len(presented) != 1 || presented[0].Subject.CommonName != "client" {
+        t.Errorf("presented client certificates = %v, want the one of client", presented)
+    }
+
+    // A key which does not belong to the certificate is rejected up front.
+    c = testTLSConfig(t, server.URL)
+    c.ClientCertFile = testWriteFile(t, "client.crt", certPEM)
+    c.ClientKeyFile = testWriteFile(t, "other.key", otherKeyPEM)
+    if err := c.LoadAndValidate(); err == nil || !strings.Contains(err.Error(), "Error loading client certificate") {
+        t.Errorf("LoadAndValidate with a mismatched client key = %v, want an error loading the client certificate", err)
+    }
+}
@@ -0,0 +1,173 @@
+package apigw
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+// defaultProfile is the profile used when none is selected.
+const defaultProfile = "default"
+
+// Profile holds the settings of a named profile of the credentials file.
+type Profile struct {
+    APIKey		string
+    URL			string
+    CACertFile		string
+    ClientCertFile	string
+    ClientKeyFile	string
+    Insecure		bool
+}
+
+// defaultCredentialsFile returns ~/.apigw/credentials, or "" if the home
+// directory is unknown.
+func defaultCredentialsFile() string {
+    home, err := os.UserHomeDir()
+    if err != nil {
+        return ""
+    }
+    return filepath.Join(home, ".apigw", "credentials")
+}
+
+// expandHome replaces a leading ~ of path by the home directory.
+func expandHome(path string) string {
+    if path != "~" && !strings.HasPrefix(path, "~/") {
+        return path
+    }
+
+    home, err := os.UserHomeDir()
+    if err != nil {
+        return path
+    }
+    return filepath.Join(home, path[1:])
+}
+
+// readProfiles parses an INI style credentials file of the form
+//
+//   [default]
+//   apikey    = <APIKEY>
+//   apigw_url = <APIGW_URL>
+//
+// Lines starting with # or ; are comments.
+func readProfiles(path string) (map[string]*Profile, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    profiles := make(map[string]*Profile)
+    var profile *Profile
+    scanner := bufio.NewScanner(f)
+    for n := 1; scanner.Scan(); n++ {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+            continue
+        }
+
+        if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+            name := strings.TrimSpace(line[1 : len(line)-1])
+            if name == "" {
+                return nil, fmt.Errorf("Empty profile name in %s line %d", path, n)
+            }
+            profile = &Profile{}
+            profiles[name] = profile
+            continue
+        }
+
+        parts := strings.SplitN(line, "=", 2)
+        if len(parts) != 2 {
+            return nil, fmt.Errorf("Expected key = value in %s line %d", path, n)
+        }
+        if profile == nil {
+            return nil, fmt.Errorf("Setting outside of a profile in %s line %d", path, n)
+        }
+
+        key := strings.TrimSpace(parts[0])
+        value := strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+        switch key {
+        case "apikey":
+            profile.APIKey = value
+        case "apigw_url":
+            profile.URL = value
+        case "cacert_file":
+            profile.CACertFile = expandHome(value)
+        case "client_cert":
+            profile.ClientCertFile = expandHome(value)
+        case "client_key":
+            profile.ClientKeyFile = expandHome(value)
+        case "insecure":
+            insecure, err := strconv.ParseBool(value)
+            if err != nil {
+                return nil, fmt.Errorf("Invalid insecure %q in %s line %d", value, path, n)
+            }
+            profile.Insecure = insecure
+        default:
+            return nil, fmt.Errorf("Unknown setting %q in %s line %d", key, path, n)
+        }
+    }
+
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    return profiles, nil
+}
+
+// loadProfile fills the settings which are neither set in the provider
+// block nor in the environment from the selected profile of the credentials
+// file. A missing credentials file is only an error if a profile or file was
+// chosen explicitly.
+func (c *Config) loadProfile() error {
+    explicit := c.Profile != "" || c.CredentialsFile != ""
+
+    name := c.Profile
+    if name == "" {
+        name = defaultProfile
+    }
+
+    path := expandHome(c.CredentialsFile)
+    if path == "" {
+        path = defaultCredentialsFile()
+        if path == "" {
+            return nil
+        }
+    }
+
+    profiles, err := readProfiles(path)
+    if err != nil {
+        if os.IsNotExist(err) && !explicit {
+            return nil
+        }
+        return fmt.Errorf("Error reading credentials file %s: %v", path, err)
+    }
+
+    profile, ok := profiles[name]
+    if !ok {
+        if !explicit {
+            return nil
+        }
+        return fmt.Errorf("Profile %q not found in credentials file %s", name, path)
+    }
+
+    if c.APIGW_APIKEY == "" {
+        c.APIGW_APIKEY = profile.APIKey
+    }
+    if c.APIGW_URL == "" {
+        c.APIGW_URL = profile.URL
+    }
+    if c.CACertFile == "" {
+        c.CACertFile = profile.CACertFile
+    }
+    if c.ClientCertFile == "" && c.ClientKeyFile == "" {
+        c.ClientCertFile = profile.ClientCertFile
+        c.ClientKeyFile = profile.ClientKeyFile
+    }
+    if !c.InsecureSet {
+        c.Insecure = profile.Insecure
+    }
+
+    return nil
+}
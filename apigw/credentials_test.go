@@ -0,0 +1,249 @@
+package apigw
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// testCredentialsFile writes content as credentials file into a temporary
+// directory and returns its path.
+func testCredentialsFile(t *testing.T, content string) string {
+    path := filepath.Join(t.TempDir(), "credentials")
+    if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestReadProfiles(t *testing.T) {
+    home, err := os.UserHomeDir()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    path := testCredentialsFile(t, `
+# comment
+; comment
+[default]
+apikey    = key
+apigw_url = "https://apigw.example.com/"
+
+[ staging ]
+apikey      = 'staging key'
+apigw_url   = https://staging.example.com/
+cacert_file = ~/.apigw/ca.pem
+client_cert = /etc/apigw/client.crt
+client_key  = /etc/apigw/client.key
+insecure    = true
+`)
+
+    profiles, err := readProfiles(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    want := map[string]*Profile{
+        "default": {
+            APIKey:	"key",
+            URL:	"https://apigw.example.com/",
+        },
+        "staging": {
+            APIKey:		"staging key",
+            URL:		"https://staging.example.com/",
+            CACertFile:		filepath.Join(home, ".apigw", "ca.pem"),
+            ClientCertFile:	"/etc/apigw/client.crt",
+            ClientKeyFile:	"/etc/apigw/client.key",
+            Insecure:		true,
+        },
+    }
+    if !reflect.DeepEqual(profiles, want) {
+        t.Errorf("readProfiles = %+v, want %+v", profiles, want)
+    }
+}
+
+func TestReadProfilesErrors(t *testing.T) {
+    cases := []struct {
+        name		string
+        content		string
+        err		string
+    }{
+        {"empty name", "[]\napikey = key\n", "Empty profile name in"},
+        {"no value", "[default]\napikey\n", "Expected key = value in"},
+        {"outside profile", "apikey = key\n", "Setting outside of a profile in"},
+        {"unknown setting", "[default]\napi_key = key\n", `Unknown setting "api_key" in`},
+        {"invalid insecure", "[default]\ninsecure = maybe\n", `Invalid insecure "maybe" in`},
+    }
+
+    for _, c := range cases {
+        _, err := readProfiles(testCredentialsFile(t, c.content))
+        if err == nil || !strings.Contains(err.Error(), c.err) {
+            t.Errorf("%s: readProfiles = %v, want an error containing %q", c.name, err, c.err)
+        }
+    }
+
+    if _, err := readProfiles(filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+        t.Errorf("readProfiles of a missing file = %v, want it not to exist", err)
+    }
+}
+
+func TestLoadProfile(t *testing.T) {
+    path := testCredentialsFile(t, `
+[default]
+apikey    = key
+apigw_url = https://apigw.example.com/
+
+[staging]
+apikey      = staging key
+apigw_url   = https://staging.example.com/
+client_cert = client.crt
+client_key  = client.key
+insecure    = true
+`)
+    missing := filepath.Join(t.TempDir(), "missing")
+
+    cases := []struct {
+        name		string
+        config		Config
+        want		Config
+        err		string
+    }{
+        {
+            name:	"default profile",
+            config:	Config{CredentialsFile: path},
+            want: Config{
+                CredentialsFile:	path,
+                APIGW_APIKEY:		"key",
+                APIGW_URL:		"https://apigw.example.com/",
+            },
+        },
+        {
+            name:	"named profile",
+            config:	Config{CredentialsFile: path, Profile: "staging"},
+            want: Config{
+                CredentialsFile:	path,
+                Profile:		"staging",
+                APIGW_APIKEY:		"staging key",
+                APIGW_URL:		"https://staging.example.com/",
+                ClientCertFile:		"client.crt",
+                ClientKeyFile:		"client.key",
+                Insecure:		true,
+            },
+        },
+        {
+            name: "provider settings take precedence",
+            config: Config{
+                CredentialsFile:	path,
+                Profile:		"staging",
+                APIGW_APIKEY:		"provider key",
+                ClientKeyFile:		"provider.key",
+                InsecureSet:		true,
+            },
+            want: Config{
+                CredentialsFile:	path,
+                Profile:		"staging",
+                APIGW_APIKEY:		"provider key",
+                APIGW_URL:		"https://staging.example.com/",
+                ClientKeyFile:		"provider.key",
+                InsecureSet:		true,
+            },
+        },
+        {
+            name:	"missing profile",
+            config:	Config{CredentialsFile: path, Profile: "production"},
+            err:	`Profile "production" not found in credentials file`,
+        },
+        {
+            name:	"missing file",
+            config:	Config{CredentialsFile: missing},
+            err:	"Error reading credentials file",
+        },
+    }
+
+    for _, c := range cases {
+        config := c.config
+        err := config.loadProfile()
+        switch {
+        case c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)):
+            t.Errorf("%s: loadProfile = %v, want an error containing %q", c.name, err, c.err)
+        case c.err == "" && err != nil:
+            t.Errorf("%s: loadProfile = %v", c.name, err)
+        case c.err == "" && !reflect.DeepEqual(config, c.want):
+            t.Errorf("%s: loadProfile = %+v, want %+v", c.name, config, c.want)
+        }
+    }
+}
+
+func TestLoadProfileDefaultFile(t *testing.T) {
+    home := t.TempDir()
+    t.Setenv("HOME", home)
+
+    // Neither the default file nor its default profile are required.
+    config := Config{APIGW_APIKEY: "key"}
+    if err := config.loadProfile(); err != nil || config.APIGW_URL != "" {
+        t.Errorf("loadProfile without a credentials file = %v, %+v", err, config)
+    }
+
+    if err := os.MkdirAll(filepath.Join(home, ".apigw"), 0700); err != nil {
+        t.Fatal(err)
+    }
+    err := os.WriteFile(filepath.Join(home, ".apigw", "credentials"),
+        []byte("[staging]\napigw_url = https://staging.example.com/\n"), 0600)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := config.loadProfile(); err != nil || config.APIGW_URL != "" {
+        t.Errorf("loadProfile without a default profile = %v, %+v", err, config)
+    }
+
+    config.Profile = "staging"
+    if err := config.loadProfile(); err != nil || config.APIGW_URL != "https://staging.example.com/" {
+        t.Errorf("loadProfile of the default file = %v, %+v", err, config)
+    }
+}
+
+func TestProviderInsecurePrecedence(t *testing.T) {
+    path := testCredentialsFile(t, `
+[default]
+apikey    = key
+apigw_url = https://apigw.example.com/
+insecure  = true
+`)
+
+    cases := []struct {
+        name		string
+        raw		map[string]interface{}
+        env		string
+        insecure	bool
+    }{
+        {"profile", map[string]interface{}{}, "", true},
+        {"provider", map[string]interface{}{"insecure": false}, "", false},
+        {"environment", map[string]interface{}{}, "false", false},
+    }
+
+    for _, c := range cases {
+        t.Setenv("APIGW_INSECURE", c.env)
+        if c.env == "" {
+            os.Unsetenv("APIGW_INSECURE")
+        }
+        t.Setenv("APIGW_APIKEY", "")
+        t.Setenv("APIGW_URL", "")
+
+        raw := map[string]interface{}{"credentials_file": path}
+        for k, v := range c.raw {
+            raw[k] = v
+        }
+        p := Provider().(*schema.Provider)
+        if err := p.Configure(terraform.NewResourceConfigRaw(raw)); err != nil {
+            t.Fatalf("%s: %v", c.name, err)
+        }
+        if insecure := p.Meta().(*PConfig).Insecure; insecure != c.insecure {
+            t.Errorf("%s: insecure = %t, want %t", c.name, insecure, c.insecure)
+        }
+    }
+}
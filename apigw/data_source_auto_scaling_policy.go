@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceAutoScalingPolicy() * schema.Resource {
@@ -70,28 +71,26 @@ func dataSourceAutoScalingPolicy() * schema.Resource {
 // dataSourceAutoScalingPolicyRead performs the auto scaling policy lookup.
 func dataSourceAutoScalingPolicyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/auto_scaling_policies/?project=%s&name=%s", platform, projectID, name)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
-
-    if err != nil {
-        return fmt.Errorf("Unable to list auto scaling policies: %v", err)
+    opts := client.AutoScalingPolicyListOpts{
+        Name:		name,
+        Project:	projectID,
     }
-
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    policies, err := config.API.AutoScalingPolicies.List(ctx, platform, opts)
 
     if err != nil {
-        return err
+        return fmt.Errorf("Unable to list auto scaling policies: %v", err)
     }
 
-    for _, auto_scaling_policy := range data {
-        if auto_scaling_policy["name"] == name {
-            return dataSourceAutoScalingPolicyAttributes(d, auto_scaling_policy)
+    for i := range policies {
+        if policies[i].Name == name {
+            return dataSourceAutoScalingPolicyAttributes(d, &policies[i])
         }
     }
 
@@ -99,17 +98,16 @@ func dataSourceAutoScalingPolicyRead(d *schema.ResourceData, meta interface{}) e
 }
 
 // dataSourceAutoScalingPolicyAttributes populates the fields of a auto scaling policy data source.
-func dataSourceAutoScalingPolicyAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    auto_scaling_policy_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_auto_scaling_policy: %d", auto_scaling_policy_id)
-
-    d.SetId(fmt.Sprintf("%d", auto_scaling_policy_id))
-    d.Set("meter_name", data["meter_name"])
-    d.Set("description", data["description"])
-    d.Set("scale_max_size", data["scale_max_size"])
-    d.Set("scaledown_threshold", data["scaledown_threshold"])
-    d.Set("scaleup_threshold", data["scaleup_threshold"])
-    d.Set("user", data["user"])
+func dataSourceAutoScalingPolicyAttributes(d *schema.ResourceData, policy *client.AutoScalingPolicy) error {
+    log.Printf("[DEBUG] Retrieved apigw_auto_scaling_policy: %s", policy.ID)
+
+    d.SetId(policy.ID.String())
+    d.Set("meter_name", policy.MeterName)
+    d.Set("description", policy.Description)
+    d.Set("scale_max_size", policy.ScaleMaxSize)
+    d.Set("scaledown_threshold", policy.ScaledownThreshold)
+    d.Set("scaleup_threshold", policy.ScaleupThreshold)
+    d.Set("user", policy.User)
 
     return nil
 }
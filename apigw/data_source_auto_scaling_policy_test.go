@@ -0,0 +1,33 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccAutoScalingPolicyDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccAutoScalingPolicyConfig(name) + `
+data "apigw_auto_scaling_policy" "test" {
+  name = apigw_auto_scaling_policy.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_auto_scaling_policy.test", "id", "apigw_auto_scaling_policy.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_auto_scaling_policy.test", "meter_name", "apigw_auto_scaling_policy.test", "meter_name"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_auto_scaling_policy.test", "scale_max_size", "apigw_auto_scaling_policy.test", "scale_max_size"),
+                ),
+            },
+        },
+    })
+}
@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceContainer() * schema.Resource {
@@ -213,46 +214,34 @@ func dataSourceContainer() * schema.Resource {
 // dataSourceContainerRead performs the container lookup.
 func dataSourceContainerRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     projectID := d.Get("project").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/?project=%s&name=%s", platform, projectID, name)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    sites, err := config.API.Sites.List(ctx, platform, client.SiteListOpts{Project: projectID, Name: name})
 
     if err != nil {
         return fmt.Errorf("Unable to list containers: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    var site_id int
-    var siteInfo, containerInfo map[string]interface{}
-    for _, site := range data {
-        if site["name"] == name {
-            if site_id != 0 {
+    var siteInfo *client.Site
+    for i := range sites {
+        if sites[i].Name == name {
+            if siteInfo != nil {
                 return fmt.Errorf("There are duplicated containers with name '%s'", name)
             }
-            site_id = int(site["id"].(float64))
-            siteInfo = site
+            siteInfo = &sites[i]
         }
     }
-    if site_id != 0 {
-        resourcePath := fmt.Sprintf("api/v4/%s/sites/%d/container/", platform, site_id)
-        response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    if siteInfo != nil {
+        containerInfo, err := config.API.Sites.GetContainer(ctx, platform, siteInfo.ID.String())
         if err != nil {
             return fmt.Errorf("Unable to retrieve container: %v", err)
         }
 
-        if err = json.Unmarshal([]byte(response), &containerInfo); err != nil {
-            return err
-        }
         return dataSourceContainerAttributes(d, siteInfo, containerInfo)
     }
 
@@ -260,18 +249,17 @@ func dataSourceContainerRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceContainerAttributes populates the fields of a container data source.
-func dataSourceContainerAttributes(d *schema.ResourceData, siteInfo map[string]interface{}, containerInfo map[string]interface{}) error {
-    site_id := int(siteInfo["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_container: %d", site_id)
-
-    d.SetId(fmt.Sprintf("%d", site_id))
-    d.Set("create_time", siteInfo["create_time"])
-    d.Set("public_ip", siteInfo["public_ip"])
-    d.Set("solution", fmt.Sprintf("%d", int(siteInfo["solution"].(float64))))
-    d.Set("pod", flattenSitePodInfo(containerInfo["Pod"].([]interface{})))
-    d.Set("service", flattenSiteServiceInfo(containerInfo["Service"].([]interface{})))
-    d.Set("status", siteInfo["status"])
-    d.Set("user", siteInfo["user"])
+func dataSourceContainerAttributes(d *schema.ResourceData, siteInfo *client.Site, containerInfo *client.Container) error {
+    log.Printf("[DEBUG] Retrieved apigw_container: %s", siteInfo.ID)
+
+    d.SetId(siteInfo.ID.String())
+    d.Set("create_time", siteInfo.CreateTime)
+    d.Set("public_ip", siteInfo.PublicIP)
+    d.Set("solution", siteInfo.Solution.String())
+    d.Set("pod", flattenSitePodInfo(containerInfo.Pods))
+    d.Set("service", flattenSiteServiceInfo(containerInfo.Services))
+    d.Set("status", siteInfo.Status)
+    d.Set("user", siteInfo.User)
 
     return nil
 }
@@ -0,0 +1,32 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccContainerDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_CONTAINER_SOLUTION") },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccContainerConfig(name) + `
+data "apigw_container" "test" {
+  name = apigw_container.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair("data.apigw_container.test", "id", "apigw_container.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_container.test", "solution", "apigw_container.test", "solution"),
+                    resource.TestCheckResourceAttr("data.apigw_container.test", "status", "Ready"),
+                    resource.TestCheckResourceAttr("data.apigw_container.test", "pod.#", "1"),
+                ),
+            },
+        },
+    })
+}
@@ -6,6 +6,8 @@ import (
     "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceExtraProperty() * schema.Resource {
@@ -42,38 +44,33 @@ func dataSourceExtraProperty() * schema.Resource {
 // dataSourceExtraPropertyRead performs the extra property lookup.
 func dataSourceExtraPropertyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
     solution := d.Get("solution").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/projects/%s/solutions/%s/", platform, project, solution)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    data, err := config.API.Solutions.GetProjectSolution(ctx, platform, project, solution)
 
     if err != nil {
         return fmt.Errorf("Unable to retrieve extra property: %v", err)
     }
 
-    var data map[string]interface{}
-    if err = json.Unmarshal([]byte(response), &data); err != nil {
-        return err
-    }
-
     // remove volume-size & volume-type
-    delete(data["site_extra_prop"].(map[string]interface{}), "volume-size")
-    delete(data["site_extra_prop"].(map[string]interface{}), "volume-type")
+    delete(data.SiteExtraProp, "volume-size")
+    delete(data.SiteExtraProp, "volume-type")
     return dataSourceExtraPropertyAttributes(d, data)
 }
 
 // dataSourceExtraPropertyAttributes populates the fields of a extra property data source.
-func dataSourceExtraPropertyAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    solution_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_extra_property: %d", solution_id)
+func dataSourceExtraPropertyAttributes(d *schema.ResourceData, data *client.ProjectSolution) error {
+    log.Printf("[DEBUG] Retrieved apigw_extra_property: %s", data.ID)
 
-    json_data, err := json.Marshal(data["site_extra_prop"])
+    json_data, err := json.Marshal(data.SiteExtraProp)
     if err != nil {
         return err
     }
-    d.SetId(fmt.Sprintf("%d", solution_id))
+    d.SetId(data.ID.String())
     d.Set("extra_property", string(json_data))
 
     return nil
@@ -0,0 +1,30 @@
+package apigw
+
+import (
+    "fmt"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccExtraPropertyDataSource_basic(t *testing.T) {
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccSolutionConfig("vcs", "APIGW_TEST_VCS_SOLUTION") + fmt.Sprintf(`
+data "apigw_extra_property" "test" {
+  platform = %q
+  solution = data.apigw_solution.vcs.id
+}
+`, testAccPlatform()),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_extra_property.test", "id", "data.apigw_solution.vcs", "id"),
+                    resource.TestCheckResourceAttrSet("data.apigw_extra_property.test", "extra_property"),
+                ),
+            },
+        },
+    })
+}
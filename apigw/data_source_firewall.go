@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceFirewall() * schema.Resource {
@@ -55,28 +56,22 @@ func dataSourceFirewall() * schema.Resource {
 // dataSourceFirewallRead performs the firewall lookup.
 func dataSourceFirewallRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/firewalls/?project=%s", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    firewalls, err := config.API.Firewalls.List(ctx, platform, client.FirewallListOpts{Project: projectID})
 
     if err != nil {
         return fmt.Errorf("Unable to list firewalls: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    for _, firewall := range data {
-        if firewall["name"] == name {
-            return dataSourceFirewallAttributes(d, firewall)
+    for i := range firewalls {
+        if firewalls[i].Name == name {
+            return dataSourceFirewallAttributes(d, &firewalls[i])
         }
     }
 
@@ -84,17 +79,16 @@ func dataSourceFirewallRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceFirewallAttributes populates the fields of a firewall data source.
-func dataSourceFirewallAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    firewall_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_firewall: %d", firewall_id)
-
-    d.SetId(fmt.Sprintf("%d", firewall_id))
-    d.Set("project", fmt.Sprintf("%v", data["project"]))
-    d.Set("status", data["status"])
-    d.Set("user", data["user"])
-    d.Set("name", data["name"])
-    d.Set("desc", data["desc"])
-    d.Set("platform", data["platform"])
+func dataSourceFirewallAttributes(d *schema.ResourceData, firewall *client.Firewall) error {
+    log.Printf("[DEBUG] Retrieved apigw_firewall: %s", firewall.ID)
+
+    d.SetId(firewall.ID.String())
+    d.Set("project", firewall.Project.String())
+    d.Set("status", firewall.Status)
+    d.Set("user", firewall.User)
+    d.Set("name", firewall.Name)
+    d.Set("desc", firewall.Desc)
+    d.Set("platform", firewall.Platform)
 
     return nil
 }
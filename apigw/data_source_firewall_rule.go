@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceFirewallRule() * schema.Resource {
@@ -80,28 +81,22 @@ func dataSourceFirewallRule() * schema.Resource {
 // dataSourceFirewallRuleRead performs the firewall rule lookup.
 func dataSourceFirewallRuleRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/firewall_rules/?project=%s", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    rules, err := config.API.FirewallRules.List(ctx, platform, client.FirewallRuleListOpts{Project: projectID})
 
     if err != nil {
         return fmt.Errorf("Unable to list firewall rules: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    for _, firewall_rule := range data {
-        if firewall_rule["name"] == name {
-            return dataSourceFirewallRuleAttributes(d, firewall_rule)
+    for i := range rules {
+        if rules[i].Name == name {
+            return dataSourceFirewallRuleAttributes(d, &rules[i])
         }
     }
 
@@ -109,22 +104,21 @@ func dataSourceFirewallRuleRead(d *schema.ResourceData, meta interface{}) error
 }
 
 // dataSourceFirewallRuleAttributes populates the fields of a firewall rule data source.
-func dataSourceFirewallRuleAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    firewall_rule_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_firewall_rule: %d", firewall_rule_id)
-
-    d.SetId(fmt.Sprintf("%d", firewall_rule_id))
-    d.Set("project", fmt.Sprintf("%v", data["project"]))
-    d.Set("user", data["user"])
-    d.Set("name", data["name"])
-    d.Set("platform", data["platform"])
-    d.Set("protocol", data["protocol"])
-    d.Set("ip_version", data["ip_version"])
-    d.Set("action", data["action"])
-    d.Set("destination_ip_address", data["destination_ip_address"])
-    d.Set("destination_port", data["destination_port"])
-    d.Set("source_ip_address", data["source_ip_address"])
-    d.Set("source_port", data["source_port"])
+func dataSourceFirewallRuleAttributes(d *schema.ResourceData, rule *client.FirewallRule) error {
+    log.Printf("[DEBUG] Retrieved apigw_firewall_rule: %s", rule.ID)
+
+    d.SetId(rule.ID.String())
+    d.Set("project", rule.Project.String())
+    d.Set("user", rule.User)
+    d.Set("name", rule.Name)
+    d.Set("platform", rule.Platform)
+    d.Set("protocol", rule.Protocol)
+    d.Set("ip_version", rule.IPVersion)
+    d.Set("action", rule.Action)
+    d.Set("destination_ip_address", rule.DestinationIPAddress)
+    d.Set("destination_port", rule.DestinationPort)
+    d.Set("source_ip_address", rule.SourceIPAddress)
+    d.Set("source_port", rule.SourcePort)
 
     return nil
 }
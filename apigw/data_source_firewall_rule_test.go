@@ -0,0 +1,33 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccFirewallRuleDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccFirewallRuleConfig(name, "test", "22") + `
+data "apigw_firewall_rule" "test" {
+  name = apigw_firewall_rule.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_firewall_rule.test", "id", "apigw_firewall_rule.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_firewall_rule.test", "action", "apigw_firewall_rule.test", "action"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_firewall_rule.test", "destination_port", "apigw_firewall_rule.test", "destination_port"),
+                ),
+            },
+        },
+    })
+}
@@ -0,0 +1,31 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccFirewallDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccFirewallConfig(name, "ssh", "[]", "[apigw_firewall_rule.ssh.id]") + `
+data "apigw_firewall" "test" {
+  name = apigw_firewall.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair("data.apigw_firewall.test", "id", "apigw_firewall.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_firewall.test", "desc", "apigw_firewall.test", "desc"),
+                    resource.TestCheckResourceAttr("data.apigw_firewall.test", "status", "INACTIVE"),
+                ),
+            },
+        },
+    })
+}
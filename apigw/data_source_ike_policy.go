@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceIKEPolicy() * schema.Resource {
@@ -69,32 +70,26 @@ func dataSourceIKEPolicy() * schema.Resource {
 // dataSourceIKEPolicyRead performs the IKE policy lookup.
 func dataSourceIKEPolicyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/ike_policies/?project=%s", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    policies, err := config.API.IKEPolicies.List(ctx, platform, client.IKEPolicyListOpts{Project: projectID})
 
     if err != nil {
         return fmt.Errorf("Unable to list IKE policies: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
     found := false
-    for _, ike_policy := range data {
-        if ike_policy["name"] == name {
+    for i := range policies {
+        if policies[i].Name == name {
             if found {
                 return fmt.Errorf("There are duplicated IKE policies with name '%s'", name)
             }
-            err = dataSourceIKEPolicyAttributes(d, ike_policy)
+            err = dataSourceIKEPolicyAttributes(d, &policies[i])
             found = true
         }
     }
@@ -106,17 +101,16 @@ func dataSourceIKEPolicyRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceIKEPolicyAttributes populates the fields of a IKEPolicy data source.
-func dataSourceIKEPolicyAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    ike_policy_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_ike_policy: %d", ike_policy_id)
-
-    d.SetId(fmt.Sprintf("%d", ike_policy_id))
-    d.Set("auth_algorithm", data["auth_algorithm"])
-    d.Set("ike_version", data["ike_version"])
-    d.Set("encryption_algorithm", data["encryption_algorithm"])
-    d.Set("pfs", data["pfs"])
-    d.Set("lifetime", data["lifetime"])
-    d.Set("user", data["user"])
+func dataSourceIKEPolicyAttributes(d *schema.ResourceData, policy *client.IKEPolicy) error {
+    log.Printf("[DEBUG] Retrieved apigw_ike_policy: %s", policy.ID)
+
+    d.SetId(policy.ID.String())
+    d.Set("auth_algorithm", policy.AuthAlgorithm)
+    d.Set("ike_version", policy.IKEVersion)
+    d.Set("encryption_algorithm", policy.EncryptionAlgorithm)
+    d.Set("pfs", policy.PFS)
+    d.Set("lifetime", policy.Lifetime)
+    d.Set("user", policy.User)
 
     return nil
 }
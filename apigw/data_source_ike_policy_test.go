@@ -0,0 +1,33 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccIKEPolicyDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccIKEPolicyConfig(name) + `
+data "apigw_ike_policy" "test" {
+  name = apigw_ike_policy.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_ike_policy.test", "id", "apigw_ike_policy.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_ike_policy.test", "lifetime", "apigw_ike_policy.test", "lifetime"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_ike_policy.test", "auth_algorithm", "apigw_ike_policy.test", "auth_algorithm"),
+                ),
+            },
+        },
+    })
+}
@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceIPSecPolicy() * schema.Resource {
@@ -74,32 +75,26 @@ func dataSourceIPSecPolicy() * schema.Resource {
 // dataSourceIPSecPolicyRead performs the IPSec policy lookup.
 func dataSourceIPSecPolicyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/ipsec_policies/?project=%s", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    policies, err := config.API.IPSecPolicies.List(ctx, platform, client.IPSecPolicyListOpts{Project: projectID})
 
     if err != nil {
         return fmt.Errorf("Unable to list IPSec policies: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
     found := false
-    for _, ipsec_policy := range data {
-        if ipsec_policy["name"] == name {
+    for i := range policies {
+        if policies[i].Name == name {
             if found {
                 return fmt.Errorf("There are duplicated IPSec policies with name '%s'", name)
             }
-            err = dataSourceIPSecPolicyAttributes(d, ipsec_policy)
+            err = dataSourceIPSecPolicyAttributes(d, &policies[i])
             found = true
         }
     }
@@ -111,18 +106,17 @@ func dataSourceIPSecPolicyRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceIPSecPolicyAttributes populates the fields of a IPSecPolicy data source.
-func dataSourceIPSecPolicyAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    ipsec_policy_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_ipsec_policy: %d", ipsec_policy_id)
-
-    d.SetId(fmt.Sprintf("%d", ipsec_policy_id))
-    d.Set("auth_algorithm", data["auth_algorithm"])
-    d.Set("encryption_algorithm", data["encryption_algorithm"])
-    d.Set("encapsulation_mode", data["encapsulation_mode"])
-    d.Set("transform_protocol", data["transform_protocol"])
-    d.Set("pfs", data["pfs"])
-    d.Set("lifetime", data["lifetime"])
-    d.Set("user", data["user"])
+func dataSourceIPSecPolicyAttributes(d *schema.ResourceData, policy *client.IPSecPolicy) error {
+    log.Printf("[DEBUG] Retrieved apigw_ipsec_policy: %s", policy.ID)
+
+    d.SetId(policy.ID.String())
+    d.Set("auth_algorithm", policy.AuthAlgorithm)
+    d.Set("encryption_algorithm", policy.EncryptionAlgorithm)
+    d.Set("encapsulation_mode", policy.EncapsulationMode)
+    d.Set("transform_protocol", policy.TransformProtocol)
+    d.Set("pfs", policy.PFS)
+    d.Set("lifetime", policy.Lifetime)
+    d.Set("user", policy.User)
 
     return nil
 }
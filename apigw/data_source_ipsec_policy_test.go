@@ -0,0 +1,33 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccIPSecPolicyDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccIPSecPolicyConfig(name) + `
+data "apigw_ipsec_policy" "test" {
+  name = apigw_ipsec_policy.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_ipsec_policy.test", "id", "apigw_ipsec_policy.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_ipsec_policy.test", "lifetime", "apigw_ipsec_policy.test", "lifetime"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_ipsec_policy.test", "transform_protocol", "apigw_ipsec_policy.test", "transform_protocol"),
+                ),
+            },
+        },
+    })
+}
@@ -3,10 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
-    "strings"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceLoadBalancer() * schema.Resource {
@@ -82,38 +82,29 @@ func dataSourceLoadBalancer() * schema.Resource {
 // dataSourceLoadBalancerRead performs the loadbalancer lookup.
 func dataSourceLoadBalancerRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
-    params := []string{fmt.Sprintf("name=%s", name)}
-    if project := d.Get("project"); project != "" {
-        params = append(params, fmt.Sprintf("project=%s", project))
-    }
-    if private_net := d.Get("private_net"); private_net != "" {
-        params = append(params, fmt.Sprintf("private_net=%s", private_net))
+    opts := client.LoadBalancerListOpts{
+        Name:		name,
+        Project:	d.Get("project").(string),
+        PrivateNet:	d.Get("private_net").(string),
     }
-    if len(params) < 2 {
+    if opts.Project == "" && opts.PrivateNet == "" {
         return fmt.Errorf("Either project or private_net should be defined")
     }
 
-    resourcePath := fmt.Sprintf("api/v4/%s/loadbalancers/?%s", platform,
-                                strings.Join(params, "&"))
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    lbs, err := config.API.LoadBalancers.List(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Unable to list loadbalancers: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    for _, loadbalancer := range data {
-        if loadbalancer["name"] == name {
-            return dataSourceLoadBalancerAttributes(d, loadbalancer)
+    for i := range lbs {
+        if lbs[i].Name == name {
+            return dataSourceLoadBalancerAttributes(d, &lbs[i])
         }
     }
 
@@ -121,21 +112,18 @@ func dataSourceLoadBalancerRead(d *schema.ResourceData, meta interface{}) error
 }
 
 // dataSourceLoadBalancerAttributes populates the fields of a loadbalancer data source.
-func dataSourceLoadBalancerAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    loadbalancer_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_loadbalancer: %d", loadbalancer_id)
-
-    d.SetId(fmt.Sprintf("%d", loadbalancer_id))
-    d.Set("status", data["status"])
-    d.Set("user", data["user"])
-    d.Set("name", data["name"])
-    d.Set("desc", data["desc"])
-    d.Set("protocol", data["protocol"])
-    d.Set("protocol_port", data["protocol_port"])
-    d.Set("lb_method", data["lb_method"])
-    private_net_info := data["private_net"].(map[string]interface{})
-    private_net := fmt.Sprintf("%d", int(private_net_info["id"].(float64)))
-    d.Set("private_net", private_net)
+func dataSourceLoadBalancerAttributes(d *schema.ResourceData, lb *client.LoadBalancer) error {
+    log.Printf("[DEBUG] Retrieved apigw_loadbalancer: %s", lb.ID)
+
+    d.SetId(lb.ID.String())
+    d.Set("status", lb.Status)
+    d.Set("user", lb.User)
+    d.Set("name", lb.Name)
+    d.Set("desc", lb.Desc)
+    d.Set("protocol", lb.Protocol)
+    d.Set("protocol_port", lb.ProtocolPort)
+    d.Set("lb_method", lb.LBMethod)
+    d.Set("private_net", lb.PrivateNet.String())
 
     return nil
 }
@@ -0,0 +1,35 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccLoadBalancerDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccLoadBalancerConfig(name, "ROUND_ROBIN", "") + `
+data "apigw_loadbalancer" "test" {
+  name        = apigw_loadbalancer.test.name
+  private_net = apigw_loadbalancer.test.private_net
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_loadbalancer.test", "id", "apigw_loadbalancer.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_loadbalancer.test", "lb_method", "apigw_loadbalancer.test", "lb_method"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_loadbalancer.test", "protocol_port", "apigw_loadbalancer.test", "protocol_port"),
+                    resource.TestCheckResourceAttr("data.apigw_loadbalancer.test", "status", "ACTIVE"),
+                ),
+            },
+        },
+    })
+}
@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceNetwork() * schema.Resource {
@@ -91,28 +92,22 @@ func dataSourceNetwork() * schema.Resource {
 // dataSourceNetworkRead performs the network lookup.
 func dataSourceNetworkRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/networks/?project=%s", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    networks, err := config.API.Networks.List(ctx, platform, client.NetworkListOpts{Project: projectID})
 
     if err != nil {
         return fmt.Errorf("Unable to list networks: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    for i := range data {
-        if data[i]["name"] == name {
-            return dataSourceNetworkAttributes(d, data[i])
+    for i := range networks {
+        if networks[i].Name == name {
+            return dataSourceNetworkAttributes(d, &networks[i])
         }
     }
 
@@ -120,24 +115,23 @@ func dataSourceNetworkRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceNetworkAttributes populates the fields of a network data source.
-func dataSourceNetworkAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    network_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_network: %d", network_id)
-
-    d.SetId(fmt.Sprintf("%d", network_id))
-    d.Set("cidr", data["cidr"])
-    d.Set("create_time", data["create_time"])
-    d.Set("dns_domain", data["dns_domain"])
-    d.Set("ext_net", data["ext_net"])
-    d.Set("gateway", data["gateway"])
-    d.Set("ip_version", data["ip_version"])
-    d.Set("nameservers", data["nameservers"])
-    d.Set("project", fmt.Sprintf("%v", data["project"]))
-    d.Set("status", data["status"])
-    d.Set("user", data["user"])
-    d.Set("with_router", data["with_router"])
-    d.Set("name", data["name"])
-    d.Set("platform", data["platform"])
+func dataSourceNetworkAttributes(d *schema.ResourceData, network *client.Network) error {
+    log.Printf("[DEBUG] Retrieved apigw_network: %s", network.ID)
+
+    d.SetId(network.ID.String())
+    d.Set("cidr", network.CIDR)
+    d.Set("create_time", network.CreateTime)
+    d.Set("dns_domain", network.DNSDomain)
+    d.Set("ext_net", network.ExtNet)
+    d.Set("gateway", network.Gateway)
+    d.Set("ip_version", network.IPVersion)
+    d.Set("nameservers", network.Nameservers)
+    d.Set("project", network.Project.String())
+    d.Set("status", network.Status)
+    d.Set("user", network.User)
+    d.Set("with_router", network.WithRouter)
+    d.Set("name", network.Name)
+    d.Set("platform", network.Platform)
 
     return nil
 }
@@ -0,0 +1,34 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccNetworkDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccNetworkConfig(name) + `
+data "apigw_network" "test" {
+  name = apigw_network.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_network.test", "id", "apigw_network.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_network.test", "cidr", "apigw_network.test", "cidr"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_network.test", "gateway", "apigw_network.test", "gateway"),
+                    resource.TestCheckResourceAttr("data.apigw_network.test", "status", "ACTIVE"),
+                ),
+            },
+        },
+    })
+}
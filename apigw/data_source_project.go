@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceProject() * schema.Resource {
@@ -29,26 +30,20 @@ func dataSourceProject() * schema.Resource {
 // dataSourceProjectRead performs the project lookup.
 func dataSourceProjectRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/projects/", platform)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    projects, err := config.API.Projects.List(ctx, platform)
 
     if err != nil {
         return fmt.Errorf("Unable to list projects: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    for i := range data {
-        if data[i]["name"] == name {
-            return dataSourceProjectAttributes(d, data[i])
+    for i := range projects {
+        if projects[i].Name == name {
+            return dataSourceProjectAttributes(d, &projects[i])
         }
     }
 
@@ -56,13 +51,12 @@ func dataSourceProjectRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceProjectAttributes populates the fields of a project data source.
-func dataSourceProjectAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    project_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_project: %d", project_id)
+func dataSourceProjectAttributes(d *schema.ResourceData, project *client.Project) error {
+    log.Printf("[DEBUG] Retrieved apigw_project: %s", project.ID)
 
-    d.SetId(fmt.Sprintf("%d", project_id))
-    d.Set("name", data["name"])
-    d.Set("platform", data["platform"])
+    d.SetId(project.ID.String())
+    d.Set("name", project.Name)
+    d.Set("platform", project.Platform)
 
     return nil
 }
@@ -0,0 +1,28 @@
+package apigw
+
+import (
+    "fmt"
+    "os"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccProjectDataSource_basic(t *testing.T) {
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_PROJECT_NAME") },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: fmt.Sprintf(`
+data "apigw_project" "test" {
+  name = %q
+}
+`, os.Getenv("APIGW_TEST_PROJECT_NAME")),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("data.apigw_project.test", "id", testAccProject()),
+                ),
+            },
+        },
+    })
+}
@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceS3Key() * schema.Resource {
@@ -53,55 +54,47 @@ func dataSourceS3Key() * schema.Resource {
 // dataSourceS3KeyRead performs the s3 key lookup.
 func dataSourceS3KeyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/projects/%s/key/", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    keys, err := config.API.Keys.List(ctx, platform, projectID)
     if err != nil {
         return fmt.Errorf("Unable to retrive project keys: %v", err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
     d.Set("platform", platform)
     d.Set("project", projectID)
     if name != "" {
         d.Set("is_public", false)
-        privateS3List := data["private"].([]interface{})
-        for _, v := range privateS3List{
-            key := v.(map[string]interface{})
-            if name == key["name"].(string) {
-                return dataSourceS3KeyAttributes(d, key)
+        for i := range keys.Private {
+            if name == keys.Private[i].Name {
+                return dataSourceS3KeyAttributes(d, &keys.Private[i])
             }
         }
-    } else {
+    } else if keys.Public != nil {
         d.Set("is_public", true)
-        return dataSourceS3KeyAttributes(d, data["public"].(map[string]interface{}))
+        return dataSourceS3KeyAttributes(d, keys.Public)
     }
 
     return fmt.Errorf("Unable to retrieve s3 key %s", name)
 }
 
 // dataSourceS3KeyAttributes populates the fields of a s3 key data source.
-func dataSourceS3KeyAttributes(d *schema.ResourceData, data map[string]interface{}) error {
+func dataSourceS3KeyAttributes(d *schema.ResourceData, key *client.Key) error {
     log.Printf("[DEBUG] Retrieved apigw_s3_key")
     project := d.Get("project").(string)
-    if name, ok := data["name"].(string); ok {
-        d.Set("name", name)
-        d.SetId(fmt.Sprintf("%s-%s", project, name))
+    if key.Name != "" {
+        d.Set("name", key.Name)
+        d.SetId(fmt.Sprintf("%s-%s", project, key.Name))
     } else {
         d.Set("name", "Public Key")
         d.SetId(fmt.Sprintf("%s-%s", project, "public"))
     }
 
-    d.Set("access_key", data["access_key"])
-    d.Set("secret_key", data["secret_key"])
+    d.Set("access_key", key.AccessKey)
+    d.Set("secret_key", key.SecretKey)
 
     return nil
 }
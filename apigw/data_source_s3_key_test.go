@@ -0,0 +1,35 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccS3KeyDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccS3KeyConfig(name) + `
+data "apigw_s3_key" "test" {
+  name = apigw_s3_key.test.name
+}
+
+data "apigw_s3_key" "public" {}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_s3_key.test", "access_key", "apigw_s3_key.test", "access_key"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_s3_key.test", "secret_key", "apigw_s3_key.test", "secret_key"),
+                    resource.TestCheckResourceAttr("data.apigw_s3_key.test", "is_public", "false"),
+                    resource.TestCheckResourceAttr("data.apigw_s3_key.public", "is_public", "true"),
+                ),
+            },
+        },
+    })
+}
@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceSecurityGroup() * schema.Resource {
@@ -73,54 +74,46 @@ func dataSourceSecurityGroup() * schema.Resource {
 // dataSourceSecurityGroupRead performs the security group lookup.
 func dataSourceSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     platform := d.Get("platform").(string)
     siteID := d.Get("vcs").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/%s/", platform, siteID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    site, err := config.API.Sites.Get(ctx, platform, siteID)
     if err != nil {
         return fmt.Errorf("Unable to get VCS %s: %v", siteID, err)
     }
-    var site map[string]interface{}
-    if err = json.Unmarshal([]byte(response), &site); err != nil {
-        return err
-    }
-    projectID := int(site["project"].(float64))
-    var serverID int
-    for _, server := range site["servers"].([]interface{}) {
-        serverInfo := server.(map[string]interface{})
-        serverID = int(serverInfo["id"].(float64))
+    var serverID string
+    for _, server := range site.Servers {
+        serverID = server.ID.String()
         break
     }
 
-    resourcePath = fmt.Sprintf("api/v4/%s/security_groups/?project=%d&server=%d", platform, projectID, serverID)
-    response, err = config.doNormalRequest(platform, resourcePath, "GET", nil)
+    opts := client.SecurityGroupListOpts{
+        Project:	site.Project.String(),
+        Server:		serverID,
+    }
+    securityGroups, err := config.API.SecurityGroups.List(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Unable to list security_groups: %v", err)
     }
 
-    var security_groups []map[string]interface{}
-    if err = json.Unmarshal([]byte(response), &security_groups); err != nil {
-        return err
-    }
-
-    for _, security_group := range security_groups {
-        return dataSourceSecurityGroupAttributes(d, security_group)
+    for i := range securityGroups {
+        return dataSourceSecurityGroupAttributes(d, &securityGroups[i])
     }
 
     return fmt.Errorf("Unable to retrieve security group by VCS %s: %v", siteID, err)
 }
 
 // dataSourceSecurityGroupAttributes populates the fields of a security group data source.
-func dataSourceSecurityGroupAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    security_group_id := data["id"].(string)
-    log.Printf("[DEBUG] Retrieved apigw_security_group: %s", security_group_id)
+func dataSourceSecurityGroupAttributes(d *schema.ResourceData, sg *client.SecurityGroup) error {
+    log.Printf("[DEBUG] Retrieved apigw_security_group: %s", sg.ID)
 
-    d.SetId(security_group_id)
-    d.Set("name", data["name"])
-    security_group_rules := flattenSecurityGroupRulesInfo(data["security_group_rules"].([]interface{}))
+    d.SetId(sg.ID.String())
+    d.Set("name", sg.Name)
+    security_group_rules := flattenSecurityGroupRulesInfo(sg.Rules)
     d.Set("security_group_rules", security_group_rules)
 
     return nil
@@ -0,0 +1,29 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccSecurityGroupDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVCSConfig(name) + `
+data "apigw_security_group" "test" {
+  vcs = apigw_vcs.test.id
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrSet("data.apigw_security_group.test", "id"),
+                    resource.TestCheckResourceAttrSet("data.apigw_security_group.test", "name"),
+                ),
+            },
+        },
+    })
+}
@@ -3,10 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
-    "strings"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceSolution() * schema.Resource {
@@ -58,32 +58,24 @@ func dataSourceSolution() * schema.Resource {
 // dataSourceSolutionRead performs the solution lookup.
 func dataSourceSolutionRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
-    params := []string{
-        fmt.Sprintf("name=%s", name),
-        fmt.Sprintf("project=%s", d.Get("project").(string)),
-    }
-    if category := d.Get("category"); category != "" {
-        params = append(params, fmt.Sprintf("category=%s", category.(string)))
+    opts := client.SolutionListOpts{
+        Category:	d.Get("category").(string),
+        Name:		name,
+        Project:	d.Get("project").(string),
     }
-    resourcePath := fmt.Sprintf("api/v4/solutions/?%s", strings.Join(params, "&"))
-    response, err := config.doNormalRequest("goc", resourcePath, "GET", nil)
+    solutions, err := config.API.Solutions.List(ctx, opts)
 
     if err != nil {
         return fmt.Errorf("Unable to list solutions: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    for _, solution := range data {
-        if solution["name"] == name {
-            return dataSourceSolutionAttributes(d, solution)
+    for i := range solutions {
+        if solutions[i].Name == name {
+            return dataSourceSolutionAttributes(d, &solutions[i])
         }
     }
 
@@ -91,16 +83,15 @@ func dataSourceSolutionRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceSolutionAttributes populates the fields of a solution data source.
-func dataSourceSolutionAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    solution_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_solution: %d", solution_id)
-
-    d.SetId(fmt.Sprintf("%d", solution_id))
-    d.Set("create_time", data["create_time"])
-    d.Set("desc", data["desc"])
-    d.Set("category", data["category"])
-    d.Set("is_public", data["is_public"])
-    d.Set("is_tenant_admin_only", data["is_tenant_admin_only"])
+func dataSourceSolutionAttributes(d *schema.ResourceData, solution *client.Solution) error {
+    log.Printf("[DEBUG] Retrieved apigw_solution: %s", solution.ID)
+
+    d.SetId(solution.ID.String())
+    d.Set("create_time", solution.CreateTime)
+    d.Set("desc", solution.Desc)
+    d.Set("category", solution.Category)
+    d.Set("is_public", solution.IsPublic)
+    d.Set("is_tenant_admin_only", solution.IsTenantAdminOnly)
 
     return nil
 }
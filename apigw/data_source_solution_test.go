@@ -0,0 +1,23 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccSolutionDataSource_basic(t *testing.T) {
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccSolutionConfig("vcs", "APIGW_TEST_VCS_SOLUTION"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrSet("data.apigw_solution.vcs", "id"),
+                    resource.TestCheckResourceAttrSet("data.apigw_solution.vcs", "category"),
+                ),
+            },
+        },
+    })
+}
@@ -0,0 +1,32 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccVCSDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVCSConfig(name) + `
+data "apigw_vcs" "test" {
+  name = apigw_vcs.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair("data.apigw_vcs.test", "id", "apigw_vcs.test", "id"),
+                    resource.TestCheckResourceAttrPair("data.apigw_vcs.test", "solution", "apigw_vcs.test", "solution"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_vcs.test", "servers.0.id", "apigw_vcs.test", "servers.0.id"),
+                    resource.TestCheckResourceAttr("data.apigw_vcs.test", "status", "Ready"),
+                ),
+            },
+        },
+    })
+}
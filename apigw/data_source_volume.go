@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceVolume() * schema.Resource {
@@ -97,9 +98,11 @@ func dataSourceVolume() * schema.Resource {
 // dataSourceVolumeRead performs the volume lookup.
 func dataSourceVolumeRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     platform := d.Get("platform").(string)
-    serversID := []int{}
+    serversID := []string{}
     var name, siteID, projectID string
     if site := d.Get("vcs"); site != "" {
         siteID = site.(string)
@@ -112,50 +115,38 @@ func dataSourceVolumeRead(d *schema.ResourceData, meta interface{}) error {
     }
 
     if siteID != "" {
-        resourcePath := fmt.Sprintf("api/v4/%s/sites/%s/", platform, siteID)
-        response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+        site, err := config.API.Sites.Get(ctx, platform, siteID)
         if err != nil {
             return fmt.Errorf("Unable to get VCS %s: %v", siteID, err)
         }
-        var data map[string]interface{}
-        if err = json.Unmarshal([]byte(response), &data); err != nil {
-            return err
-        }
-        projectID = fmt.Sprintf("%d", int(data["project"].(float64)))
-        for _, server := range data["servers"].([]interface{}) {
-            serverInfo := server.(map[string]interface{})
-            serversID = append(serversID, int(serverInfo["id"].(float64)))
+        projectID = site.Project.String()
+        for _, server := range site.Servers {
+            serversID = append(serversID, server.ID.String())
         }
     } else if name == "" || projectID == "" {
         return fmt.Errorf("name and project are required when vcs is not defined")
     }
 
-    resourcePath := fmt.Sprintf("api/v4/%s/volumes/?project=%s", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    volumes, err := config.API.Volumes.List(ctx, platform, client.VolumeListOpts{Project: projectID})
 
     if err != nil {
         return fmt.Errorf("Unable to list volumes: %v", err)
     }
 
-    var data []map[string]interface{}
-    if err = json.Unmarshal([]byte(response), &data); err != nil {
-        return err
-    }
-
     found := false
-    for _, volume := range data {
+    for i := range volumes {
+        volume := &volumes[i]
         if len(serversID) > 0 {
-            if host, ok := volume["attached_host"].(map[string]interface{}); ok {
-                attached_hostID := int(host["id"].(float64))
+            if volume.AttachedHost != nil {
                 for _, serverID := range serversID {
-                    if attached_hostID == serverID {
+                    if volume.AttachedHost.ID.String() == serverID {
                         found = true
                     }
                 }
             }
         }
         if name != "" {
-            if volume["name"] == name {
+            if volume.Name == name {
                 found = true
             } else if found {
                 found = false
@@ -173,30 +164,27 @@ func dataSourceVolumeRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceVolumeAttributes populates the fields of a volume data source.
-func dataSourceVolumeAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    volume_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_volume: %d", volume_id)
+func dataSourceVolumeAttributes(d *schema.ResourceData, volume *client.Volume) error {
+    log.Printf("[DEBUG] Retrieved apigw_volume: %s", volume.ID)
 
-    d.SetId(fmt.Sprintf("%d", volume_id))
-    if host, ok := data["attached_host"].(interface{}); ok {
-        hostInfo := flattenVolumeHostInfo(host)
+    d.SetId(volume.ID.String())
+    if volume.AttachedHost != nil {
+        hostInfo := flattenVolumeHostInfo(volume.AttachedHost)
         d.Set("attached_host", hostInfo)
     } else {
         var emptyHost interface{}
         d.Set("attached_host", emptyHost)
     }
-    d.Set("create_time", data["create_time"])
-    d.Set("is_attached", data["is_attached"])
-    d.Set("is_bootable", data["is_bootable"])
-    d.Set("mountpoint", data["mountpoint"])
-    d.Set("name", data["name"])
-    projectInfo := data["project"].(map[string]interface{})
-    projectID := int(projectInfo["id"].(float64))
-    d.Set("project", fmt.Sprintf("%d", projectID))
-    d.Set("size", data["size"])
-    d.Set("status", data["status"])
-    d.Set("volume_type", data["volume_type"])
-    d.Set("user", data["user"])
+    d.Set("create_time", volume.CreateTime)
+    d.Set("is_attached", volume.IsAttached)
+    d.Set("is_bootable", volume.IsBootable)
+    d.Set("mountpoint", volume.Mountpoint)
+    d.Set("name", volume.Name)
+    d.Set("project", volume.Project.String())
+    d.Set("size", volume.Size)
+    d.Set("status", volume.Status)
+    d.Set("volume_type", volume.VolumeType)
+    d.Set("user", volume.User)
 
     return nil
 }
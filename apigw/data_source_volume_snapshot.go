@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceVolumeSnapshot() * schema.Resource {
@@ -65,26 +66,22 @@ func dataSourceVolumeSnapshot() * schema.Resource {
 // dataSourceVolumeSnapshotRead performs the volume snapshot lookup.
 func dataSourceVolumeSnapshotRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     platform := d.Get("platform").(string)
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/snapshots/?project=%s", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    snapshots, err := config.API.Snapshots.List(ctx, platform, client.SnapshotListOpts{Project: projectID})
 
     if err != nil {
         return fmt.Errorf("Unable to list snapshots: %v", err)
     }
 
-    var data []map[string]interface{}
-    if err = json.Unmarshal([]byte(response), &data); err != nil {
-        return err
-    }
-
-    for _, snapshot := range data {
-        if snapshot["name"] == name {
-            return dataSourceVolumeSnapshotAttributes(d, snapshot)
+    for i := range snapshots {
+        if snapshots[i].Name == name {
+            return dataSourceVolumeSnapshotAttributes(d, &snapshots[i])
         }
     }
 
@@ -92,18 +89,15 @@ func dataSourceVolumeSnapshotRead(d *schema.ResourceData, meta interface{}) erro
 }
 
 // dataSourceVolumeSnapshotAttributes populates the fields of a volume snapshot data source.
-func dataSourceVolumeSnapshotAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    snapshot_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_volume_snapshot: %d", snapshot_id)
-
-    d.SetId(fmt.Sprintf("%d", snapshot_id))
-    d.Set("create_time", data["create_time"])
-    d.Set("desc", data["desc"])
-    d.Set("status", data["status"])
-    d.Set("user", data["user"])
-    volumeInfo := data["volume"].(map[string]interface{})
-    volumeID := int(volumeInfo["id"].(float64))
-    d.Set("volume", fmt.Sprintf("%d", volumeID))
+func dataSourceVolumeSnapshotAttributes(d *schema.ResourceData, snapshot *client.Snapshot) error {
+    log.Printf("[DEBUG] Retrieved apigw_volume_snapshot: %s", snapshot.ID)
+
+    d.SetId(snapshot.ID.String())
+    d.Set("create_time", snapshot.CreateTime)
+    d.Set("desc", snapshot.Desc)
+    d.Set("status", snapshot.Status)
+    d.Set("user", snapshot.User)
+    d.Set("volume", snapshot.Volume.String())
 
     return nil
 }
@@ -0,0 +1,33 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccVolumeSnapshotDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVolumeSnapshotConfig(name) + `
+data "apigw_volume_snapshot" "test" {
+  name    = apigw_volume_snapshot.test.name
+  project = apigw_volume.test.project
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_volume_snapshot.test", "id", "apigw_volume_snapshot.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_volume_snapshot.test", "volume", "apigw_volume_snapshot.test", "volume"),
+                    resource.TestCheckResourceAttr("data.apigw_volume_snapshot.test", "status", "AVAILABLE"),
+                ),
+            },
+        },
+    })
+}
@@ -0,0 +1,31 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccVolumeDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVolumeConfig(name, 1) + `
+data "apigw_volume" "test" {
+  name    = apigw_volume.test.name
+  project = apigw_volume.test.project
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair("data.apigw_volume.test", "id", "apigw_volume.test", "id"),
+                    resource.TestCheckResourceAttrPair("data.apigw_volume.test", "size", "apigw_volume.test", "size"),
+                    resource.TestCheckResourceAttr("data.apigw_volume.test", "status", "AVAILABLE"),
+                ),
+            },
+        },
+    })
+}
@@ -3,10 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
-    "strings"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceVPN() * schema.Resource {
@@ -139,83 +139,58 @@ func dataSourceVPN() * schema.Resource {
 // dataSourceVPNRead performs the VPN lookup.
 func dataSourceVPNRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
-    var params []string
-    if project := d.Get("project"); project != "" {
-        params = append(params, fmt.Sprintf("project=%s", project))
-    }
-    if ike_policy := d.Get("ike_policy"); ike_policy != "" {
-        params = append(params, fmt.Sprintf("ike_policy=%s", ike_policy))
-    }
-    if ipsec_policy := d.Get("ipsec_policy"); ipsec_policy != "" {
-        params = append(params, fmt.Sprintf("ipsec_policy=%s", ipsec_policy))
-    }
-    if private_network := d.Get("private_network"); private_network != "" {
-        params = append(params, fmt.Sprintf("private_network=%s", private_network))
+    opts := client.VPNServiceListOpts{
+        Project:	d.Get("project").(string),
+        IKEPolicy:	d.Get("ike_policy").(string),
+        IPSecPolicy:	d.Get("ipsec_policy").(string),
+        PrivateNetwork:	d.Get("private_network").(string),
     }
 
-    resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/?%s",
-                                platform, strings.Join(params, "&"))
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    vpns, err := config.API.VPNServices.List(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Unable to list vpn: %v", err)
     }
 
-    var data []map[string]interface{}
-    if err = json.Unmarshal([]byte(response), &data); err != nil {
-        return err
-    }
-
-    var vpn_id int
-    for _, vpn := range data {
-        if vpn["name"] == name {
-            if vpn_id != 0 {
+    var vpnID string
+    for _, vpn := range vpns {
+        if vpn.Name == name {
+            if vpnID != "" {
                 return fmt.Errorf("There are duplicated vpn with name '%s'", name)
             }
-            vpn_id = int(vpn["id"].(float64))
+            vpnID = vpn.ID.String()
         }
     }
-    if vpn_id != 0 {
-        resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/%d/", platform, vpn_id)
-        response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    if vpnID != "" {
+        vpn, err := config.API.VPNServices.Get(ctx, platform, vpnID)
         if err != nil {
             return fmt.Errorf("Unable to retrieve vpn: %v", err)
         }
 
-        var vpn map[string]interface{}
-        if err = json.Unmarshal([]byte(response), &vpn); err != nil {
-            return err
-        }
         return dataSourceVPNAttributes(d, vpn)
     }
 
     return fmt.Errorf("Unable to retrieve vpn %s: %v", name, err)
 }
 
-// retrieve id from a map
-func retrieveID(data interface{}) string {
-    dataMap := data.(map[string]interface{})
-    ID := int(dataMap["id"].(float64))
-    return fmt.Sprintf("%d", ID)
-}
-
 // dataSourceVPNAttributes populates the fields of a VPN data source.
-func dataSourceVPNAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    vpn_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_vpn: %d", vpn_id)
-
-    d.SetId(fmt.Sprintf("%d", vpn_id))
-    d.Set("user", data["user"])
-    d.Set("local_address", data["local_address"])
-    d.Set("local_cidr", data["local_cidr"])
-    d.Set("status", data["status"])
-    d.Set("ike_policy", retrieveID(data["ike_policy"]))
-    d.Set("ipsec_policy", retrieveID(data["ipsec_policy"]))
-    d.Set("private_network", retrieveID(data["private_network"]))
-    d.Set("vpn_connection", flattenVPNConnectionInfo(data["connection"]))
+func dataSourceVPNAttributes(d *schema.ResourceData, vpn *client.VPNService) error {
+    log.Printf("[DEBUG] Retrieved apigw_vpn: %s", vpn.ID)
+
+    d.SetId(vpn.ID.String())
+    d.Set("user", vpn.User)
+    d.Set("local_address", vpn.LocalAddress)
+    d.Set("local_cidr", vpn.LocalCIDR)
+    d.Set("status", vpn.Status)
+    d.Set("ike_policy", vpn.IKEPolicy.String())
+    d.Set("ipsec_policy", vpn.IPSecPolicy.String())
+    d.Set("private_network", vpn.PrivateNetwork.String())
+    d.Set("vpn_connection", flattenVPNConnectionInfo(vpn.Connection))
 
     return nil
 }
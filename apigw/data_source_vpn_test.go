@@ -0,0 +1,35 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccVPNDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVPNConfig(name) + `
+data "apigw_vpn" "test" {
+  name            = apigw_vpn.test.name
+  private_network = apigw_vpn.test.private_network
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair("data.apigw_vpn.test", "id", "apigw_vpn.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_vpn.test", "ike_policy", "apigw_vpn.test", "ike_policy"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_vpn.test", "ipsec_policy", "apigw_vpn.test", "ipsec_policy"),
+                    resource.TestCheckResourceAttrPair(
+                        "data.apigw_vpn.test", "local_address", "apigw_vpn.test", "local_address"),
+                ),
+            },
+        },
+    })
+}
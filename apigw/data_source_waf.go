@@ -3,9 +3,10 @@ package apigw
 import (
     "fmt"
     "log"
-    "encoding/json"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func dataSourceWAF() * schema.Resource {
@@ -92,32 +93,26 @@ func dataSourceWAF() * schema.Resource {
 // dataSourceWAFRead performs the WAF lookup.
 func dataSourceWAFRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
 
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     projectID := d.Get("project").(string)
 
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/?project=%s&name=%s", platform, projectID, name)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    sites, err := config.API.Sites.List(ctx, platform, client.SiteListOpts{Project: projectID, Name: name})
 
     if err != nil {
         return fmt.Errorf("Unable to list WAF: %v", err)
     }
 
-    var data []map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
     found := false
-    for _, waf := range data {
-        if waf["name"] == name {
+    for i := range sites {
+        if sites[i].Name == name {
             if found {
                 return fmt.Errorf("There are duplicated WAF with name '%s'", name)
             }
-            err = dataSourceWAFAttributes(d, waf)
+            err = dataSourceWAFAttributes(d, &sites[i])
             found = true
         }
     }
@@ -129,18 +124,17 @@ func dataSourceWAFRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // dataSourceWAFAttributes populates the fields of a WAF data source.
-func dataSourceWAFAttributes(d *schema.ResourceData, data map[string]interface{}) error {
-    waf_id := int(data["id"].(float64))
-    log.Printf("[DEBUG] Retrieved apigw_waf: %d", waf_id)
-
-    d.SetId(fmt.Sprintf("%d", waf_id))
-    d.Set("create_time", data["create_time"])
-    d.Set("public_ip", data["public_ip"])
-    d.Set("solution", fmt.Sprintf("%d", int(data["solution"].(float64))))
-    serversInfo := flattenSiteServersInfo(data["servers"].([]interface{}))
+func dataSourceWAFAttributes(d *schema.ResourceData, site *client.Site) error {
+    log.Printf("[DEBUG] Retrieved apigw_waf: %s", site.ID)
+
+    d.SetId(site.ID.String())
+    d.Set("create_time", site.CreateTime)
+    d.Set("public_ip", site.PublicIP)
+    d.Set("solution", site.Solution.String())
+    serversInfo := flattenSiteServersInfo(site.Servers)
     d.Set("servers", serversInfo)
-    d.Set("status", data["status"])
-    d.Set("user", data["user"])
+    d.Set("status", site.Status)
+    d.Set("user", site.User)
 
     return nil
 }
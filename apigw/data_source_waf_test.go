@@ -0,0 +1,30 @@
+package apigw
+
+import (
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccWAFDataSource_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_WAF_SOLUTION") },
+        Providers:	testAccProviders,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccWAFConfig(name) + `
+data "apigw_waf" "test" {
+  name = apigw_waf.test.name
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair("data.apigw_waf.test", "id", "apigw_waf.test", "id"),
+                    resource.TestCheckResourceAttrPair("data.apigw_waf.test", "solution", "apigw_waf.test", "solution"),
+                    resource.TestCheckResourceAttr("data.apigw_waf.test", "status", "Ready"),
+                ),
+            },
+        },
+    })
+}
@@ -0,0 +1,63 @@
+package apigw
+
+import (
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// providerDefault is a required argument of resources and data sources which
+// falls back to an argument of the provider.
+type providerDefault struct {
+    Key		string
+    Argument	string
+    Value	func(*Config) string
+}
+
+var providerDefaults = []providerDefault{
+    {"platform", "default_platform", func(c *Config) string { return c.DefaultPlatform }},
+    {"project", "default_project", func(c *Config) string { return c.DefaultProject }},
+}
+
+// setProviderDefaults makes the required platform and project arguments of
+// all resources and data sources optional, defaulting to default_platform
+// and default_project. As they are schema defaults, a changed default shows
+// up in the plan just like a changed argument.
+func setProviderDefaults(p *schema.Provider) {
+    resources := make([]*schema.Resource, 0, len(p.ResourcesMap) + len(p.DataSourcesMap))
+    for _, r := range p.ResourcesMap {
+        resources = append(resources, r)
+    }
+    for _, r := range p.DataSourcesMap {
+        resources = append(resources, r)
+    }
+
+    for _, r := range resources {
+        for _, def := range providerDefaults {
+            s, ok := r.Schema[def.Key]
+            if !ok || !s.Required {
+                continue
+            }
+            s.Required = false
+            s.Optional = true
+            s.DefaultFunc = providerDefaultFunc(p, def)
+        }
+    }
+}
+
+func providerDefaultFunc(p *schema.Provider, def providerDefault) schema.SchemaDefaultFunc {
+    return func() (interface{}, error) {
+        config, ok := p.Meta().(*PConfig)
+        if !ok {
+            // Configurations are validated before the provider is configured.
+            return nil, nil
+        }
+
+        value := def.Value(&config.Config)
+        if value == "" {
+            return nil, fmt.Errorf(
+                "%q must be set, or %q in the provider", def.Key, def.Argument)
+        }
+        return value, nil
+    }
+}
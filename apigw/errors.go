@@ -1,8 +1,15 @@
 package apigw
 
 import (
+    "encoding/json"
+    "errors"
     "fmt"
+    "log"
     "net/http"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
 // BaseError is an error type that all other error types embed.
@@ -23,6 +30,118 @@ func (e BaseError) choseErrString() string {
     return e.DefaultErrString
 }
 
+// APIError is the error payload returned by the gateway.
+type APIError struct {
+    Code	string
+    Message	string
+    // FieldErrors holds the validation errors of single request fields.
+    FieldErrors	map[string][]string
+    RequestID	string
+}
+
+func (e *APIError) Error() string {
+    var b strings.Builder
+    b.WriteString(e.Message)
+    if e.Code != "" {
+        fmt.Fprintf(&b, " (code %s)", e.Code)
+    }
+
+    fields := make([]string, 0, len(e.FieldErrors))
+    for field := range e.FieldErrors {
+        fields = append(fields, field)
+    }
+    sort.Strings(fields)
+    for _, field := range fields {
+        if b.Len() > 0 {
+            b.WriteString("\n")
+        }
+        fmt.Fprintf(&b, "  %s: %s", field, strings.Join(e.FieldErrors[field], "; "))
+    }
+
+    if e.RequestID != "" {
+        fmt.Fprintf(&b, "\n(request id %s)", e.RequestID)
+    }
+    return b.String()
+}
+
+// apiErrorMessageKeys are the fields the gateway and the services behind it
+// use for the error message, in order of preference.
+var apiErrorMessageKeys = []string{"message", "detail", "error", "msg"}
+
+// parseAPIError decodes the error payload of a failed request. Payloads which
+// are not JSON objects are kept as the message.
+func parseAPIError(body []byte, header http.Header) *APIError {
+    apiErr := &APIError{
+        RequestID:	header.Get("X-Request-Id"),
+    }
+
+    var data map[string]interface{}
+    if err := json.Unmarshal(body, &data); err != nil {
+        apiErr.Message = strings.TrimSpace(string(body))
+        return apiErr
+    }
+
+    for _, key := range apiErrorMessageKeys {
+        if message, ok := data[key].(string); ok {
+            apiErr.Message = message
+            delete(data, key)
+            break
+        }
+    }
+
+    if code, ok := data["code"]; ok && code != nil {
+        apiErr.Code = fmt.Sprintf("%v", code)
+        delete(data, "code")
+    }
+
+    if requestID, ok := data["request_id"].(string); ok {
+        apiErr.RequestID = requestID
+        delete(data, "request_id")
+    }
+
+    fieldErrors := data
+    if errs, ok := data["errors"].(map[string]interface{}); ok {
+        fieldErrors = errs
+    }
+
+    for field, value := range fieldErrors {
+        messages := errorMessages(value)
+        if messages == nil {
+            continue
+        }
+        if field == "non_field_errors" && apiErr.Message == "" {
+            apiErr.Message = strings.Join(messages, "; ")
+            continue
+        }
+        if apiErr.FieldErrors == nil {
+            apiErr.FieldErrors = make(map[string][]string)
+        }
+        apiErr.FieldErrors[field] = messages
+    }
+
+    return apiErr
+}
+
+// errorMessages returns the messages of a field error, which is a list of
+// strings, or nil for any other value, e.g. a status string or a nested
+// object which is not about a field.
+func errorMessages(v interface{}) []string {
+    value, ok := v.([]interface{})
+    if !ok || len(value) == 0 {
+        return nil
+    }
+
+    messages := make([]string, 0, len(value))
+    for _, item := range value {
+        message, ok := item.(string)
+        if !ok {
+            return nil
+        }
+        messages = append(messages, message)
+    }
+    return messages
+}
+
 // ErrUnexpectedResponseCode is returned by the Request method when a response code other than
 // those listed in OkCodes is encountered.
 type ErrUnexpectedResponseCode struct {
@@ -33,12 +152,14 @@ type ErrUnexpectedResponseCode struct {
     Actual         int
     Body           []byte
     ResponseHeader http.Header
+    // APIError is the decoded error payload, if the response had a body.
+    APIError       *APIError
 }
 
 func (e ErrUnexpectedResponseCode) Error() string {
     e.DefaultErrString = fmt.Sprintf(
         "Expected HTTP response code %v when accessing [%s %s], but got %d instead\n%s",
-        e.Expected, e.Method, e.URL, e.Actual, e.Body,
+        e.Expected, e.Method, e.URL, e.Actual, e.Message(),
     )
     return e.choseErrString()
 }
@@ -48,6 +169,22 @@ func (e ErrUnexpectedResponseCode) GetStatusCode() int {
 	return e.Actual
 }
 
+// Message returns the error message sent by the gateway.
+func (e ErrUnexpectedResponseCode) Message() string {
+    if e.APIError != nil {
+        return e.APIError.Error()
+    }
+    return string(e.Body)
+}
+
+// Unwrap gives errors.As access to the decoded error payload.
+func (e ErrUnexpectedResponseCode) Unwrap() error {
+    if e.APIError == nil {
+        return nil
+    }
+    return e.APIError
+}
+
 // ErrDefault400 is the default error type returned on a 400 HTTP response code.
 type ErrDefault400 struct {
 	ErrUnexpectedResponseCode
@@ -73,40 +210,69 @@ type ErrDefault409 struct {
         ErrUnexpectedResponseCode
 }
 
+// ErrDefault429 is the default error type returned on a 429 HTTP response code.
+type ErrDefault429 struct {
+    ErrUnexpectedResponseCode
+}
+
 // ErrDefault500 is the default error type returned on a 500 HTTP response code.
 type ErrDefault500 struct {
         ErrUnexpectedResponseCode
 }
 
+// ErrDefault502 is the default error type returned on a 502 HTTP response code.
+type ErrDefault502 struct {
+    ErrUnexpectedResponseCode
+}
+
 // ErrDefault503 is the default error type returned on a 503 HTTP response code.
 type ErrDefault503 struct {
         ErrUnexpectedResponseCode
 }
 
+// ErrDefault504 is the default error type returned on a 504 HTTP response code.
+type ErrDefault504 struct {
+    ErrUnexpectedResponseCode
+}
+
 func (e ErrDefault400) Error() string {
     e.DefaultErrString = fmt.Sprintf(
         "Bad request with: [%s %s], error message: %s",
-        e.Method, e.URL, e.Body,
+        e.Method, e.URL, e.Message(),
     )
     return e.choseErrString()
 }
 
 func (e ErrDefault401) Error() string {
-    return "Unauthorized API Key"
+    e.DefaultErrString = "Unauthorized API Key, check the apikey of the provider"
+    return e.choseErrString()
 }
 
 func (e ErrDefault403) Error() string {
-    return "Permission denied"
+    e.DefaultErrString = fmt.Sprintf(
+        "Permission denied with: [%s %s], error message: %s",
+        e.Method, e.URL, e.Message(),
+    )
+    return e.choseErrString()
 }
 
 func (e ErrDefault404) Error() string {
-    return "Resource not found"
+    e.DefaultErrString = "Resource not found"
+    return e.choseErrString()
 }
 
 func (e ErrDefault409) Error() string {
     e.DefaultErrString = fmt.Sprintf(
         "Resource conflict with: [%s %s], error message: %s",
-        e.Method, e.URL, e.Body,
+        e.Method, e.URL, e.Message(),
+    )
+    return e.choseErrString()
+}
+
+func (e ErrDefault429) Error() string {
+    e.DefaultErrString = fmt.Sprintf(
+        "Rate limit exceeded with: [%s %s], lower requests_per_second or max_concurrent_requests, error message: %s",
+        e.Method, e.URL, e.Message(),
     )
     return e.choseErrString()
 }
@@ -114,7 +280,15 @@ func (e ErrDefault409) Error() string {
 func (e ErrDefault500) Error() string {
     e.DefaultErrString = fmt.Sprintf(
         "Internal server error with: [%s %s], error message: %s",
-        e.Method, e.URL, e.Body,
+        e.Method, e.URL, e.Message(),
+    )
+    return e.choseErrString()
+}
+
+func (e ErrDefault502) Error() string {
+    e.DefaultErrString = fmt.Sprintf(
+        "Bad gateway with: [%s %s], error message: %s",
+        e.Method, e.URL, e.Message(),
     )
     return e.choseErrString()
 }
@@ -122,7 +296,15 @@ func (e ErrDefault500) Error() string {
 func (e ErrDefault503) Error() string {
     e.DefaultErrString = fmt.Sprintf(
         "Service Unavailable with: [%s %s], error message: %s",
-        e.Method, e.URL, e.Body,
+        e.Method, e.URL, e.Message(),
+    )
+    return e.choseErrString()
+}
+
+func (e ErrDefault504) Error() string {
+    e.DefaultErrString = fmt.Sprintf(
+        "Gateway timeout with: [%s %s], error message: %s",
+        e.Method, e.URL, e.Message(),
     )
     return e.choseErrString()
 }
@@ -157,6 +339,12 @@ type Err409er interface {
     Error409(ErrUnexpectedResponseCode) error
 }
 
+// Err429er is the interface resource error types implement to override the error message
+// from a 429 error.
+type Err429er interface {
+    Error429(ErrUnexpectedResponseCode) error
+}
+
 // Err500er is the interface resource error types implement to override the error message
 // from a 500 error.
 type Err500er interface {
@@ -168,3 +356,77 @@ type Err500er interface {
 type Err503er interface {
     Error503(ErrUnexpectedResponseCode) error
 }
+
+// Err502er is the interface resource error types implement to override the error message
+// from a 502 error.
+type Err502er interface {
+    Error502(ErrUnexpectedResponseCode) error
+}
+
+// Err504er is the interface resource error types implement to override the error message
+// from a 504 error.
+type Err504er interface {
+    Error504(ErrUnexpectedResponseCode) error
+}
+
+// errorTranslators maps the collection of a request path, e.g. "networks",
+// to the error type overriding the messages of its errors by implementing
+// any of the Err400er to Err504er interfaces.
+var errorTranslators = map[string]interface{}{}
+
+// registerErrorTranslator is called by resources from init to override the
+// error messages of requests to a collection. Translators should return the
+// default error type with Info set so that the Is helpers keep working.
+func registerErrorTranslator(collection string, translator interface{}) {
+    errorTranslators[collection] = translator
+}
+
+// requestCollection returns the collection of a request path of the form
+// api/v4/<platform>/<collection>/...
+func requestCollection(resourcePath string) string {
+    parts := strings.SplitN(strings.TrimPrefix(resourcePath, "/"), "/", 5)
+    if len(parts) < 4 {
+        return ""
+    }
+    return strings.SplitN(parts[3], "?", 2)[0]
+}
+
+// statusCode returns the response code of a failed request, or 0 if err did
+// not come from a response.
+func statusCode(err error) int {
+    var e interface{ GetStatusCode() int }
+    if errors.As(err, &e) {
+        return e.GetStatusCode()
+    }
+    return 0
+}
+
+// IsNotFound reports whether err was caused by a 404 response.
+func IsNotFound(err error) bool {
+    return statusCode(err) == http.StatusNotFound
+}
+
+// IsConflict reports whether err was caused by a 409 response.
+func IsConflict(err error) bool {
+    return statusCode(err) == http.StatusConflict
+}
+
+// IsRateLimited reports whether err was caused by a 429 response.
+func IsRateLimited(err error) bool {
+    return statusCode(err) == http.StatusTooManyRequests
+}
+
+// checkDeleted is called by Read with the error of retrieving a resource. A
+// resource which is gone is removed from the state so that Terraform plans
+// to create it again, any other error is returned prefixed with msg. A
+// resource which is gone right after its create is an error, which must not
+// be applied as if it had been created.
+func checkDeleted(d *schema.ResourceData, err error, msg string) error {
+    if IsNotFound(err) && !d.IsNewResource() {
+        log.Printf("[WARN] %s: removing %s from state", msg, d.Id())
+        d.SetId("")
+        return nil
+    }
+
+    return fmt.Errorf("%s: %v", msg, err)
+}
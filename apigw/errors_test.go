@@ -0,0 +1,96 @@
+package apigw
+
+import (
+    "net/http"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestCheckDeleted(t *testing.T) {
+    notFound := ErrDefault404{ErrUnexpectedResponseCode{Actual: http.StatusNotFound}}
+    resource := &schema.Resource{Schema: map[string]*schema.Schema{}}
+
+    d := resource.TestResourceData()
+    d.SetId("1")
+    if err := checkDeleted(d, notFound, "Unable to retrieve 1"); err != nil || d.Id() != "" {
+        t.Errorf("checkDeleted of a deleted resource = %v with ID %q, want it removed", err, d.Id())
+    }
+
+    d = resource.TestResourceData()
+    d.SetId("1")
+    d.MarkNewResource()
+    if err := checkDeleted(d, notFound, "Unable to retrieve 1"); err == nil || d.Id() != "1" {
+        t.Errorf("checkDeleted of a new resource = %v with ID %q, want an error", err, d.Id())
+    }
+
+    err := ErrDefault500{ErrUnexpectedResponseCode{Actual: http.StatusInternalServerError}}
+    d = resource.TestResourceData()
+    d.SetId("1")
+    if err := checkDeleted(d, err, "Unable to retrieve 1"); err == nil || d.Id() != "1" {
+        t.Errorf("checkDeleted of a 500 = %v with ID %q, want an error", err, d.Id())
+    }
+}
+
+func TestParseAPIError(t *testing.T) {
+    cases := []struct {
+        name	string
+        body	string
+        want	APIError
+    }{
+        {
+            name:	"text",
+            body:	"Bad Gateway\n",
+            want:	APIError{Message: "Bad Gateway"},
+        },
+        {
+            name:	"message",
+            body:	`{"message": "Quota exceeded", "code": 409, "request_id": "req-1"}`,
+            want:	APIError{Message: "Quota exceeded", Code: "409", RequestID: "req-1"},
+        },
+        {
+            name:	"detail",
+            body:	`{"detail": "Not found."}`,
+            want:	APIError{Message: "Not found."},
+        },
+        {
+            name:	"field errors",
+            body:	`{"name": ["This field is required."], "cidr": ["Invalid CIDR.", "Overlaps 10.0.0.0/8."]}`,
+            want: APIError{FieldErrors: map[string][]string{
+                "name":	{"This field is required."},
+                "cidr":	{"Invalid CIDR.", "Overlaps 10.0.0.0/8."},
+            }},
+        },
+        {
+            name:	"non field errors",
+            body:	`{"non_field_errors": ["Port is already used."]}`,
+            want:	APIError{Message: "Port is already used."},
+        },
+        {
+            name:	"errors object",
+            body:	`{"error": "Validation failed", "errors": {"name": ["Already taken."]}}`,
+            want: APIError{Message: "Validation failed", FieldErrors: map[string][]string{
+                "name":	{"Already taken."},
+            }},
+        },
+        {
+            // Strings, numbers, objects and mixed lists are no field errors.
+            name:	"other keys",
+            body:	`{"message": "Conflict", "status": "error", "retry": 3, "details": {"a": ["b"]}, "ids": [1, "x"], "empty": []}`,
+            want:	APIError{Message: "Conflict"},
+        },
+    }
+
+    header := http.Header{}
+    for _, c := range cases {
+        if got := parseAPIError([]byte(c.body), header); !reflect.DeepEqual(*got, c.want) {
+            t.Errorf("%s: parseAPIError = %#v, want %#v", c.name, *got, c.want)
+        }
+    }
+
+    header.Set("X-Request-Id", "req-2")
+    if got := parseAPIError([]byte(`{"message": "Conflict"}`), header); got.RequestID != "req-2" {
+        t.Errorf("request id = %q, want the X-Request-Id header", got.RequestID)
+    }
+}
@@ -1,63 +1,26 @@
 package apigw
 
 import (
-    "encoding/json"
-    "fmt"
-
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func flattenFirewallObjectInfo(v []interface{}) []interface{} {
-    networkInfo := make([]interface{}, len(v))
-    for i, obj := range v {
-        info := obj.(map[string]interface{})
-        networkInfo[i] = fmt.Sprintf("%d", int(info["id"].(float64)))
-    }
-    return networkInfo
-}
-
-func firewallStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
+func flattenFirewallObjectInfo(v []client.ID) []interface{} {
+    objectInfo := make([]interface{}, len(v))
+    for i, id := range v {
+        objectInfo[i] = id.String()
     }
+    return objectInfo
 }
 
-func firewallStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
+// firewallWaiter waits for the firewall firewallID on platform.
+func firewallWaiter(ctx context.Context, config *PConfig, platform string, firewallID string) *waiter {
+    return newWaiter(ctx, config, "firewall", firewallID,
+        func() (interface{}, error) {
+            return config.API.Firewalls.Get(ctx, platform, firewallID)
+        },
+        func(v interface{}) (string, string) {
+            firewall := v.(*client.Firewall)
+            return firewall.Status, firewall.StatusReason
+        })
 }
@@ -1,23 +1,15 @@
 package apigw
 
 import (
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
 )
 
-func firewallRuleStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        } else {
-            return response, "DELETING", nil
-        }
-    }
+// firewallRuleWaiter waits for the firewall rule ruleID on platform, which has no status and is
+// ACTIVE until it is gone.
+func firewallRuleWaiter(ctx context.Context, config *PConfig, platform string, ruleID string) *waiter {
+    return newWaiter(ctx, config, "firewall rule", ruleID,
+        func() (interface{}, error) {
+            return config.API.FirewallRules.Get(ctx, platform, ruleID)
+        },
+        existsStatus)
 }
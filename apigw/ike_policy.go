@@ -1,23 +1,15 @@
 package apigw
 
 import (
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
 )
 
-func IKEStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        } else {
-            return response, "DELETING", nil
-        }
-    }
+// ikePolicyWaiter waits for the IKE policy policyID on platform, which has no status and is
+// ACTIVE until it is gone.
+func ikePolicyWaiter(ctx context.Context, config *PConfig, platform string, policyID string) *waiter {
+    return newWaiter(ctx, config, "IKE policy", policyID,
+        func() (interface{}, error) {
+            return config.API.IKEPolicies.Get(ctx, platform, policyID)
+        },
+        existsStatus)
 }
@@ -1,53 +1,18 @@
 package apigw
 
 import (
-    "encoding/json"
-
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func imageStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
-}
-
-func imageStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
+// imageWaiter waits for the image imageID on platform.
+func imageWaiter(ctx context.Context, config *PConfig, platform string, imageID string) *waiter {
+    return newWaiter(ctx, config, "image", imageID,
+        func() (interface{}, error) {
+            return config.API.Images.Get(ctx, platform, imageID)
+        },
+        func(v interface{}) (string, string) {
+            image := v.(*client.Image)
+            return image.Status, image.StatusReason
+        })
 }
@@ -0,0 +1,103 @@
+package apigw
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// parseImportID splits an import ID into the given parts, separated by
+// slashes. The last part may contain slashes itself.
+func parseImportID(id string, parts ...string) ([]string, error) {
+    values := strings.SplitN(id, "/", len(parts))
+    valid := len(values) == len(parts)
+    for _, value := range values {
+        if value == "" {
+            valid = false
+        }
+    }
+
+    if !valid {
+        return nil, fmt.Errorf(
+            "Unexpected format of ID %q, expected <%s>", id, strings.Join(parts, ">/<"))
+    }
+    return values, nil
+}
+
+// importStatePlatformID imports a resource by an ID of the form
+// <platform>/<id>.
+func importStatePlatformID(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "id")
+    if err != nil {
+        return nil, err
+    }
+
+    d.Set("platform", values[0])
+    d.SetId(values[1])
+    return []*schema.ResourceData{d}, nil
+}
+
+// importStatePlatformProjectID imports a resource whose project is not
+// returned by the gateway, by an ID of the form <platform>/<project>/<id>.
+func importStatePlatformProjectID(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "project", "id")
+    if err != nil {
+        return nil, err
+    }
+
+    d.Set("platform", values[0])
+    d.Set("project", values[1])
+    d.SetId(values[2])
+    return []*schema.ResourceData{d}, nil
+}
+
+// splitImportArguments splits an import ID of the form
+// <id>/<key>=<value>,<key>=<value> into <id>, which consists of n parts, and
+// its arguments. The arguments name the settings of a resource which the
+// gateway does not return, and may be left out. Their values must not
+// contain commas.
+func splitImportArguments(id string, n int) (string, map[string]string, error) {
+    parts := strings.SplitN(id, "/", n + 1)
+    if len(parts) <= n {
+        return id, nil, nil
+    }
+
+    arguments := make(map[string]string)
+    for _, argument := range strings.Split(parts[n], ",") {
+        keyValue := strings.SplitN(argument, "=", 2)
+        if len(keyValue) != 2 || keyValue[0] == "" {
+            return "", nil, fmt.Errorf(
+                "Unexpected argument %q in ID %q, expected <key>=<value>", argument, id)
+        }
+        arguments[keyValue[0]] = keyValue[1]
+    }
+    return strings.Join(parts[:n], "/"), arguments, nil
+}
+
+// importStatePlatformIDExtraProperty imports a site by an ID of the form
+// <platform>/<id>, optionally followed by its extra_property as arguments,
+// e.g. <platform>/<id>/flavor=m1.small,volume-size=40.
+func importStatePlatformIDExtraProperty(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    id, extraProperty, err := splitImportArguments(d.Id(), 2)
+    if err != nil {
+        return nil, err
+    }
+
+    values, err := parseImportID(id, "platform", "id")
+    if err != nil {
+        return nil, err
+    }
+
+    d.Set("platform", values[0])
+    d.Set("extra_property", extraProperty)
+    d.SetId(values[1])
+    return []*schema.ResourceData{d}, nil
+}
+
+// suppressImportedSecret suppresses the diff of a required secret which the
+// gateway never returns, e.g. a private key, so that it is empty in the
+// state of an imported resource and must not replace it.
+func suppressImportedSecret(k, old, new string, d *schema.ResourceData) bool {
+    return d.Id() != "" && old == ""
+}
@@ -0,0 +1,121 @@
+package apigw
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestSplitImportArguments(t *testing.T) {
+    cases := []struct {
+        name		string
+        id		string
+        wantID		string
+        want		map[string]string
+        wantErr		bool
+    }{
+        {"no arguments", "dc1/abc", "dc1/abc", nil, false},
+        {"arguments", "dc1/abc/flavor=m1.small,volume-size=40", "dc1/abc",
+            map[string]string{"flavor": "m1.small", "volume-size": "40"}, false},
+        {"empty value", "dc1/abc/flavor=", "dc1/abc", map[string]string{"flavor": ""}, false},
+        {"value with equals", "dc1/abc/user-data=a=b", "dc1/abc", map[string]string{"user-data": "a=b"}, false},
+        {"no value", "dc1/abc/flavor", "", nil, true},
+        {"no key", "dc1/abc/=m1.small", "", nil, true},
+        {"empty", "dc1/abc/", "", nil, true},
+    }
+
+    for _, c := range cases {
+        id, arguments, err := splitImportArguments(c.id, 2)
+        if (err != nil) != c.wantErr {
+            t.Errorf("%s: splitImportArguments error = %v, want error %v", c.name, err, c.wantErr)
+            continue
+        }
+        if id != c.wantID || !reflect.DeepEqual(arguments, c.want) {
+            t.Errorf("%s: splitImportArguments = %q, %v, want %q, %v", c.name, id, arguments, c.wantID, c.want)
+        }
+    }
+}
+
+func TestImportStatePlatformIDExtraProperty(t *testing.T) {
+    d := schema.TestResourceDataRaw(t, resourceVCS().Schema, map[string]interface{}{})
+    d.SetId("dc1/abc/flavor=m1.small,volume-size=40")
+    if _, err := importStatePlatformIDExtraProperty(d, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    want := map[string]interface{}{"flavor": "m1.small", "volume-size": "40"}
+    if d.Id() != "abc" || d.Get("platform") != "dc1" || !reflect.DeepEqual(d.Get("extra_property"), want) {
+        t.Errorf("imported %s on %v with extra_property %v, want abc on dc1 with %v",
+            d.Id(), d.Get("platform"), d.Get("extra_property"), want)
+    }
+}
+
+func TestResourceVCSImageImport(t *testing.T) {
+    d := schema.TestResourceDataRaw(t, resourceVCSImage().Schema, map[string]interface{}{})
+    d.SetId("dc1/server-1/image-1")
+    if _, err := resourceVCSImageImport(d, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    if d.Id() != "image-1" || d.Get("platform") != "dc1" || d.Get("server") != "server-1" {
+        t.Errorf("imported %s on %v from %v, want image-1 on dc1 from server-1",
+            d.Id(), d.Get("platform"), d.Get("server"))
+    }
+}
+
+func TestResourceAutoScalingRelationImport(t *testing.T) {
+    cases := []struct {
+        name		string
+        id		string
+        want		map[string]interface{}
+        wantErr		string
+    }{
+        {
+            "no arguments",
+            "dc1/server-1/policy-1",
+            map[string]interface{}{
+                "loadbalancer": "", "protocol_port": 0, "scaledown_action": "", "scaleup_action": "",
+            },
+            "",
+        },
+        {
+            "arguments",
+            "dc1/server-1/policy-1/loadbalancer=lb-1,protocol_port=80,scaleup_action=up",
+            map[string]interface{}{
+                "loadbalancer": "lb-1", "protocol_port": 80, "scaledown_action": "", "scaleup_action": "up",
+            },
+            "",
+        },
+        {"bad port", "dc1/server-1/policy-1/protocol_port=http", nil, "Unexpected protocol_port"},
+        {"unknown argument", "dc1/server-1/policy-1/status=ASSOCIATED", nil, "Unexpected argument \"status\""},
+        {"missing policy", "dc1/server-1", nil, "Unexpected format of ID"},
+    }
+
+    for _, c := range cases {
+        d := schema.TestResourceDataRaw(t, resourceAutoScalingRelation().Schema, map[string]interface{}{})
+        d.SetId(c.id)
+        _, err := resourceAutoScalingRelationImport(d, nil)
+        if c.wantErr != "" {
+            if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+                t.Errorf("%s: import error = %v, want %q", c.name, err, c.wantErr)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("%s: %v", c.name, err)
+            continue
+        }
+
+        if d.Id() != "server-1/policy-1" || d.Get("server") != "server-1" || d.Get("auto_scaling_policy") != "policy-1" {
+            t.Errorf("%s: imported %s with server %v and policy %v, want server-1/policy-1",
+                c.name, d.Id(), d.Get("server"), d.Get("auto_scaling_policy"))
+        }
+        for key, want := range c.want {
+            if got := d.Get(key); got != want {
+                t.Errorf("%s: %s = %v, want %v", c.name, key, got, want)
+            }
+        }
+    }
+}
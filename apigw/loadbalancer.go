@@ -1,119 +1,300 @@
 package apigw
 
 import (
-    "encoding/json"
+    "context"
+    "fmt"
+    "strings"
+    "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func lbMembersDiffFunc(k, old, new string, d *schema.ResourceData) bool {
-    oldMembers, newMembers := d.GetChange("members")
-    if oldMembers == nil && newMembers != nil{
-       return false
-    }
-
-    if oldMembers != nil && newMembers == nil{
-        return false
-    }
-
-    oldArray := oldMembers.([]interface{})
-    newArray := newMembers.([]interface{})
-    if len(oldArray) != len(newArray) {
-        return false
-    }
-
-    equalCount := 0
-    for _, x := range newArray{
-        newObject := x.(map[string]interface{})
-        newIP := newObject["ip"].(string)
-        newPort := newObject["port"].(int)
-        newWeight := newObject["weight"].(int)
-        for _, y := range oldArray {
-            oldObject := y.(map[string]interface{})
-            oldIP := oldObject["ip"].(string)
-            oldPort := oldObject["port"].(int)
-            oldWeight := oldObject["weight"].(int)
-            if newIP == oldIP && newPort == oldPort && newWeight == oldWeight{
-                equalCount += 1
-                break
-            }
-        }
+// lbMemberResource is the schema of the members of loadbalancers and
+// listeners.
+func lbMemberResource() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "ip": {
+                Type:		schema.TypeString,
+                Required:	true,
+            },
+
+            "port": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Default:	80,
+            },
+
+            "status": {
+                Type:		schema.TypeString,
+                Computed:	true,
+            },
+
+            "weight": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Default:	1,
+            },
+        },
     }
-    return equalCount == len(oldArray)
 }
 
-func flattenLBMonitorInfo(v map[string]interface{}) []interface{} {
-    monitorInfo := make([]interface{}, 1)
-    info := make(map[string]interface{})
-    info["delay"] = int(v["delay"].(float64))
-    if expectedCodes, ok := v["expected_codes"].(string); ok {
-        info["expected_codes"] = expectedCodes
-    } else {
-        info["expected_codes"] = ""
+// lbMonitorResource is the schema of the health monitor of loadbalancers and
+// listeners.
+func lbMonitorResource() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "delay": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Computed:	true,
+            },
+
+            "expected_codes": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                Computed:	true,
+            },
+
+            "http_method": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                Computed:	true,
+            },
+
+            "max_retries": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Computed:	true,
+            },
+
+            "monitor_type": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                Computed:	true,
+            },
+
+            "timeout": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Computed:	true,
+            },
+
+            "url_path": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                Computed:	true,
+            },
+        },
     }
+}
 
-    if httpMethod, ok := v["http_method"].(string); ok {
-        info["http_method"] = httpMethod
-    } else {
-        info["http_method"] = ""
+// lbMemberHash identifies a member of the members attribute by its ip, port
+// and weight, leaving out its computed status so that it does not cause a
+// diff. The SDK only diffs the elements of a set whose hash changed: a
+// reweighted member is planned as the removal of its old weight and the
+// addition of the new one, which Update sends as a single PATCH of the whole
+// member list.
+func lbMemberHash(v interface{}) int {
+    member, ok := v.(map[string]interface{})
+    if !ok {
+        return 0
     }
+    return hashcode.String(fmt.Sprintf("%v:%v:%v", member["ip"], member["port"], member["weight"]))
+}
 
-    info["max_retries"] = int(v["max_retries"].(float64))
-    info["monitor_type"] = v["monitor_type"].(string)
-    info["timeout"] = int(v["timeout"].(float64))
-    if urlPath, ok := v["url_path"].(string); ok {
-        info["url_path"] = urlPath
-    } else {
-        info["url_path"] = ""
+// expandLBMembersInfo returns the members of the members attribute.
+func expandLBMembersInfo(v *schema.Set) []client.LoadBalancerMember {
+    members := make([]client.LoadBalancerMember, 0, v.Len())
+    for _, member := range v.List() {
+        detail := member.(map[string]interface{})
+        members = append(members, client.LoadBalancerMember{
+            IP:		detail["ip"].(string),
+            Port:	detail["port"].(int),
+            Weight:	detail["weight"].(int),
+        })
     }
+    return members
+}
+
+func flattenLBMembersInfo(v []client.LoadBalancerMember) *schema.Set {
+    membersInfo := schema.NewSet(lbMemberHash, nil)
+    for _, member := range v {
+        info := make(map[string]interface{})
+        info["ip"] = member.IP
+        info["port"] = member.Port
+        info["status"] = member.Status
+        info["weight"] = member.Weight
+        membersInfo.Add(info)
+    }
+    return membersInfo
+}
 
+func flattenLBMonitorInfo(v *client.LoadBalancerMonitor) []interface{} {
+    if v == nil {
+        return nil
+    }
+    monitorInfo := make([]interface{}, 1)
+    info := make(map[string]interface{})
+    info["delay"] = v.Delay
+    info["expected_codes"] = v.ExpectedCodes
+    info["http_method"] = v.HTTPMethod
+    info["max_retries"] = v.MaxRetries
+    info["monitor_type"] = v.MonitorType
+    info["timeout"] = v.Timeout
+    info["url_path"] = v.URLPath
     monitorInfo[0] = info
     return monitorInfo
 }
 
-func lbStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
+// expandLBMonitorInfo returns the health monitor of the monitor attribute,
+// or nil if it is not set.
+func expandLBMonitorInfo(v []interface{}) *client.LoadBalancerMonitor {
+    if len(v) == 0 || v[0] == nil {
+        return nil
+    }
+    info := v[0].(map[string]interface{})
+    return &client.LoadBalancerMonitor{
+        Delay:		info["delay"].(int),
+        ExpectedCodes:	info["expected_codes"].(string),
+        HTTPMethod:	info["http_method"].(string),
+        MaxRetries:	info["max_retries"].(int),
+        MonitorType:	info["monitor_type"].(string),
+        Timeout:	info["timeout"].(int),
+        URLPath:	info["url_path"].(string),
+    }
+}
 
-        if err != nil {
-            return nil, "", err
-        }
+// lbMonitorUpdateOpts returns the update of the health monitor to monitor,
+// the planned monitor of d. Only the fields which changed are sent, unless
+// the monitor is added.
+func lbMonitorUpdateOpts(d *schema.ResourceData, monitor *client.LoadBalancerMonitor) client.LoadBalancerMonitorUpdateOpts {
+    oldMonitor, _ := d.GetChange("monitor")
+    added := len(oldMonitor.([]interface{})) == 0
+    changed := func(field string) bool {
+        return added || d.HasChange("monitor.0." + field)
+    }
 
-        return data, data["status"].(string), nil
+    var opts client.LoadBalancerMonitorUpdateOpts
+    if changed("delay") {
+        opts.Delay = &monitor.Delay
+    }
+    if changed("expected_codes") {
+        opts.ExpectedCodes = &monitor.ExpectedCodes
+    }
+    if changed("http_method") {
+        opts.HTTPMethod = &monitor.HTTPMethod
+    }
+    if changed("max_retries") {
+        opts.MaxRetries = &monitor.MaxRetries
+    }
+    if changed("monitor_type") {
+        opts.MonitorType = &monitor.MonitorType
     }
+    if changed("timeout") {
+        opts.Timeout = &monitor.Timeout
+    }
+    if changed("url_path") {
+        opts.URLPath = &monitor.URLPath
+    }
+    return opts
+}
+
+// lbWaiter waits for the loadbalancer lbID on platform.
+func lbWaiter(ctx context.Context, config *PConfig, platform string, lbID string) *waiter {
+    return newWaiter(ctx, config, "loadbalancer", lbID,
+        func() (interface{}, error) {
+            return config.API.LoadBalancers.Get(ctx, platform, lbID)
+        },
+        func(v interface{}) (string, string) {
+            lb := v.(*client.LoadBalancer)
+            return lb.Status, lb.StatusReason
+        })
+}
+
+// listenerWaiter waits for the listener listenerID on platform.
+func listenerWaiter(ctx context.Context, config *PConfig, platform string, listenerID string) *waiter {
+    return newWaiter(ctx, config, "listener", listenerID,
+        func() (interface{}, error) {
+            return config.API.Listeners.Get(ctx, platform, listenerID)
+        },
+        func(v interface{}) (string, string) {
+            listener := v.(*client.Listener)
+            return listener.Status, listener.StatusReason
+        })
+}
+
+// lbMutexKey is the key of osMutexKV serializing the changes to the members
+// of the loadbalancer lbID on platform, which are always replaced as a whole,
+// and to its listeners.
+func lbMutexKey(platform string, lbID string) string {
+    return fmt.Sprintf("apigw_loadbalancer/%s/%s", platform, lbID)
 }
 
-func lbStateRefreshForDeletedFunc(
+// updateLBMembers replaces the members of the loadbalancer lbID on platform
+// by the result of change, which is given the current members. An error
+// retrieving the loadbalancer is returned as is. The
+// loadbalancer is locked until the update is complete, so that concurrent
+// updates do not undo each other.
+func updateLBMembers(
+        ctx context.Context,
         config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
+        platform string,
+        lbID string,
+        timeout time.Duration,
+        change func([]client.LoadBalancerMember) ([]client.LoadBalancerMember, error)) error {
+    key := lbMutexKey(platform, lbID)
+    osMutexKV.Lock(key)
+    defer osMutexKV.Unlock(key)
+
+    lb, err := config.API.LoadBalancers.Get(ctx, platform, lbID)
+    if err != nil {
+        return err
+    }
 
+    // Start from the members of a loadbalancer which is not being updated.
+    if status := strings.ToUpper(lb.Status); status == "BUILD" || status == "UPDATING" {
+        v, err := lbWaiter(ctx, config, platform, lbID).Wait(
+            []string{"BUILD", "UPDATING"}, []string{"ACTIVE", "DOWN"}, timeout)
         if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
+            return fmt.Errorf("Error waiting for apigw_loadbalancer %s to become ACTIVE: %v", lbID, err)
         }
+        lb = v.(*client.LoadBalancer)
+    }
 
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
+    members, err := change(lb.Members)
+    if err != nil {
+        return err
+    }
+    for i := range members {
+        members[i].Status = ""
+    }
 
-        if err != nil {
-            return nil, "", err
-        }
+    opts := client.LoadBalancerUpdateOpts {
+        Members:	&members,
+    }
+    if err := config.API.LoadBalancers.Update(ctx, platform, lbID, opts); err != nil {
+        return fmt.Errorf("Error updating apigw_loadbalancer %s on %s: %v", lbID, platform, err)
+    }
 
-        return data, data["status"].(string), nil
+    _, err = lbWaiter(ctx, config, platform, lbID).Wait(
+        []string{"UPDATING"}, []string{"ACTIVE"}, timeout)
+    if err != nil {
+        return fmt.Errorf("Error waiting for apigw_loadbalancer %s to become ACTIVE: %v", lbID, err)
+    }
+    return nil
+}
+
+// findLBMember returns the index of the member with ip and port in members,
+// or -1.
+func findLBMember(members []client.LoadBalancerMember, ip string, port int) int {
+    for i, member := range members {
+        if member.IP == ip && member.Port == port {
+            return i
+        }
     }
+    return -1
 }
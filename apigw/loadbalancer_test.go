@@ -0,0 +1,468 @@
+package apigw
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+    "apigw_plugin/terraform-provider-apigw/apigw/mock"
+)
+
+func member(ip string, port, weight int) map[string]interface{} {
+    return map[string]interface{}{"ip": ip, "port": port, "weight": weight}
+}
+
+func TestLBMemberHash(t *testing.T) {
+    a := member("10.0.0.1", 80, 1)
+
+    cases := []struct {
+        name	string
+        member	interface{}
+        same	bool
+    }{
+        {"weight", member("10.0.0.1", 80, 2), false},
+        {"status", map[string]interface{}{"ip": "10.0.0.1", "port": 80, "weight": 1, "status": "ONLINE"}, true},
+        {"port", member("10.0.0.1", 8080, 1), false},
+        {"ip", member("10.0.0.2", 80, 1), false},
+        {"missing port", map[string]interface{}{"ip": "10.0.0.1"}, false},
+    }
+
+    for _, c := range cases {
+        if same := lbMemberHash(c.member) == lbMemberHash(a); same != c.same {
+            t.Errorf("%s: same hash = %v, want %v", c.name, same, c.same)
+        }
+    }
+
+    if got := lbMemberHash("10.0.0.1"); got != 0 {
+        t.Errorf("lbMemberHash of a string = %d, want 0", got)
+    }
+}
+
+func TestFlattenLBMembersInfo(t *testing.T) {
+    members := []client.LoadBalancerMember{
+        {IP: "10.0.0.1", Port: 80, Weight: 1, Status: "ONLINE"},
+        {IP: "10.0.0.2", Port: 8080, Weight: 2, Status: "OFFLINE"},
+    }
+
+    d := schema.TestResourceDataRaw(t, resourceLoadBalancer().Schema, map[string]interface{}{})
+    if err := d.Set("members", flattenLBMembersInfo(members)); err != nil {
+        t.Fatalf("Unable to set members: %v", err)
+    }
+
+    set := d.Get("members").(*schema.Set)
+    if set.Len() != 2 {
+        t.Fatalf("members has %d members, want 2", set.Len())
+    }
+    for _, v := range set.List() {
+        if m := v.(map[string]interface{}); m["ip"] == "10.0.0.2" && m["status"] != "OFFLINE" {
+            t.Errorf("member = %v, want status OFFLINE", m)
+        }
+    }
+
+    // The status is not sent back to the gateway.
+    got := expandLBMembersInfo(set)
+    sort.Slice(got, func(i, j int) bool { return got[i].IP < got[j].IP })
+    want := []client.LoadBalancerMember{
+        {IP: "10.0.0.1", Port: 80, Weight: 1},
+        {IP: "10.0.0.2", Port: 8080, Weight: 2},
+    }
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("expandLBMembersInfo = %v, want %v", got, want)
+    }
+}
+
+func TestLBMemberZeroWeight(t *testing.T) {
+    // A drained member keeps its weight of 0 instead of getting the default
+    // of the gateway.
+    body, err := json.Marshal(client.LoadBalancerMember{IP: "10.0.0.1", Port: 80})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := `{"ip":"10.0.0.1","port":80,"weight":0}`; string(body) != want {
+        t.Errorf("member = %s, want %s", body, want)
+    }
+}
+
+func TestLBMemberReweightDiff(t *testing.T) {
+    code := lbMemberHash(member("10.0.0.1", 80, 1))
+    state := &terraform.InstanceState{
+        ID: "1",
+        Attributes: map[string]string{
+            "id":				"1",
+            "create_time":			"2024-05-02T08:15:04Z",
+            "lb_method":			"ROUND_ROBIN",
+            "members.#":			"1",
+            fmt.Sprintf("members.%d.ip", code):		"10.0.0.1",
+            fmt.Sprintf("members.%d.port", code):	"80",
+            fmt.Sprintf("members.%d.status", code):	"ONLINE",
+            fmt.Sprintf("members.%d.weight", code):	"1",
+            "monitor.#":			"0",
+            "name":				"lb",
+            "platform":				"platform",
+            "private_net":			"1",
+            "protocol":				"HTTP",
+            "protocol_port":			"80",
+            "user.%":				"0",
+            "waf.%":				"0",
+        },
+    }
+    config := func(weight int) *terraform.ResourceConfig {
+        return terraform.NewResourceConfigRaw(map[string]interface{}{
+            "lb_method":	"ROUND_ROBIN",
+            "members":	[]interface{}{member("10.0.0.1", 80, weight)},
+            "name":		"lb",
+            "platform":	"platform",
+            "private_net":	"1",
+            "protocol":	"HTTP",
+            "protocol_port":	80,
+        })
+    }
+
+    diff, err := resourceLoadBalancer().Diff(state, config(1), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !diff.Empty() {
+        t.Errorf("diff of unchanged members = %v, want it empty", diff.Attributes)
+    }
+
+    // A reweighted member is planned as the removal of the old member and
+    // the addition of the new one, without replacing the loadbalancer.
+    diff, err = resourceLoadBalancer().Diff(state, config(3), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if diff.RequiresNew() {
+        t.Error("reweighting a member replaces the loadbalancer")
+    }
+    newCode := lbMemberHash(member("10.0.0.1", 80, 3))
+    if attr := diff.Attributes[fmt.Sprintf("members.%d.weight", code)]; attr == nil || !attr.NewRemoved {
+        t.Errorf("diff of the old member = %v, want it removed", attr)
+    }
+    if attr := diff.Attributes[fmt.Sprintf("members.%d.weight", newCode)]; attr == nil || attr.New != "3" {
+        t.Errorf("diff of the new member = %v, want weight 3", attr)
+    }
+}
+
+func TestFlattenLBMonitorInfo(t *testing.T) {
+    if got := flattenLBMonitorInfo(nil); got != nil {
+        t.Errorf("flattenLBMonitorInfo(nil) = %v, want nil", got)
+    }
+
+    got := flattenLBMonitorInfo(&client.LoadBalancerMonitor{
+        Delay:		5,
+        MaxRetries:	3,
+        MonitorType:	"HTTP",
+        Timeout:	2,
+        URLPath:	"/health",
+    })
+    want := []interface{}{map[string]interface{}{
+        "delay":		5,
+        "expected_codes":	"",
+        "http_method":		"",
+        "max_retries":		3,
+        "monitor_type":		"HTTP",
+        "timeout":		2,
+        "url_path":		"/health",
+    }}
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("flattenLBMonitorInfo = %v, want %v", got, want)
+    }
+}
+
+func TestUpdateLBMembersConcurrently(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    config := newMockProviderConfig(t, server)
+    ctx := context.Background()
+
+    lb, err := config.API.LoadBalancers.Create(ctx, "openstack-mock", client.LoadBalancerCreateOpts{
+        LBMethod:	"ROUND_ROBIN",
+        Name:		"lb",
+        PrivateNet:	"1",
+        Protocol:	"HTTP",
+        ProtocolPort:	80,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    lbID := lb.ID.String()
+
+    // Members are replaced as a whole, so without the lock the additions
+    // would undo each other.
+    var wg sync.WaitGroup
+    for i := 0; i < 5; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            err := updateLBMembers(ctx, config, "openstack-mock", lbID, time.Minute,
+                func(members []client.LoadBalancerMember) ([]client.LoadBalancerMember, error) {
+                    member := client.LoadBalancerMember{IP: fmt.Sprintf("10.0.0.%d", i), Port: 80, Weight: 1}
+                    return append(members, member), nil
+                })
+            if err != nil {
+                t.Error(err)
+            }
+        }(i)
+    }
+    wg.Wait()
+
+    lb, err = config.API.LoadBalancers.Get(ctx, "openstack-mock", lbID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for i := 0; i < 5; i++ {
+        if findLBMember(lb.Members, fmt.Sprintf("10.0.0.%d", i), 80) < 0 {
+            t.Errorf("member 10.0.0.%d is missing from %v", i, lb.Members)
+        }
+    }
+}
+
+func TestCreateListenerLocksLoadBalancer(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    config := newMockProviderConfig(t, server)
+    ctx := context.Background()
+
+    lb, err := config.API.LoadBalancers.Create(ctx, "openstack-mock", client.LoadBalancerCreateOpts{
+        LBMethod:	"ROUND_ROBIN",
+        Name:		"lb",
+        PrivateNet:	"1",
+        Protocol:	"HTTP",
+        ProtocolPort:	80,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    lbID := lb.ID.String()
+
+    d := schema.TestResourceDataRaw(t, resourceLoadBalancerListener().Schema, map[string]interface{}{
+        "lb_method":		"ROUND_ROBIN",
+        "loadbalancer":		lbID,
+        "platform":		"openstack-mock",
+        "protocol":		"TCP",
+        "protocol_port":	8443,
+    })
+
+    // The listener is not created while the loadbalancer is changed.
+    key := lbMutexKey("openstack-mock", lbID)
+    osMutexKV.Lock(key)
+    done := make(chan error)
+    go func() {
+        done <- resourceLoadBalancerListenerCreate(d, config)
+    }()
+
+    time.Sleep(100 * time.Millisecond)
+    listeners, err := config.API.Listeners.List(ctx, "openstack-mock", client.ListenerListOpts{LoadBalancer: lbID})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(listeners) > 0 {
+        t.Errorf("listener created while the loadbalancer is locked: %v", listeners)
+    }
+
+    osMutexKV.Unlock(key)
+    if err := <-done; err != nil {
+        t.Fatal(err)
+    }
+    if d.Id() == "" {
+        t.Error("listener not created after the loadbalancer is unlocked")
+    }
+}
+
+func TestExpandLBMonitorInfo(t *testing.T) {
+    if got := expandLBMonitorInfo(nil); got != nil {
+        t.Errorf("expandLBMonitorInfo(nil) = %v, want nil", got)
+    }
+    if got := expandLBMonitorInfo([]interface{}{nil}); got != nil {
+        t.Errorf("expandLBMonitorInfo of an empty block = %v, want nil", got)
+    }
+
+    monitor := &client.LoadBalancerMonitor{
+        Delay:		5,
+        ExpectedCodes:	"200",
+        HTTPMethod:	"GET",
+        MaxRetries:	3,
+        MonitorType:	"HTTP",
+        Timeout:	2,
+        URLPath:	"/health",
+    }
+    if got := expandLBMonitorInfo(flattenLBMonitorInfo(monitor)); !reflect.DeepEqual(got, monitor) {
+        t.Errorf("expandLBMonitorInfo = %v, want %v", got, monitor)
+    }
+}
+
+func TestLBMonitorUpdateOpts(t *testing.T) {
+    state := &terraform.InstanceState{
+        ID: "1",
+        Attributes: map[string]string{
+            "id":			"1",
+            "lb_method":		"ROUND_ROBIN",
+            "members.#":		"0",
+            "monitor.#":		"1",
+            "monitor.0.delay":		"5",
+            "monitor.0.expected_codes":	"200",
+            "monitor.0.http_method":	"GET",
+            "monitor.0.max_retries":	"3",
+            "monitor.0.monitor_type":	"HTTP",
+            "monitor.0.timeout":	"3",
+            "monitor.0.url_path":	"/",
+            "name":			"lb",
+            "platform":			"platform",
+            "private_net":		"1",
+            "protocol":			"HTTP",
+            "protocol_port":		"80",
+        },
+    }
+    updateBody := func(state *terraform.InstanceState, monitor map[string]interface{}) string {
+        r := resourceLoadBalancer()
+        config := terraform.NewResourceConfigRaw(map[string]interface{}{
+            "lb_method":	"ROUND_ROBIN",
+            "monitor":	[]interface{}{monitor},
+            "name":		"lb",
+            "platform":	"platform",
+            "private_net":	"1",
+            "protocol":	"HTTP",
+            "protocol_port":	80,
+        })
+        diff, err := r.Diff(state, config, nil)
+        if err != nil {
+            t.Fatal(err)
+        }
+        d, err := schema.InternalMap(r.Schema).Data(state, diff)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        opts := client.LoadBalancerUpdateOpts{
+            LoadBalancerMonitorUpdateOpts: lbMonitorUpdateOpts(d, expandLBMonitorInfo(d.Get("monitor").([]interface{}))),
+        }
+        body, err := json.Marshal(opts)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return string(body)
+    }
+
+    // Only the changed fields are sent, including those changed to 0.
+    got := updateBody(state, map[string]interface{}{
+        "delay":		10,
+        "expected_codes":	"200",
+        "http_method":		"GET",
+        "max_retries":		0,
+        "monitor_type":		"HTTP",
+        "timeout":		3,
+        "url_path":		"/",
+    })
+    if want := `{"delay":10,"max_retries":0}`; got != want {
+        t.Errorf("update body = %s, want %s", got, want)
+    }
+
+    // An added monitor is sent in full.
+    added := state.DeepCopy()
+    for key := range added.Attributes {
+        if strings.HasPrefix(key, "monitor.") {
+            delete(added.Attributes, key)
+        }
+    }
+    added.Attributes["monitor.#"] = "0"
+    got = updateBody(added, map[string]interface{}{"monitor_type": "TCP"})
+    if want := `{"delay":0,"expected_codes":"","http_method":"","max_retries":0,"monitor_type":"TCP","timeout":0,"url_path":""}`; got != want {
+        t.Errorf("update body = %s, want %s", got, want)
+    }
+
+    body, err := json.Marshal(client.LoadBalancerUpdateOpts{LBMethod: "ROUND_ROBIN"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if string(body) != `{"lb_method":"ROUND_ROBIN"}` {
+        t.Errorf("update body without monitor = %s", body)
+    }
+}
+
+// FuzzLBMemberHash feeds arbitrary JSON as member, whose integral numbers
+// are converted to int the way terraform reads them from state.
+func FuzzLBMemberHash(f *testing.F) {
+    f.Add([]byte(`{"ip": "10.0.0.1", "port": 80, "weight": 1}`))
+    f.Add([]byte(`{"ip": "10.0.0.1", "status": null}`))
+    f.Add([]byte(`null`))
+
+    f.Fuzz(func(t *testing.T, data []byte) {
+        v, err := decodeFuzzJSON(data)
+        if err != nil {
+            return
+        }
+
+        hash := lbMemberHash(v)
+        if hash < 0 {
+            t.Errorf("lbMemberHash(%s) = %d, want it not negative", data, hash)
+        }
+        if m, ok := v.(map[string]interface{}); ok {
+            m["status"] = "ONLINE"
+            if lbMemberHash(m) != hash {
+                t.Errorf("lbMemberHash(%s) depends on status", data)
+            }
+        }
+    })
+}
+
+func FuzzFlattenLBMonitorInfo(f *testing.F) {
+    f.Add([]byte(`{"delay": 5, "max_retries": 3, "monitor_type": "HTTP", "url_path": "/"}`))
+    f.Add([]byte(`null`))
+    f.Add([]byte(`{}`))
+
+    f.Fuzz(func(t *testing.T, data []byte) {
+        var monitor *client.LoadBalancerMonitor
+        if err := json.Unmarshal(data, &monitor); err != nil {
+            return
+        }
+
+        d := schema.TestResourceDataRaw(t, resourceLoadBalancer().Schema, map[string]interface{}{})
+        if err := d.Set("monitor", flattenLBMonitorInfo(monitor)); err != nil {
+            t.Errorf("Unable to set monitor of %s: %v", data, err)
+        }
+    })
+}
+
+// decodeFuzzJSON decodes data, converting integral numbers to int.
+func decodeFuzzJSON(data []byte) (interface{}, error) {
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.UseNumber()
+
+    var value interface{}
+    if err := decoder.Decode(&value); err != nil {
+        return nil, err
+    }
+    return convertFuzzNumbers(value), nil
+}
+
+func convertFuzzNumbers(value interface{}) interface{} {
+    switch v := value.(type) {
+    case json.Number:
+        if i, err := v.Int64(); err == nil {
+            return int(i)
+        }
+        f, _ := v.Float64()
+        return f
+    case []interface{}:
+        for i := range v {
+            v[i] = convertFuzzNumbers(v[i])
+        }
+    case map[string]interface{}:
+        for key := range v {
+            v[key] = convertFuzzNumbers(v[key])
+        }
+    }
+    return value
+}
@@ -0,0 +1,128 @@
+package apigw
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "sort"
+    "strings"
+    "time"
+)
+
+const redactedValue = "<redacted>"
+
+// sensitiveHeaders are never written to the logs.
+var sensitiveHeaders = []string{
+    "x-api-key",
+}
+
+// sensitiveFields are the JSON body fields holding secrets.
+var sensitiveFields = map[string]bool{
+    "access_key":	true,
+    "private_key":	true,
+    "psk":		true,
+    "secret_key":	true,
+}
+
+// redactHeaders returns a copy of header with the values of sensitive
+// headers replaced. Names are compared case-insensitively, so that headers
+// which were not added in their canonical form are redacted as well.
+func redactHeaders(header http.Header) http.Header {
+    redacted := header.Clone()
+    for name := range redacted {
+        for _, sensitive := range sensitiveHeaders {
+            if strings.EqualFold(name, sensitive) {
+                redacted[name] = []string{redactedValue}
+            }
+        }
+    }
+    return redacted
+}
+
+// redactBody replaces the values of sensitive fields anywhere in a JSON
+// document. Bodies which are not JSON are returned unchanged.
+func redactBody(body []byte) []byte {
+    if len(body) == 0 {
+        return body
+    }
+
+    var data interface{}
+    if err := json.Unmarshal(body, &data); err != nil {
+        return body
+    }
+
+    buf := new(bytes.Buffer)
+    encoder := json.NewEncoder(buf)
+    encoder.SetEscapeHTML(false)
+    if err := encoder.Encode(redactValue(data)); err != nil {
+        return body
+    }
+    return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+func redactValue(v interface{}) interface{} {
+    switch value := v.(type) {
+    case map[string]interface{}:
+        for key, item := range value {
+            if sensitiveFields[strings.ToLower(key)] && item != nil {
+                value[key] = redactedValue
+            } else {
+                value[key] = redactValue(item)
+            }
+        }
+    case []interface{}:
+        for i, item := range value {
+            value[i] = redactValue(item)
+        }
+    }
+    return v
+}
+
+func formatHeaders(header http.Header) string {
+    names := make([]string, 0, len(header))
+    for name := range header {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    var b strings.Builder
+    for _, name := range names {
+        fmt.Fprintf(&b, "\n%s: %s", name, strings.Join(header[name], ", "))
+    }
+    return b.String()
+}
+
+// logRequest writes an outgoing request to the debug log.
+func (pc *ProviderClient) logRequest(req *http.Request, payload []byte) {
+    if !pc.Debug {
+        return
+    }
+
+    log.Printf("[DEBUG] APIGW Request: %s %s%s\n\n%s",
+        req.Method, req.URL, formatHeaders(redactHeaders(req.Header)), redactBody(payload))
+}
+
+// logResponse writes the response to a request, or the error which prevented
+// one, to the debug log.
+func (pc *ProviderClient) logResponse(
+        req *http.Request,
+        resp *http.Response,
+        body []byte,
+        latency time.Duration,
+        err error) {
+    if !pc.Debug {
+        return
+    }
+
+    if resp == nil {
+        log.Printf("[DEBUG] APIGW Response: %s %s failed after %s: %v",
+            req.Method, req.URL, latency, err)
+        return
+    }
+
+    log.Printf("[DEBUG] APIGW Response: %s %s %s in %s%s\n\n%s",
+        req.Method, req.URL, resp.Status, latency,
+        formatHeaders(redactHeaders(resp.Header)), redactBody(body))
+}
@@ -0,0 +1,131 @@
+package apigw
+
+import (
+    "bytes"
+    "log"
+    "net/http"
+    "net/url"
+    "os"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestRedactHeaders(t *testing.T) {
+    cases := []struct {
+        name		string
+        header		http.Header
+        want		http.Header
+    }{
+        {
+            "canonical",
+            http.Header{"X-Api-Key": {"secret"}, "X-Api-Host": {"dc1"}},
+            http.Header{"X-Api-Key": {redactedValue}, "X-Api-Host": {"dc1"}},
+        },
+        {
+            "lower case",
+            http.Header{"x-api-key": {"secret"}},
+            http.Header{"x-api-key": {redactedValue}},
+        },
+        {
+            "upper case",
+            http.Header{"X-API-KEY": {"secret", "other"}},
+            http.Header{"X-API-KEY": {redactedValue}},
+        },
+        {
+            "no key",
+            http.Header{"Content-Type": {"application/json"}},
+            http.Header{"Content-Type": {"application/json"}},
+        },
+    }
+
+    for _, c := range cases {
+        original := c.header.Clone()
+        if got := redactHeaders(c.header); !reflect.DeepEqual(got, c.want) {
+            t.Errorf("%s: redactHeaders = %v, want %v", c.name, got, c.want)
+        }
+        if !reflect.DeepEqual(c.header, original) {
+            t.Errorf("%s: redactHeaders changed its argument to %v", c.name, c.header)
+        }
+    }
+}
+
+func TestRedactBody(t *testing.T) {
+    cases := []struct {
+        name		string
+        body		string
+        want		string
+    }{
+        {"empty", ``, ``},
+        {"not JSON", `psk=secret`, `psk=secret`},
+        {"truncated JSON", `{"psk": "secret"`, `{"psk": "secret"`},
+        {"no secrets", `{"name":"vpn"}`, `{"name":"vpn"}`},
+        {"top level", `{"psk":"secret","name":"vpn"}`, `{"name":"vpn","psk":"<redacted>"}`},
+        {"upper case", `{"PSK":"secret"}`, `{"PSK":"<redacted>"}`},
+        {"null", `{"psk":null}`, `{"psk":null}`},
+        {
+            "nested object",
+            `{"key":{"access_key":"AK","secret_key":"SK","user":"u"}}`,
+            `{"key":{"access_key":"<redacted>","secret_key":"<redacted>","user":"u"}}`,
+        },
+        {
+            "array",
+            `{"certificates":[{"name":"a","private_key":"-----BEGIN"},{"name":"b"}]}`,
+            `{"certificates":[{"name":"a","private_key":"<redacted>"},{"name":"b"}]}`,
+        },
+        {
+            "top level array",
+            `[{"connection":{"psk":"secret"}},"psk"]`,
+            `[{"connection":{"psk":"<redacted>"}},"psk"]`,
+        },
+        {
+            "object value",
+            `{"secret_key":{"value":"SK"}}`,
+            `{"secret_key":"<redacted>"}`,
+        },
+    }
+
+    for _, c := range cases {
+        if got := string(redactBody([]byte(c.body))); got != c.want {
+            t.Errorf("%s: redactBody = %s, want %s", c.name, got, c.want)
+        }
+    }
+}
+
+func TestLogDisabled(t *testing.T) {
+    t.Setenv("APIGW_HTTP_DEBUG", "")
+    provider := Provider().(*schema.Provider)
+    debug, err := provider.Schema["http_debug"].DefaultValue()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if debug != false {
+        t.Fatalf("http_debug without APIGW_HTTP_DEBUG = %v, want false", debug)
+    }
+
+    var buf bytes.Buffer
+    log.SetOutput(&buf)
+    defer log.SetOutput(os.Stderr)
+
+    pc := &ProviderClient{}
+    req := &http.Request{
+        Method:	http.MethodPost,
+        URL:	&url.URL{Scheme: "https", Host: "apigw.example.com", Path: "/vpn"},
+        Header:	http.Header{"X-Api-Key": {"secret"}},
+    }
+    resp := &http.Response{Status: "200 OK", StatusCode: http.StatusOK}
+    pc.logRequest(req, []byte(`{"psk":"secret"}`))
+    pc.logResponse(req, resp, []byte(`{"psk":"secret"}`), time.Second, nil)
+    if buf.Len() != 0 {
+        t.Errorf("logged without http_debug: %s", buf.String())
+    }
+
+    pc.Debug = true
+    pc.logRequest(req, []byte(`{"psk":"secret"}`))
+    pc.logResponse(req, resp, []byte(`{"psk":"secret"}`), time.Second, nil)
+    if logged := buf.String(); logged == "" || bytes.Contains(buf.Bytes(), []byte("secret")) {
+        t.Errorf("logged with http_debug: %q, want the redacted request and response", logged)
+    }
+}
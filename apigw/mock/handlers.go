@@ -0,0 +1,944 @@
+package mock
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+type handler func(s *Server, r *request) response
+
+// handlers serves the collections of api/v4/<platform>/.
+var handlers = map[string]handler{
+    "auto_scaling_policies":	servePolicies(autoScalingPolicyFields, nil),
+    "certificates":		serveCertificates,
+    "firewall_rules":		serveFirewallRules,
+    "firewalls":		serveFirewalls,
+    "ike_policies":		servePolicies(ikePolicyFields, ikePolicyDefaults),
+    "images":			serveImages,
+    "ipsec_policies":		servePolicies(ipsecPolicyFields, ipsecPolicyDefaults),
+    "listeners":		serveListeners,
+    "loadbalancers":		serveLoadBalancers,
+    "networks":			serveNetworks,
+    "projects":			serveProjects,
+    "security_group_rules":	serveSecurityGroupRules,
+    "security_groups":		serveSecurityGroups,
+    "servers":			serveServers,
+    "sites":			serveSites,
+    "snapshots":		serveSnapshots,
+    "solutions":		serveSolutions,
+    "volumes":			serveVolumes,
+    "vpn_services":		serveVPNServices,
+}
+
+// serveCRUD serves listing, reading and deleting objects, the parts all
+// collections have in common. deleting is the status of an object being
+// deleted, objects without status keep theirs if it is empty.
+func serveCRUD(s *Server, r *request, deleting string, match func(*object) bool) (response, bool) {
+    switch {
+    case r.id == "" && r.Method == "GET":
+        return response{http.StatusOK, s.list(r.platform, r.collection, match)}, true
+    case r.id != "" && r.sub == "" && r.Method == "GET":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound(), true
+        }
+        return response{http.StatusOK, obj.data}, true
+    case r.id != "" && r.sub == "" && r.Method == "DELETE":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound(), true
+        }
+        if deleting != "" {
+            obj.data["status"] = deleting
+        }
+        obj.polls = s.Polls
+        obj.done = remove
+        return response{status: http.StatusNoContent}, true
+    }
+    return response{}, false
+}
+
+func methodNotAllowed(r *request) response {
+    return errorResponse(http.StatusMethodNotAllowed, "Method \"%s\" not allowed.", r.Method)
+}
+
+func serveSites(s *Server, r *request) response {
+    if resp, ok := serveCRUD(s, r, "Deleting", matchQuery(r, "project", "name")); ok {
+        return resp
+    }
+
+    switch {
+    case r.id == "" && r.Method == "POST":
+        if resp := required(r, "name", "project", "solution"); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "name", "desc", "project", "solution")
+        data["ext_net"] = ""
+        data["public_ip"] = ""
+        data["servers"] = []interface{}{}
+        obj := s.create(r.platform, r.collection, data)
+        s.transition(obj, r.collection, "Initializing", "Error", func(o *object) {
+            o.data["status"] = "Ready"
+            o.data["public_ip"] = fmt.Sprintf("203.0.113.%d", o.id % 256)
+            o.data["ext_net"] = o.data["public_ip"]
+            o.data["servers"] = []interface{}{s.createServer(r.platform, o)}
+        })
+        return response{http.StatusCreated, obj.data}
+    case r.id != "" && r.sub == "container" && r.Method == "GET":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound()
+        }
+        return response{http.StatusOK, containerOf(obj)}
+    }
+    return methodNotAllowed(r)
+}
+
+// createServer adds a server with a security group to the site obj.
+func (s *Server) createServer(platform string, obj *object) map[string]interface{} {
+    server := s.create(platform, "servers", map[string]interface{}{
+        "flavor_id":		1,
+        "status":		"ACTIVE",
+        "auto_scaling_policy":	nil,
+    })
+    server.data["hostname"] = fmt.Sprintf("%s-%d", obj.data["name"], server.id)
+
+    s.create(platform, "security_groups", map[string]interface{}{
+        "name":			fmt.Sprintf("%s-sg", server.data["hostname"]),
+        "project":		obj.data["project"],
+        "server":		fmt.Sprintf("%d", server.id),
+        "security_group_rules":	[]interface{}{},
+    })
+    return server.data
+}
+
+// containerOf returns the pods and services of the container site obj. The
+// pod of a failed site carries its status reason.
+func containerOf(obj *object) map[string]interface{} {
+    pod := map[string]interface{}{
+        "name":		fmt.Sprintf("%s-pod", obj.data["name"]),
+        "flavor":	"1 GPU",
+        "status":	"Running",
+        "reason":	"",
+        "message":	"",
+        "container":	[]interface{}{},
+    }
+    if obj.data["status"] == "Error" {
+        pod["status"] = "Failed"
+        pod["reason"] = "Error"
+        pod["message"] = obj.data["status_reason"]
+    }
+
+    return map[string]interface{}{
+        "Pod":		[]interface{}{pod},
+        "Service":	[]interface{}{},
+    }
+}
+
+func serveNetworks(s *Server, r *request) response {
+    if resp, ok := serveCRUD(s, r, "DELETING", matchQuery(r, "project")); ok {
+        return resp
+    }
+
+    if r.id == "" && r.Method == "POST" {
+        if resp := required(r, "name", "cidr", "gateway", "project"); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "name", "cidr", "gateway", "project", "dns_domain", "nameservers", "with_router")
+        data["ip_version"] = 4
+        data["platform"] = r.platform
+        data["firewall"] = nil
+        obj := s.create(r.platform, r.collection, data)
+        s.transition(obj, r.collection, "BUILD", "ERROR", setStatus("ACTIVE"))
+        return response{http.StatusCreated, obj.data}
+    }
+    return methodNotAllowed(r)
+}
+
+func serveVolumes(s *Server, r *request) response {
+    if resp, ok := serveCRUD(s, r, "DELETING", matchQuery(r, "project")); ok {
+        return resp
+    }
+
+    switch {
+    case r.id == "" && r.Method == "POST":
+        if resp := required(r, "name"); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "name", "desc", "project", "size", "src_snapshot", "volume_type")
+        if _, ok := data["project"]; !ok {
+            data["project"] = DefaultProject
+        }
+        if _, ok := data["size"]; !ok {
+            data["size"] = 1
+        }
+        if _, ok := data["volume_type"]; !ok {
+            data["volume_type"] = "hdd"
+        }
+        data["is_attached"] = false
+        data["mountpoint"] = []interface{}{}
+        data["snapshot_list"] = []interface{}{}
+        obj := s.create(r.platform, r.collection, data)
+        s.transition(obj, r.collection, "CREATING", "ERROR", setStatus("AVAILABLE"))
+        return response{http.StatusCreated, obj.data}
+    case r.id != "" && r.sub == "action" && r.Method == "PUT":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound()
+        }
+        return volumeAction(s, r, obj)
+    }
+    return methodNotAllowed(r)
+}
+
+// volumeAction extends, attaches or detaches the volume obj.
+func volumeAction(s *Server, r *request, obj *object) response {
+    if resp := required(r, "status"); resp != nil {
+        return *resp
+    }
+
+    switch r.body["status"] {
+    case "extend":
+        size := r.body["size"]
+        settled := obj.data["status"]
+        s.transition(obj, r.collection, "EXTENDING", "ERROR", func(o *object) {
+            o.data["size"] = size
+            o.data["status"] = settled
+        })
+    case "attach":
+        if resp := required(r, "server"); resp != nil {
+            return *resp
+        }
+        server := r.body["server"]
+        mountpoint := r.body["mountpoint"]
+        if mountpoint == nil {
+            mountpoint = "/dev/vdb"
+        }
+        s.transition(obj, r.collection, "ATTACHING", "ERROR", func(o *object) {
+            o.data["status"] = "IN-USE"
+            o.data["is_attached"] = true
+            o.data["attached_host"] = map[string]interface{}{"id": server, "hostname": fmt.Sprintf("server-%v", server)}
+            o.data["mountpoint"] = []interface{}{mountpoint}
+        })
+    case "detach":
+        s.transition(obj, r.collection, "DETACHING", "ERROR", func(o *object) {
+            o.data["status"] = "AVAILABLE"
+            o.data["is_attached"] = false
+            o.data["attached_host"] = nil
+            o.data["mountpoint"] = []interface{}{}
+        })
+    default:
+        return errorResponse(http.StatusBadRequest, "Unknown action %v.", r.body["status"])
+    }
+    return response{status: http.StatusOK}
+}
+
+// lbMonitorFields are the fields of the health monitor of load balancers and
+// listeners, which are given flat in requests.
+var lbMonitorFields = []string{
+    "delay", "expected_codes", "http_method", "max_retries", "monitor_type", "timeout", "url_path",
+}
+
+// lbMonitor returns the health monitor of the request body, or nil if it has
+// none.
+func lbMonitor(r *request) map[string]interface{} {
+    if _, ok := r.body["monitor_type"]; !ok {
+        return nil
+    }
+    return copyFields(r, lbMonitorFields...)
+}
+
+// lbActive is the transition callback of load balancers and listeners,
+// whose members come online with them.
+func lbActive(o *object) {
+    if members, ok := o.data["members"].([]interface{}); ok {
+        for _, member := range members {
+            if m, ok := member.(map[string]interface{}); ok {
+                m["status"] = "ONLINE"
+            }
+        }
+    }
+    o.data["status"] = "ACTIVE"
+}
+
+// serveLBUpdate serves changing the pool and health monitor of a load
+// balancer or listener.
+func serveLBUpdate(s *Server, r *request) response {
+    obj := s.get(r.platform, r.collection, r.id)
+    if obj == nil {
+        return notFound()
+    }
+
+    switch {
+    case r.sub == "" && r.Method == "PATCH":
+        if resp := s.checkCertificate(r, obj.data["protocol"]); resp != nil {
+            return *resp
+        }
+        update := copyFields(r, "certificate", "lb_method", "members")
+        // The monitor fields which are given change the existing monitor,
+        // adding one requires its monitor_type.
+        monitor := copyFields(r, lbMonitorFields...)
+        if len(monitor) > 0 && obj.data["monitor"] == nil {
+            if resp := required(r, "monitor_type"); resp != nil {
+                return *resp
+            }
+        }
+        s.transition(obj, r.collection, "UPDATING", "ERROR", func(o *object) {
+            for key, value := range update {
+                o.data[key] = value
+            }
+            if len(monitor) > 0 {
+                merged := map[string]interface{}{}
+                if existing, ok := o.data["monitor"].(map[string]interface{}); ok {
+                    for key, value := range existing {
+                        merged[key] = value
+                    }
+                }
+                for key, value := range monitor {
+                    merged[key] = value
+                }
+                o.data["monitor"] = merged
+            }
+            lbActive(o)
+        })
+        return response{http.StatusOK, obj.data}
+    case r.sub == "monitor" && r.Method == "DELETE":
+        if obj.data["monitor"] == nil {
+            return notFound()
+        }
+        s.transition(obj, r.collection, "UPDATING", "ERROR", func(o *object) {
+            o.data["monitor"] = nil
+            o.data["status"] = "ACTIVE"
+        })
+        return response{status: http.StatusNoContent}
+    }
+    return methodNotAllowed(r)
+}
+
+func serveLoadBalancers(s *Server, r *request) response {
+    // Load balancers are only deleted once their listeners are.
+    if r.id != "" && r.sub == "" && r.Method == "DELETE" {
+        listeners := s.list(r.platform, "listeners", func(o *object) bool {
+            return fmt.Sprintf("%v", o.data["loadbalancer"]) == r.id
+        })
+        if len(listeners) > 0 {
+            return errorResponse(http.StatusConflict, "Load balancer %s has %d listeners.", r.id, len(listeners))
+        }
+    }
+    if resp, ok := serveCRUD(s, r, "DELETING", matchQuery(r, "name", "project", "private_net")); ok {
+        return resp
+    }
+
+    switch {
+    case r.id == "" && r.Method == "POST":
+        if resp := required(r, "name", "private_net", "protocol", "protocol_port", "lb_method"); resp != nil {
+            return *resp
+        }
+        if resp := s.checkCertificate(r, r.body["protocol"]); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "certificate", "name", "desc", "private_net", "protocol", "protocol_port", "lb_method")
+        data["members"] = []interface{}{}
+        data["vip"] = "10.0.0.10"
+        data["active_connections"] = 0
+        data["total_connections"] = 0
+        if monitor := lbMonitor(r); monitor != nil {
+            data["monitor"] = monitor
+        }
+        obj := s.create(r.platform, r.collection, data)
+        s.transition(obj, r.collection, "BUILD", "ERROR", setStatus("ACTIVE"))
+        return response{http.StatusCreated, obj.data}
+    case r.id != "":
+        return serveLBUpdate(s, r)
+    }
+    return methodNotAllowed(r)
+}
+
+// serveListeners serves the listeners of load balancers, whose ports must
+// differ from the port of the load balancer and of its other listeners.
+func serveListeners(s *Server, r *request) response {
+    if resp, ok := serveCRUD(s, r, "DELETING", matchQuery(r, "loadbalancer")); ok {
+        return resp
+    }
+
+    switch {
+    case r.id == "" && r.Method == "POST":
+        if resp := required(r, "loadbalancer", "protocol", "protocol_port", "lb_method"); resp != nil {
+            return *resp
+        }
+        lbID := fmt.Sprintf("%v", r.body["loadbalancer"])
+        lb := s.get(r.platform, "loadbalancers", lbID)
+        if lb == nil {
+            return response{http.StatusBadRequest, map[string]interface{}{
+                "loadbalancer": []string{fmt.Sprintf("Invalid pk \"%s\" - object does not exist.", lbID)},
+            }}
+        }
+        port := fmt.Sprintf("%v", r.body["protocol_port"])
+        used := s.list(r.platform, r.collection, func(o *object) bool {
+            return fmt.Sprintf("%v", o.data["loadbalancer"]) == lbID &&
+                fmt.Sprintf("%v", o.data["protocol_port"]) == port
+        })
+        if len(used) > 0 || fmt.Sprintf("%v", lb.data["protocol_port"]) == port {
+            return errorResponse(http.StatusConflict, "Port %s of load balancer %s is already in use.", port, lbID)
+        }
+
+        if resp := s.checkCertificate(r, r.body["protocol"]); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "certificate", "loadbalancer", "protocol", "protocol_port", "lb_method", "members")
+        data["loadbalancer"] = lbID
+        if _, ok := data["members"]; !ok {
+            data["members"] = []interface{}{}
+        }
+        data["monitor"] = lbMonitor(r)
+        obj := s.create(r.platform, r.collection, data)
+        s.transition(obj, r.collection, "BUILD", "ERROR", lbActive)
+        return response{http.StatusCreated, obj.data}
+    case r.id != "":
+        return serveLBUpdate(s, r)
+    }
+    return methodNotAllowed(r)
+}
+
+// checkCertificate returns an error response if the certificate of the
+// request body is missing for protocol TERMINATED_HTTPS, given for another
+// protocol, or does not exist.
+func (s *Server) checkCertificate(r *request, protocol interface{}) *response {
+    id, ok := r.body["certificate"]
+    switch {
+    case !ok && r.Method == "PATCH":
+        return nil
+    case !ok && protocol == "TERMINATED_HTTPS":
+        return &response{http.StatusBadRequest, map[string]interface{}{
+            "certificate": []string{"This field is required with protocol TERMINATED_HTTPS."},
+        }}
+    case ok && protocol != "TERMINATED_HTTPS":
+        return &response{http.StatusBadRequest, map[string]interface{}{
+            "certificate": []string{"This field is only allowed with protocol TERMINATED_HTTPS."},
+        }}
+    case ok && s.get(r.platform, "certificates", fmt.Sprintf("%v", id)) == nil:
+        return &response{http.StatusBadRequest, map[string]interface{}{
+            "certificate": []string{fmt.Sprintf("Invalid pk \"%v\" - object does not exist.", id)},
+        }}
+    }
+    return nil
+}
+
+// serveCertificates serves TLS certificates, which must match their private
+// key. The private key is never returned, and certificates used by load
+// balancers or listeners cannot be deleted.
+func serveCertificates(s *Server, r *request) response {
+    if r.id != "" && r.sub == "" && r.Method == "DELETE" {
+        using := func(o *object) bool {
+            return fmt.Sprintf("%v", o.data["certificate"]) == r.id
+        }
+        for _, collection := range []string{"loadbalancers", "listeners"} {
+            if len(s.list(r.platform, collection, using)) > 0 {
+                return errorResponse(http.StatusConflict, "Certificate %s is in use by %s.", r.id, collection)
+            }
+        }
+    }
+    if resp, ok := serveCRUD(s, r, "", matchQuery(r, "name", "project")); ok {
+        return resp
+    }
+
+    if r.id != "" || r.Method != "POST" {
+        return methodNotAllowed(r)
+    }
+    if resp := required(r, "name", "certificate", "private_key", "project"); resp != nil {
+        return *resp
+    }
+    certificate, _ := r.body["certificate"].(string)
+    privateKey, _ := r.body["private_key"].(string)
+    pair, err := tls.X509KeyPair([]byte(certificate), []byte(privateKey))
+    if err != nil {
+        return response{http.StatusBadRequest, map[string]interface{}{
+            "private_key": []string{fmt.Sprintf("Invalid certificate or private key: %v", err)},
+        }}
+    }
+    cert, err := x509.ParseCertificate(pair.Certificate[0])
+    if err != nil {
+        return response{http.StatusBadRequest, map[string]interface{}{
+            "certificate": []string{fmt.Sprintf("Invalid certificate: %v", err)},
+        }}
+    }
+
+    data := copyFields(r, "name", "certificate", "chain", "project")
+    if _, ok := data["chain"]; !ok {
+        data["chain"] = ""
+    }
+    data["expiration"] = cert.NotAfter.UTC().Format("2006-01-02T15:04:05Z")
+    obj := s.create(r.platform, r.collection, data)
+    return response{http.StatusCreated, obj.data}
+}
+
+// firewallStatus returns the settled status of a firewall, which is only
+// ACTIVE while associated with networks.
+func firewallStatus(o *object) {
+    networks, _ := o.data["associate_networks"].([]interface{})
+    if len(networks) > 0 {
+        o.data["status"] = "ACTIVE"
+    } else {
+        o.data["status"] = "INACTIVE"
+    }
+}
+
+func serveFirewalls(s *Server, r *request) response {
+    if resp, ok := serveCRUD(s, r, "PENDING_DELETE", matchQuery(r, "project")); ok {
+        return resp
+    }
+
+    switch {
+    case r.id == "" && r.Method == "POST":
+        if resp := required(r, "name", "project"); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "name", "desc", "project", "associate_networks", "rules")
+        data["platform"] = r.platform
+        for _, field := range []string{"associate_networks", "rules"} {
+            if _, ok := data[field]; !ok {
+                data[field] = []interface{}{}
+            }
+        }
+        obj := s.create(r.platform, r.collection, data)
+        s.transition(obj, r.collection, "PENDING_UPDATE", "ERROR", firewallStatus)
+        return response{http.StatusCreated, obj.data}
+    case r.id != "" && r.sub == "" && r.Method == "PATCH":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound()
+        }
+        update := copyFields(r, "desc", "associate_networks", "rules")
+        s.transition(obj, r.collection, "PENDING_UPDATE", "ERROR", func(o *object) {
+            for key, value := range update {
+                o.data[key] = value
+            }
+            firewallStatus(o)
+        })
+        return response{http.StatusOK, obj.data}
+    }
+    return methodNotAllowed(r)
+}
+
+func serveVPNServices(s *Server, r *request) response {
+    match := matchQuery(r, "project", "ike_policy", "ipsec_policy", "private_network")
+    if resp, ok := serveCRUD(s, r, "DELETING", match); ok {
+        return resp
+    }
+
+    switch {
+    case r.id == "" && r.Method == "POST":
+        if resp := required(r, "name", "ike_policy", "ipsec_policy", "private_network"); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "name", "ike_policy", "ipsec_policy", "private_network", "project")
+        data["connection"] = nil
+        data["local_address"] = "203.0.113.1"
+        data["local_cidr"] = "10.0.0.0/24"
+        data["status"] = "ACTIVE"
+        if network := s.get(r.platform, "networks", fmt.Sprintf("%v", data["private_network"])); network != nil {
+            data["project"] = network.data["project"]
+        }
+        obj := s.create(r.platform, r.collection, data)
+        return response{http.StatusCreated, obj.data}
+    case r.id != "" && r.sub == "connection" && r.Method == "POST":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound()
+        }
+        if resp := required(r, "peer_address", "peer_cidrs"); resp != nil {
+            return *resp
+        }
+        connection := copyFields(r,
+            "dpd_action", "dpd_interval", "dpd_timeout", "initiator", "mtu", "peer_address", "peer_cidrs", "peer_id")
+        connection["status"] = "ACTIVE"
+        if reason, ok := s.failures["connection"]; ok {
+            delete(s.failures, "connection")
+            connection["status"] = "ERROR"
+            connection["status_reason"] = reason
+        }
+        obj.polls = s.Polls
+        obj.done = func(o *object) {
+            o.data["connection"] = connection
+        }
+        return response{status: http.StatusCreated}
+    case r.id != "" && r.sub == "connection" && r.Method == "DELETE":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil || obj.data["connection"] == nil {
+            return notFound()
+        }
+        obj.polls = s.Polls
+        obj.done = func(o *object) {
+            o.data["connection"] = nil
+        }
+        return response{status: http.StatusNoContent}
+    }
+    return methodNotAllowed(r)
+}
+
+func serveProjects(s *Server, r *request) response {
+    switch {
+    case r.id == "" && r.Method == "GET":
+        return response{http.StatusOK, []interface{}{
+            map[string]interface{}{"id": DefaultProject, "name": "mock", "platform": r.platform},
+        }}
+    case r.id != "" && r.sub == "key":
+        return serveKeys(s, r)
+    case r.id != "" && strings.HasPrefix(r.sub, "solutions/") && r.Method == "GET":
+        id := strings.TrimPrefix(r.sub, "solutions/")
+        for _, solution := range solutions {
+            if fmt.Sprintf("%v", solution["id"]) == id {
+                return response{http.StatusOK, map[string]interface{}{
+                    "id":			solution["id"],
+                    "site_extra_prop":	map[string]interface{}{"replicas": 1},
+                }}
+            }
+        }
+    }
+    return notFound()
+}
+
+// serveKeys serves the S3 keys of the project r.id.
+func serveKeys(s *Server, r *request) response {
+    key := storeKey(r.platform, r.id)
+    keys := s.keys[key]
+
+    switch r.Method {
+    case "GET":
+        private := []interface{}{}
+        for _, k := range keys {
+            private = append(private, k)
+        }
+        return response{http.StatusOK, map[string]interface{}{
+            "public":	map[string]interface{}{"access_key": "public-" + r.id, "secret_key": ""},
+            "private":	private,
+        }}
+    case "POST":
+        if resp := required(r, "name"); resp != nil {
+            return *resp
+        }
+        name := fmt.Sprintf("%v", r.body["name"])
+        for _, k := range keys {
+            if k["name"] == name {
+                return errorResponse(http.StatusBadRequest, "Key %s already exists.", name)
+            }
+        }
+        s.nextID++
+        k := map[string]interface{}{
+            "name":		name,
+            "access_key":	fmt.Sprintf("ACCESS%d", s.nextID),
+            "secret_key":	fmt.Sprintf("SECRET%d", s.nextID),
+        }
+        s.keys[key] = append(keys, k)
+        return response{http.StatusCreated, k}
+    case "DELETE":
+        for i, k := range keys {
+            if k["name"] == r.body["name"] {
+                s.keys[key] = append(keys[:i], keys[i+1:]...)
+                return response{status: http.StatusNoContent}
+            }
+        }
+        return notFound()
+    }
+    return methodNotAllowed(r)
+}
+
+// securityGroupRuleDefaults are the values of the fields of a rule which
+// were not given.
+var securityGroupRuleDefaults = map[string]interface{}{
+    "direction":		"ingress",
+    "protocol":			"tcp",
+    "remote_ip_prefix":	"0.0.0.0/0",
+}
+
+func serveSecurityGroups(s *Server, r *request) response {
+    switch {
+    case r.id == "" && r.Method == "GET":
+        if id := r.URL.Query().Get("sg"); id != "" {
+            obj := s.get(r.platform, r.collection, id)
+            if obj == nil || !matchQuery(r, "project")(obj) {
+                return notFound()
+            }
+            return response{http.StatusOK, obj.data}
+        }
+        return response{http.StatusOK, s.list(r.platform, r.collection, matchQuery(r, "project", "server"))}
+    case r.id != "" && r.sub == "" && r.Method == "PATCH":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound()
+        }
+        if resp := required(r, "project"); resp != nil {
+            return *resp
+        }
+        s.nextID++
+        rule := copyFields(r, "direction", "protocol", "remote_ip_prefix", "port_range_min", "port_range_max")
+        rule["id"] = s.nextID
+        rule["ethertype"] = "IPv4"
+        for field, value := range securityGroupRuleDefaults {
+            if _, ok := rule[field]; !ok {
+                rule[field] = value
+            }
+        }
+        for _, field := range []string{"port_range_min", "port_range_max"} {
+            if _, ok := rule[field]; !ok {
+                rule[field] = nil
+            }
+        }
+        rules, _ := obj.data["security_group_rules"].([]interface{})
+        obj.data["security_group_rules"] = append(rules, rule)
+        return response{http.StatusOK, obj.data}
+    }
+    return methodNotAllowed(r)
+}
+
+func serveSecurityGroupRules(s *Server, r *request) response {
+    if r.id == "" || r.Method != "DELETE" {
+        return methodNotAllowed(r)
+    }
+
+    id, err := strconv.Atoi(r.id)
+    if err != nil {
+        return notFound()
+    }
+    for _, obj := range s.objects[storeKey(r.platform, "security_groups")] {
+        rules, _ := obj.data["security_group_rules"].([]interface{})
+        for i, rule := range rules {
+            if rule.(map[string]interface{})["id"] == id {
+                obj.data["security_group_rules"] = append(rules[:i], rules[i+1:]...)
+                return response{status: http.StatusNoContent}
+            }
+        }
+    }
+    return notFound()
+}
+
+var (
+    autoScalingPolicyFields = []string{
+        "name", "description", "meter_name", "project", "scale_max_size", "scaledown_threshold", "scaleup_threshold",
+    }
+    ikePolicyFields = []string{
+        "name", "project", "auth_algorithm", "encryption_algorithm", "ike_version", "lifetime", "pfs",
+    }
+    ikePolicyDefaults = map[string]interface{}{
+        "auth_algorithm":		"sha1",
+        "encryption_algorithm":	"aes-128",
+        "ike_version":		"v1",
+        "lifetime":			3600,
+        "pfs":			"group5",
+    }
+    ipsecPolicyFields = []string{
+        "name", "project", "auth_algorithm", "encapsulation_mode", "encryption_algorithm", "lifetime", "pfs",
+        "transform_protocol",
+    }
+    ipsecPolicyDefaults = map[string]interface{}{
+        "auth_algorithm":		"sha1",
+        "encapsulation_mode":	"tunnel",
+        "encryption_algorithm":	"aes-128",
+        "lifetime":			3600,
+        "pfs":			"group5",
+        "transform_protocol":	"esp",
+    }
+)
+
+// servePolicies returns the handler of a collection of policies, which are
+// created at once and have no status. fields are the fields of a policy,
+// defaults the values of the fields which are optional.
+func servePolicies(fields []string, defaults map[string]interface{}) handler {
+    return func(s *Server, r *request) response {
+        if resp, ok := serveCRUD(s, r, "", matchQuery(r, "name", "project")); ok {
+            return resp
+        }
+        if r.id != "" || r.Method != "POST" {
+            return methodNotAllowed(r)
+        }
+
+        var mandatory []string
+        for _, field := range fields {
+            if _, ok := defaults[field]; !ok && field != "description" {
+                mandatory = append(mandatory, field)
+            }
+        }
+        if resp := required(r, mandatory...); resp != nil {
+            return *resp
+        }
+
+        data := copyFields(r, fields...)
+        for field, value := range defaults {
+            if _, ok := data[field]; !ok {
+                data[field] = value
+            }
+        }
+        obj := s.create(r.platform, r.collection, data)
+        return response{http.StatusCreated, obj.data}
+    }
+}
+
+func serveFirewallRules(s *Server, r *request) response {
+    if resp, ok := serveCRUD(s, r, "", matchQuery(r, "project")); ok {
+        return resp
+    }
+
+    fields := []string{
+        "action", "destination_ip_address", "destination_port", "protocol", "source_ip_address", "source_port",
+    }
+    switch {
+    case r.id == "" && r.Method == "POST":
+        if resp := required(r, "name", "project"); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, append(fields, "name", "project")...)
+        for _, field := range fields {
+            if _, ok := data[field]; !ok {
+                data[field] = ""
+            }
+        }
+        if data["action"] == "" {
+            data["action"] = "allow"
+        }
+        data["ip_version"] = 4
+        data["platform"] = r.platform
+        obj := s.create(r.platform, r.collection, data)
+        return response{http.StatusCreated, obj.data}
+    case r.id != "" && r.sub == "" && r.Method == "PATCH":
+        obj := s.get(r.platform, r.collection, r.id)
+        if obj == nil {
+            return notFound()
+        }
+        for key, value := range copyFields(r, fields...) {
+            obj.data[key] = value
+        }
+        return response{http.StatusOK, obj.data}
+    }
+    return methodNotAllowed(r)
+}
+
+func serveSnapshots(s *Server, r *request) response {
+    if resp, ok := serveCRUD(s, r, "DELETING", matchQuery(r, "project")); ok {
+        return resp
+    }
+
+    if r.id == "" && r.Method == "POST" {
+        if resp := required(r, "name", "volume"); resp != nil {
+            return *resp
+        }
+        volume := s.get(r.platform, "volumes", fmt.Sprintf("%v", r.body["volume"]))
+        if volume == nil {
+            return errorResponse(http.StatusBadRequest, "Volume %v does not exist.", r.body["volume"])
+        }
+        data := copyFields(r, "name", "desc", "volume")
+        data["project"] = volume.data["project"]
+        data["restore_volume"] = nil
+        obj := s.create(r.platform, r.collection, data)
+        obj.data["snapshot_uuid"] = fmt.Sprintf("00000000-0000-0000-0000-%012d", obj.id)
+        s.transition(obj, r.collection, "CREATING", "ERROR", setStatus("AVAILABLE"))
+        return response{http.StatusCreated, obj.data}
+    }
+    return methodNotAllowed(r)
+}
+
+// serveImages serves the images saved from servers. Images are saved with
+// PUT images/<server>/save/.
+func serveImages(s *Server, r *request) response {
+    if r.sub == "save" && r.Method == "PUT" {
+        if s.get(r.platform, "servers", r.id) == nil {
+            return notFound()
+        }
+        if resp := required(r, "name", "os", "os_version"); resp != nil {
+            return *resp
+        }
+        data := copyFields(r, "name", "desc", "os", "os_version")
+        data["is_enabled"] = true
+        data["is_public"] = false
+        obj := s.create(r.platform, r.collection, data)
+        obj.data["ref_img_id"] = fmt.Sprintf("00000000-0000-0000-0000-%012d", obj.id)
+        s.transition(obj, r.collection, "QUEUED", "ERROR", setStatus("ACTIVE"))
+        return response{http.StatusCreated, obj.data}
+    }
+    if resp, ok := serveCRUD(s, r, "", nil); ok && r.id != "" {
+        return resp
+    }
+    return methodNotAllowed(r)
+}
+
+// serveServers serves the servers of sites and their association with an
+// auto scaling policy.
+func serveServers(s *Server, r *request) response {
+    if r.id == "" {
+        return methodNotAllowed(r)
+    }
+    obj := s.get(r.platform, r.collection, r.id)
+    if obj == nil {
+        return notFound()
+    }
+
+    switch {
+    case r.sub == "" && r.Method == "GET":
+        return response{http.StatusOK, obj.data}
+    case r.sub == "auto_scaling_policy" && r.Method == "POST":
+        if resp := required(r, "auto_scaling_policy"); resp != nil {
+            return *resp
+        }
+        policy := fmt.Sprintf("%v", r.body["auto_scaling_policy"])
+        if s.get(r.platform, "auto_scaling_policies", policy) == nil {
+            return errorResponse(http.StatusBadRequest, "Auto scaling policy %s does not exist.", policy)
+        }
+        relation := map[string]interface{}{"id": policy, "status": "ASSOCIATING", "status_reason": ""}
+        status, reason := "ASSOCIATED", ""
+        if failure, ok := s.failures["auto_scaling_policy"]; ok {
+            delete(s.failures, "auto_scaling_policy")
+            status, reason = "ERROR", failure
+        }
+        obj.data["auto_scaling_policy"] = relation
+        obj.polls = s.Polls
+        obj.done = func(o *object) {
+            relation["status"] = status
+            relation["status_reason"] = reason
+        }
+        return response{status: http.StatusCreated}
+    case r.sub == "auto_scaling_policy" && r.Method == "DELETE":
+        relation, ok := obj.data["auto_scaling_policy"].(map[string]interface{})
+        if !ok {
+            return notFound()
+        }
+        relation["status"] = "DISASSOCIATING"
+        obj.polls = s.Polls
+        obj.done = func(o *object) {
+            o.data["auto_scaling_policy"] = nil
+        }
+        return response{status: http.StatusNoContent}
+    }
+    return methodNotAllowed(r)
+}
+
+// solutions is the solution catalog.
+var solutions = []map[string]interface{}{
+    {"id": 1, "name": "vcs", "category": "vcs", "desc": "Virtual compute service"},
+    {"id": 2, "name": "waf", "category": "waf", "desc": "Web application firewall"},
+    {"id": 3, "name": "container", "category": "container", "desc": "GPU container"},
+}
+
+func serveSolutions(s *Server, r *request) response {
+    if r.platform != solutionsHost || r.id != "" || r.Method != "GET" {
+        return notFound()
+    }
+
+    result := []interface{}{}
+    for _, solution := range solutions {
+        if matchQuery(r, "name", "category")(&object{data: solution}) {
+            data := map[string]interface{}{
+                "create_time":		"2020-01-01T00:00:00Z",
+                "is_public":		true,
+                "is_tenant_admin_only":	false,
+            }
+            for key, value := range solution {
+                data[key] = value
+            }
+            result = append(result, data)
+        }
+    }
+    return response{http.StatusOK, result}
+}
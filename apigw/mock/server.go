@@ -0,0 +1,470 @@
+// Package mock is an in-memory fake of the APIGW v4 API, for local
+// development and for tests which should not depend on a live gateway.
+//
+// It serves the endpoints the provider uses for sites, servers, images,
+// networks, volumes, snapshots, load balancers and their listeners,
+// certificates, firewalls, firewall rules, VPN services, policies, solutions,
+// S3 keys and security groups.
+// Requests must carry the configured x-api-key and an x-api-host matching
+// the platform of the path. Objects go through the same transitional
+// statuses as on the gateway, e.g. BUILD to ACTIVE, and reach their final
+// status after Polls reads:
+//
+//    server := mock.NewServer("apikey")
+//    defer server.Close()
+//
+//    config := apigw.Config{APIGW_APIKEY: "apikey", APIGW_URL: server.URL + "/"}
+//
+// Failures can be injected with AddFault for HTTP errors and with FailNext
+// for asynchronous operations ending in an error status. A POST repeated
+// with the same Idempotency-Key header is answered like the first one.
+package mock
+
+import (
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "net/http"
+    "net/http/httptest"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// DefaultProject is the project every platform has.
+const DefaultProject = "1"
+
+// solutionsHost is the platform serving the solution catalog at
+// api/v4/solutions/, without platform in the path.
+const solutionsHost = "goc"
+
+// Fault makes matching requests fail instead of being served.
+type Fault struct {
+    // Method and Path select the requests, an empty value matches all. Path
+    // matches if it is contained in the request path.
+    Method	string
+    Path	string
+    // Status is the response code, Body the response body. Body defaults to
+    // an error payload with the status text.
+    Status	int
+    Body	string
+    Header	map[string]string
+    // Delay is waited before responding.
+    Delay	time.Duration
+    // Served serves the request before failing it, like a gateway which
+    // made the change but whose response was lost.
+    Served	bool
+    // Count is the number of requests to fail, 0 means all of them.
+    Count	int
+}
+
+// object is a gateway object. When polls reaches zero, done is called to
+// complete a pending transition.
+type object struct {
+    id		int
+    data	map[string]interface{}
+    polls	int
+    done	func(*object)
+    removed	bool
+}
+
+// Server is the fake gateway. Its fields may be changed before it serves
+// requests.
+type Server struct {
+    // URL is the base URL of a server started with NewServer, without
+    // trailing slash.
+    URL		string
+    APIKey	string
+    // Platforms lists the valid platforms, any platform is valid if empty.
+    Platforms	[]string
+    // Polls is the number of reads an object stays in a transitional status.
+    Polls	int
+
+    mu		sync.Mutex
+    httpServer	*httptest.Server
+    nextID	int
+    objects	map[string]map[int]*object
+    keys	map[string][]map[string]interface{}
+    faults	[]*Fault
+    failures	map[string]string
+    requests	[]string
+    // idempotent are the responses to POST requests by Idempotency-Key.
+    idempotent	map[string]response
+}
+
+// New returns a fake gateway accepting apiKey, to be served with
+// net/http.
+func New(apiKey string) *Server {
+    return &Server{
+        APIKey:		apiKey,
+        Polls:		1,
+        objects:	make(map[string]map[int]*object),
+        keys:		make(map[string][]map[string]interface{}),
+        failures:	make(map[string]string),
+        idempotent:	make(map[string]response),
+    }
+}
+
+// NewServer starts a fake gateway accepting apiKey on a local port.
+func NewServer(apiKey string) *Server {
+    s := New(apiKey)
+    s.httpServer = httptest.NewServer(s)
+    s.URL = s.httpServer.URL
+    return s
+}
+
+// Close stops a server started with NewServer.
+func (s *Server) Close() {
+    if s.httpServer != nil {
+        s.httpServer.Close()
+    }
+}
+
+// AddFault injects a failure.
+func (s *Server) AddFault(f Fault) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected failures.
+func (s *Server) ClearFaults() {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.faults = nil
+}
+
+// FailNext makes the next asynchronous operation on an object of collection,
+// e.g. "networks", end in the error status with reason as status_reason.
+// VPN connections and auto scaling relations are failed with "connection"
+// and "auto_scaling_policy".
+func (s *Server) FailNext(collection, reason string) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.failures[collection] = reason
+}
+
+// Requests returns the method and path of every request served so far.
+func (s *Server) Requests() []string {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return append([]string(nil), s.requests...)
+}
+
+// request is a parsed request to api/v4/<platform>/<collection>/<id>/<sub>/.
+type request struct {
+    *http.Request
+    platform	string
+    collection	string
+    id		string
+    sub		string
+    body	map[string]interface{}
+}
+
+// response is the status and body of a handled request. A nil body is sent
+// as empty body.
+type response struct {
+    status	int
+    body	interface{}
+}
+
+func errorResponse(status int, format string, args ...interface{}) response {
+    return response{status, map[string]interface{}{"detail": fmt.Sprintf(format, args...)}}
+}
+
+func notFound() response {
+    return errorResponse(http.StatusNotFound, "Not found.")
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    s.mu.Lock()
+    s.requests = append(s.requests, r.Method + " " + r.URL.RequestURI())
+    fault := s.fault(r)
+    s.mu.Unlock()
+
+    if fault != nil {
+        if fault.Served {
+            s.serve(r)
+        }
+        time.Sleep(fault.Delay)
+        if fault.Status != 0 {
+            for key, value := range fault.Header {
+                w.Header().Set(key, value)
+            }
+            body := fault.Body
+            if body == "" {
+                encoded, _ := json.Marshal(map[string]string{"detail": http.StatusText(fault.Status)})
+                body = string(encoded)
+            }
+            w.Header().Set("Content-Type", "application/json")
+            w.WriteHeader(fault.Status)
+            w.Write([]byte(body))
+            return
+        }
+    }
+
+    resp := s.serve(r)
+    if resp.body == nil {
+        w.WriteHeader(resp.status)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(resp.status)
+    json.NewEncoder(w).Encode(resp.body)
+}
+
+// fault returns the first injected failure matching r and counts it.
+func (s *Server) fault(r *http.Request) *Fault {
+    for i, f := range s.faults {
+        if f.Method != "" && f.Method != r.Method {
+            continue
+        }
+        if !strings.Contains(r.URL.Path, f.Path) {
+            continue
+        }
+
+        matched := *f
+        if f.Count > 0 {
+            f.Count--
+            if f.Count == 0 {
+                s.faults = append(s.faults[:i], s.faults[i+1:]...)
+            }
+        }
+        return &matched
+    }
+    return nil
+}
+
+func (s *Server) serve(r *http.Request) response {
+    if key := r.Header.Get("x-api-key"); key == "" || key != s.APIKey {
+        return errorResponse(http.StatusUnauthorized, "Invalid API key.")
+    }
+
+    parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+    if len(parts) == 3 && parts[2] == "solutions" {
+        parts = []string{parts[0], parts[1], solutionsHost, parts[2]}
+    }
+    if len(parts) < 4 || parts[0] != "api" || parts[1] != "v4" {
+        return notFound()
+    }
+
+    req := &request{Request: r, platform: parts[2], collection: parts[3]}
+    if len(parts) > 4 {
+        req.id = parts[4]
+    }
+    if len(parts) > 5 {
+        req.sub = strings.Join(parts[5:], "/")
+    }
+
+    if host := r.Header.Get("x-api-host"); host != req.platform {
+        return errorResponse(http.StatusBadRequest,
+            "x-api-host %q does not match platform %q.", host, req.platform)
+    }
+    if !s.validPlatform(req.platform) {
+        return errorResponse(http.StatusBadRequest, "Unknown platform %q.", req.platform)
+    }
+
+    body, err := ioutil.ReadAll(r.Body)
+    if err != nil {
+        return errorResponse(http.StatusBadRequest, "Unable to read body: %v", err)
+    }
+    if len(body) > 0 {
+        if err := json.Unmarshal(body, &req.body); err != nil {
+            return errorResponse(http.StatusBadRequest, "JSON parse error - %v", err)
+        }
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    handler, ok := handlers[req.collection]
+    if !ok {
+        return notFound()
+    }
+
+    // A POST repeated with the same Idempotency-Key is answered like the
+    // first one, without creating another object.
+    key := r.Header.Get("Idempotency-Key")
+    if r.Method != "POST" || key == "" {
+        return handler(s, req)
+    }
+    if resp, ok := s.idempotent[key]; ok {
+        return resp
+    }
+    resp := handler(s, req)
+    if resp.status < 300 {
+        s.idempotent[key] = resp
+    }
+    return resp
+}
+
+func (s *Server) validPlatform(platform string) bool {
+    if len(s.Platforms) == 0 || platform == solutionsHost {
+        return true
+    }
+    for _, p := range s.Platforms {
+        if p == platform {
+            return true
+        }
+    }
+    return false
+}
+
+func storeKey(platform, collection string) string {
+    return platform + "/" + collection
+}
+
+// create stores data as a new object of collection and returns it.
+func (s *Server) create(platform, collection string, data map[string]interface{}) *object {
+    s.nextID++
+    obj := &object{id: s.nextID, data: data}
+    data["id"] = obj.id
+    if _, ok := data["create_time"]; !ok {
+        data["create_time"] = time.Now().UTC().Format(time.RFC3339)
+    }
+    if _, ok := data["user"]; !ok {
+        data["user"] = map[string]interface{}{"username": "mock"}
+    }
+
+    key := storeKey(platform, collection)
+    if s.objects[key] == nil {
+        s.objects[key] = make(map[int]*object)
+    }
+    s.objects[key][obj.id] = obj
+    return obj
+}
+
+// get returns the object id of collection, completing its pending
+// transition if it has been read often enough.
+func (s *Server) get(platform, collection, id string) *object {
+    n, err := strconv.Atoi(id)
+    if err != nil {
+        return nil
+    }
+
+    key := storeKey(platform, collection)
+    obj, ok := s.objects[key][n]
+    if !ok {
+        return nil
+    }
+
+    s.poll(obj)
+    if obj.removed {
+        delete(s.objects[key], n)
+        return nil
+    }
+    return obj
+}
+
+// list returns the objects of collection ordered by ID, for which match
+// returns true.
+func (s *Server) list(platform, collection string, match func(*object) bool) []interface{} {
+    key := storeKey(platform, collection)
+    ids := make([]int, 0, len(s.objects[key]))
+    for id := range s.objects[key] {
+        ids = append(ids, id)
+    }
+    sort.Ints(ids)
+
+    result := []interface{}{}
+    for _, id := range ids {
+        obj := s.get(platform, collection, strconv.Itoa(id))
+        if obj != nil && (match == nil || match(obj)) {
+            result = append(result, obj.data)
+        }
+    }
+    return result
+}
+
+func (s *Server) poll(obj *object) {
+    if obj.done == nil {
+        return
+    }
+    if obj.polls > 0 {
+        obj.polls--
+        return
+    }
+
+    done := obj.done
+    obj.done = nil
+    done(obj)
+}
+
+// transition puts obj into the status pending until it has been read Polls
+// times, then calls done. If a failure was injected for collection, obj
+// lands in errorStatus instead.
+func (s *Server) transition(
+        obj *object,
+        collection string,
+        pending string,
+        errorStatus string,
+        done func(*object)) {
+    obj.data["status"] = pending
+    obj.data["status_reason"] = ""
+    obj.polls = s.Polls
+
+    if reason, ok := s.failures[collection]; ok {
+        delete(s.failures, collection)
+        obj.done = func(o *object) {
+            o.data["status"] = errorStatus
+            o.data["status_reason"] = reason
+        }
+        return
+    }
+    obj.done = done
+}
+
+// setStatus returns a transition callback setting the status.
+func setStatus(status string) func(*object) {
+    return func(o *object) {
+        o.data["status"] = status
+    }
+}
+
+// remove is the transition callback of deletions.
+func remove(o *object) {
+    o.removed = true
+}
+
+// matchQuery returns a filter of objects whose fields equal the non-empty
+// query parameters of r given in params.
+func matchQuery(r *request, params ...string) func(*object) bool {
+    return func(o *object) bool {
+        for _, param := range params {
+            value := r.URL.Query().Get(param)
+            if value != "" && fmt.Sprintf("%v", o.data[param]) != value {
+                return false
+            }
+        }
+        return true
+    }
+}
+
+// required returns an error response if one of fields is missing in the
+// request body.
+func required(r *request, fields ...string) *response {
+    errs := map[string]interface{}{}
+    for _, field := range fields {
+        if value, ok := r.body[field]; !ok || value == nil || value == "" {
+            errs[field] = []string{"This field is required."}
+        }
+    }
+    if len(errs) == 0 {
+        return nil
+    }
+    return &response{http.StatusBadRequest, errs}
+}
+
+// copyFields returns the given fields of the request body.
+func copyFields(r *request, fields ...string) map[string]interface{} {
+    data := map[string]interface{}{}
+    for _, field := range fields {
+        if value, ok := r.body[field]; ok {
+            data[field] = value
+        }
+    }
+    return data
+}
@@ -0,0 +1,271 @@
+package mock_test
+
+import (
+    "context"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "apigw_plugin/terraform-provider-apigw/apigw"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+    "apigw_plugin/terraform-provider-apigw/apigw/mock"
+)
+
+const platform = "openstack-mock"
+
+var ctx = context.Background()
+
+func newClient(t *testing.T, server *mock.Server, apiKey string) *client.Client {
+    config := apigw.Config{
+        APIGW_APIKEY:	apiKey,
+        APIGW_URL:	server.URL + "/",
+        MaxRetries:	2,
+        RetryWaitMin:	time.Millisecond,
+        RetryWaitMax:	time.Millisecond,
+        // The mock deduplicates creates on their Idempotency-Key.
+        RetryIdempotentCreates:	true,
+    }
+    if err := config.LoadAndValidate(); err != nil {
+        t.Fatal(err)
+    }
+    return config.API
+}
+
+func TestServerRejectsInvalidAPIKey(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+
+    _, err := newClient(t, server, "wrong").Networks.List(ctx, platform, client.NetworkListOpts{})
+    if _, ok := err.(apigw.ErrDefault401); !ok {
+        t.Fatalf("expected a 401 error, got %v", err)
+    }
+}
+
+func TestServerNetworkLifecycle(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    api := newClient(t, server, "secret")
+
+    network, err := api.Networks.Create(ctx, platform, client.NetworkCreateOpts{
+        Name:		"net",
+        CIDR:		"10.0.0.0/24",
+        Gateway:	"10.0.0.1",
+        Project:	mock.DefaultProject,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var statuses []string
+    for i := 0; i < 3; i++ {
+        got, err := api.Networks.Get(ctx, platform, network.ID.String())
+        if err != nil {
+            t.Fatal(err)
+        }
+        statuses = append(statuses, got.Status)
+    }
+    if got := strings.Join(statuses, ","); got != "BUILD,ACTIVE,ACTIVE" {
+        t.Errorf("statuses = %s, want BUILD,ACTIVE,ACTIVE", got)
+    }
+
+    if err := api.Networks.Delete(ctx, platform, network.ID.String()); err != nil {
+        t.Fatal(err)
+    }
+    for i := 0; i < 2; i++ {
+        _, err = api.Networks.Get(ctx, platform, network.ID.String())
+    }
+    if !apigw.IsNotFound(err) {
+        t.Errorf("expected the network to be gone, got %v", err)
+    }
+}
+
+func TestServerFailNext(t *testing.T) {
+    server := mock.NewServer("secret")
+    server.Polls = 0
+    defer server.Close()
+    api := newClient(t, server, "secret")
+
+    server.FailNext("sites", "out of GPUs")
+    site, err := api.Sites.Create(ctx, platform, client.SiteCreateOpts{
+        Name:		"site",
+        Project:	mock.DefaultProject,
+        Solution:	"1",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    got, err := api.Sites.Get(ctx, platform, site.ID.String())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.Status != "Error" || got.StatusReason != "out of GPUs" {
+        t.Errorf("status = %s (%s), want Error (out of GPUs)", got.Status, got.StatusReason)
+    }
+
+    container, err := api.Sites.GetContainer(ctx, platform, site.ID.String())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if pod := container.Pods[0]; pod.Message != "out of GPUs" {
+        t.Errorf("pod message = %q, want out of GPUs", pod.Message)
+    }
+}
+
+func TestServerFaultIsRetried(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    api := newClient(t, server, "secret")
+
+    server.AddFault(mock.Fault{Method: "GET", Path: "/volumes/", Status: http.StatusServiceUnavailable, Count: 2})
+    if _, err := api.Volumes.List(ctx, platform, client.VolumeListOpts{}); err != nil {
+        t.Fatalf("expected the request to be retried, got %v", err)
+    }
+    if got := len(server.Requests()); got != 3 {
+        t.Errorf("served %d requests, want 3", got)
+    }
+
+    server.AddFault(mock.Fault{Status: http.StatusInternalServerError})
+    if _, err := api.Volumes.List(ctx, platform, client.VolumeListOpts{}); err == nil {
+        t.Fatal("expected an error")
+    }
+}
+
+func TestServerIdempotencyKey(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    api := newClient(t, server, "secret")
+
+    // The first response is lost, the retry must not create another network.
+    server.AddFault(mock.Fault{Method: "POST", Status: http.StatusBadGateway, Served: true, Count: 1})
+    opts := client.NetworkCreateOpts{
+        Name:		"net",
+        CIDR:		"10.0.0.0/24",
+        Gateway:	"10.0.0.1",
+        Project:	mock.DefaultProject,
+        IdempotencyKey:	client.NewIdempotencyKey(),
+    }
+    network, err := api.Networks.Create(ctx, platform, opts)
+    if err != nil {
+        t.Fatalf("expected the create to be retried, got %v", err)
+    }
+
+    networks, err := api.Networks.List(ctx, platform, client.NetworkListOpts{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(networks) != 1 || networks[0].ID != network.ID {
+        t.Errorf("networks = %v, want only %s", networks, network.ID)
+    }
+
+    opts.IdempotencyKey = client.NewIdempotencyKey()
+    if _, err := api.Networks.Create(ctx, platform, opts); err != nil {
+        t.Fatal(err)
+    }
+    if networks, _ = api.Networks.List(ctx, platform, client.NetworkListOpts{}); len(networks) != 2 {
+        t.Errorf("created %d networks with two keys, want 2", len(networks))
+    }
+}
+
+func TestServerSecurityGroupRules(t *testing.T) {
+    server := mock.NewServer("secret")
+    server.Polls = 0
+    defer server.Close()
+    api := newClient(t, server, "secret")
+
+    site, err := api.Sites.Create(ctx, platform, client.SiteCreateOpts{
+        Name:		"vcs",
+        Project:	mock.DefaultProject,
+        Solution:	"1",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    site, err = api.Sites.Get(ctx, platform, site.ID.String())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sgs, err := api.SecurityGroups.List(ctx, platform, client.SecurityGroupListOpts{
+        Project:	mock.DefaultProject,
+        Server:		site.Servers[0].ID.String(),
+    })
+    if err != nil || len(sgs) != 1 {
+        t.Fatalf("expected one security group, got %v: %v", sgs, err)
+    }
+
+    sgID := sgs[0].ID.String()
+    err = api.SecurityGroups.AddRule(ctx, platform, sgID, client.SecurityGroupRuleCreateOpts{
+        Direction:	"ingress",
+        Protocol:	"tcp",
+        PortRangeMin:	22,
+        PortRangeMax:	22,
+        Project:	mock.DefaultProject,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sg, err := api.SecurityGroups.Get(ctx, platform, mock.DefaultProject, sgID)
+    if err != nil || len(sg.Rules) != 1 {
+        t.Fatalf("expected one rule, got %v: %v", sg, err)
+    }
+
+    err = api.SecurityGroups.DeleteRule(ctx, platform, mock.DefaultProject, sg.Rules[0].ID.String())
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestServerListenerPorts(t *testing.T) {
+    server := mock.NewServer("secret")
+    defer server.Close()
+    api := newClient(t, server, "secret")
+
+    lb, err := api.LoadBalancers.Create(ctx, platform, client.LoadBalancerCreateOpts{
+        Name:		"lb",
+        LBMethod:	"ROUND_ROBIN",
+        PrivateNet:	"1",
+        Protocol:	"HTTP",
+        ProtocolPort:	80,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    opts := client.ListenerCreateOpts{
+        LBMethod:	"ROUND_ROBIN",
+        LoadBalancer:	lb.ID.String(),
+        Protocol:	"HTTP",
+        ProtocolPort:	80,
+    }
+    if _, err := api.Listeners.Create(ctx, platform, opts); !apigw.IsConflict(err) {
+        t.Errorf("expected a conflict with the port of the loadbalancer, got %v", err)
+    }
+    opts.Protocol = "TCP"
+    opts.ProtocolPort = 443
+    listener, err := api.Listeners.Create(ctx, platform, opts)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := api.Listeners.Create(ctx, platform, opts); !apigw.IsConflict(err) {
+        t.Errorf("expected a conflict with the port of the listener, got %v", err)
+    }
+
+    if err := api.LoadBalancers.Delete(ctx, platform, lb.ID.String()); !apigw.IsConflict(err) {
+        t.Errorf("expected a conflict deleting a loadbalancer with listeners, got %v", err)
+    }
+    if err := api.Listeners.Delete(ctx, platform, listener.ID.String()); err != nil {
+        t.Fatal(err)
+    }
+    for i := 0; i < 2; i++ {
+        _, err = api.Listeners.Get(ctx, platform, listener.ID.String())
+    }
+    if !apigw.IsNotFound(err) {
+        t.Errorf("expected the listener to be gone, got %v", err)
+    }
+    if err := api.LoadBalancers.Delete(ctx, platform, lb.ID.String()); err != nil {
+        t.Errorf("Unable to delete the loadbalancer without listeners: %v", err)
+    }
+}
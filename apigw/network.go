@@ -1,62 +1,28 @@
 package apigw
 
 import (
+    "context"
     "fmt"
-    "encoding/json"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 func flattenNetworkNameServersInfo(v []interface{}) []string {
-    var nameServers []string
+    nameServers := make([]string, len(v))
     for i, obj := range v {
         nameServers[i] = fmt.Sprintf("%v", obj)
     }
     return nameServers
 }
 
-func networkStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
-}
-
-func networkStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
+// networkWaiter waits for the network networkID on platform.
+func networkWaiter(ctx context.Context, config *PConfig, platform string, networkID string) *waiter {
+    return newWaiter(ctx, config, "network", networkID,
+        func() (interface{}, error) {
+            return config.API.Networks.Get(ctx, platform, networkID)
+        },
+        func(v interface{}) (string, string) {
+            network := v.(*client.Network)
+            return network.Status, network.StatusReason
+        })
 }
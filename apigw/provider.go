@@ -1,6 +1,10 @@
 package apigw
   
 import (
+    "context"
+    "os"
+    "time"
+
     "github.com/hashicorp/terraform-plugin-sdk/helper/mutexkv"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
     "github.com/hashicorp/terraform-plugin-sdk/terraform"
@@ -10,24 +14,166 @@ var osMutexKV = mutexkv.NewMutexKV()
 
 type PConfig struct {
     Config
+
+    // StopContext is done when terraform is interrupted.
+    StopContext	context.Context
+
+    // resourceType is the type of the resource whose operation is running,
+    // see setAuditResourceTypes.
+    resourceType	string
+}
+
+// operationContext returns the context of the CRUD operation on d, which
+// is done when terraform is interrupted or once the timeout of the
+// operation, e.g. schema.TimeoutCreate, expires. It carries the resource
+// for the audit log.
+func (c *PConfig) operationContext(d *schema.ResourceData, timeout string) (context.Context, context.CancelFunc) {
+    ctx := c.StopContext
+    if ctx == nil {
+        ctx = context.Background()
+    }
+    if c.resourceType != "" {
+        ctx = withAuditResource(ctx, auditResource{Type: c.resourceType, ID: d.Id()})
+    }
+    return context.WithTimeout(ctx, d.Timeout(timeout))
 }
 
 // Provider returns a schema.Provider for APIGW.
 func Provider() terraform.ResourceProvider {
     provider := &schema.Provider{
         Schema: map[string]*schema.Schema{
+            "audit_log_path": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_AUDIT_LOG_PATH", ""),
+                Description:	descriptions["audit_log_path"],
+            },
             "apikey": {
                 Type:		schema.TypeString,
-                Required:	true,
+                Optional:	true,
                 DefaultFunc:	schema.EnvDefaultFunc("APIGW_APIKEY", ""),
                 Description:	descriptions["apikey"],
             },
             "apigw_url": {       
                 Type:		schema.TypeString,
-                Required:	true,
+                Optional:	true,
                 DefaultFunc:	schema.EnvDefaultFunc("APIGW_URL", ""),
                 Description:	descriptions["apigw_url"],
             },
+            "cacert_file": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_CACERT", ""),
+                Description:	descriptions["cacert_file"],
+            },
+            "client_cert": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_CLIENT_CERT", ""),
+                Description:	descriptions["client_cert"],
+            },
+            "client_key": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_CLIENT_KEY", ""),
+                Description:	descriptions["client_key"],
+            },
+            "credentials_file": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_CREDENTIALS_FILE", ""),
+                Description:	descriptions["credentials_file"],
+            },
+            "default_platform": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_DEFAULT_PLATFORM", ""),
+                Description:	descriptions["default_platform"],
+            },
+            "default_project": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_DEFAULT_PROJECT", ""),
+                Description:	descriptions["default_project"],
+            },
+            "http_debug": {
+                Type:		schema.TypeBool,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_HTTP_DEBUG", false),
+                Description:	descriptions["http_debug"],
+            },
+            "insecure": {
+                Type:		schema.TypeBool,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_INSECURE", nil),
+                Description:	descriptions["insecure"],
+            },
+            "max_concurrent_requests": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_MAX_CONCURRENT_REQUESTS", 0),
+                Description:	descriptions["max_concurrent_requests"],
+            },
+            "max_retries": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Default:	3,
+                Description:	descriptions["max_retries"],
+            },
+            "poll_interval": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_POLL_INTERVAL", 0),
+                Description:	descriptions["poll_interval"],
+            },
+            "poll_min_timeout": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_POLL_MIN_TIMEOUT", 1),
+                Description:	descriptions["poll_min_timeout"],
+            },
+            "profile": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_PROFILE", ""),
+                Description:	descriptions["profile"],
+            },
+            "request_timeout": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_REQUEST_TIMEOUT", 0),
+                Description:	descriptions["request_timeout"],
+            },
+            "requests_per_second": {
+                Type:		schema.TypeFloat,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_REQUESTS_PER_SECOND", 0),
+                Description:	descriptions["requests_per_second"],
+            },
+            "retry_idempotent_creates": {
+                Type:		schema.TypeBool,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_RETRY_IDEMPOTENT_CREATES", false),
+                Description:	descriptions["retry_idempotent_creates"],
+            },
+            "retry_wait_max": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Default:	30,
+                Description:	descriptions["retry_wait_max"],
+            },
+            "retry_wait_min": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Default:	1,
+                Description:	descriptions["retry_wait_min"],
+            },
+            "user_agent_extra": {
+                Type:		schema.TypeString,
+                Optional:	true,
+                DefaultFunc:	schema.EnvDefaultFunc("APIGW_USER_AGENT_EXTRA", ""),
+                Description:	descriptions["user_agent_extra"],
+            },
         },
 
         DataSourcesMap: map[string]*schema.Resource{
@@ -54,12 +200,15 @@ func Provider() terraform.ResourceProvider {
         ResourcesMap: map[string]*schema.Resource{
             "apigw_auto_scaling_policy":	resourceAutoScalingPolicy(),
             "apigw_auto_scaling_relation":	resourceAutoScalingRelation(),
+            "apigw_certificate":		resourceCertificate(),
             "apigw_container":			resourceContainer(),
             "apigw_firewall":			resourceFirewall(),
             "apigw_firewall_rule":		resourceFirewallRule(),
             "apigw_ike_policy":			resourceIKEPolicy(),
             "apigw_ipsec_policy":		resourceIPSecPolicy(),
             "apigw_loadbalancer":		resourceLoadBalancer(),
+            "apigw_loadbalancer_listener":	resourceLoadBalancerListener(),
+            "apigw_loadbalancer_member":	resourceLoadBalancerMember(),
             "apigw_network":			resourceNetwork(),
             "apigw_vcs":			resourceVCS(),
             "apigw_vcs_image":			resourceVCSImage(),
@@ -74,6 +223,9 @@ func Provider() terraform.ResourceProvider {
         },
     }
 
+    setProviderDefaults(provider)
+    setAuditResourceTypes(provider)
+
     provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
         terraformVersion := provider.TerraformVersion
         if terraformVersion == "" {
@@ -81,7 +233,7 @@ func Provider() terraform.ResourceProvider {
             // We can therefore assume that if it's missing it's 0.10 or 0.11
             terraformVersion = "0.11+compatible"
         }
-        return configureProvider(d, terraformVersion)
+        return configureProvider(d, terraformVersion, provider.StopContext())
     }
     return provider
 }
@@ -92,15 +244,64 @@ func init() {
     descriptions = map[string]string{
         "apikey": "APIKey to login with.",
         "apigw_url": "APIGW endpoint to request to.",
+        "audit_log_path": "A file to which every mutating request is appended as a JSON line.",
+        "cacert_file": "A custom CA certificate file used to verify the APIGW endpoint.",
+        "client_cert": "A client certificate file to authenticate with.",
+        "client_key": "The private key file of the client certificate.",
+        "credentials_file": "The credentials file holding the profiles, defaults to ~/.apigw/credentials.",
+        "default_platform": "The platform of resources and data sources which do not set one.",
+        "default_project": "The project of resources and data sources which do not set one.",
+        "http_debug": "Log the requests sent to and responses received from APIGW at the DEBUG level.",
+        "insecure": "Skip TLS verification of the APIGW endpoint.",
+        "max_concurrent_requests": "Maximum number of requests in flight per platform, 0 means unlimited.",
+        "max_retries": "Number of times a request failing with a transient error is retried.",
+        "poll_interval": "Number of seconds between polls of long running operations, 0 means backing off.",
+        "poll_min_timeout": "Minimum number of seconds between polls of long running operations.",
+        "profile": "The profile of the credentials file to use, defaults to default.",
+        "request_timeout": "Number of seconds after which a single request is aborted, 0 means no limit.",
+        "requests_per_second": "Maximum number of requests per second per platform, 0 means unlimited.",
+        "retry_idempotent_creates": "Retry creates sent with an Idempotency-Key header, the gateway must deduplicate them.",
+        "retry_wait_max": "Maximum number of seconds to wait between retries.",
+        "retry_wait_min": "Minimum number of seconds to wait between retries.",
+        "user_agent_extra": "A suffix of the User-Agent header sent to APIGW.",
     }
 }
 
-func configureProvider(d *schema.ResourceData, terraformVersion string) (interface{}, error) {
+func configureProvider(
+        d *schema.ResourceData,
+        terraformVersion string,
+        stopContext context.Context) (interface{}, error) {
+    insecure, insecureSet := d.GetOkExists("insecure")
     config := PConfig{
-        Config{
+        Config: Config{
+            Profile:		d.Get("profile").(string),
+            CredentialsFile:	d.Get("credentials_file").(string),
             APIGW_APIKEY:	d.Get("apikey").(string),
             APIGW_URL:		d.Get("apigw_url").(string),
+            CACertFile:		d.Get("cacert_file").(string),
+            ClientCertFile:	d.Get("client_cert").(string),
+            ClientKeyFile:	d.Get("client_key").(string),
+            Insecure:		insecure.(bool),
+            InsecureSet:	insecureSet,
+            MaxRetries:		d.Get("max_retries").(int),
+            RetryWaitMin:	time.Duration(d.Get("retry_wait_min").(int)) * time.Second,
+            RetryWaitMax:	time.Duration(d.Get("retry_wait_max").(int)) * time.Second,
+            RetryIdempotentCreates:	d.Get("retry_idempotent_creates").(bool),
+            RequestTimeout:	time.Duration(d.Get("request_timeout").(int)) * time.Second,
+            RequestsPerSecond:	d.Get("requests_per_second").(float64),
+            MaxConcurrentRequests:	d.Get("max_concurrent_requests").(int),
+            HTTPDebug:		d.Get("http_debug").(bool),
+            DefaultPlatform:	d.Get("default_platform").(string),
+            DefaultProject:	d.Get("default_project").(string),
+            TerraformVersion:	terraformVersion,
+            UserAgentExtra:	d.Get("user_agent_extra").(string),
+            PollInterval:	time.Duration(d.Get("poll_interval").(int)) * time.Second,
+            PollMinTimeout:	time.Duration(d.Get("poll_min_timeout").(int)) * time.Second,
+            RecordFile:		os.Getenv("APIGW_RECORD"),
+            ReplayFile:		os.Getenv("APIGW_REPLAY"),
+            AuditLogPath:	d.Get("audit_log_path").(string),
         },
+        StopContext:	stopContext,
     }
 
     if err := config.LoadAndValidate(); err != nil {
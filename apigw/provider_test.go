@@ -0,0 +1,191 @@
+package apigw
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+    "apigw_plugin/terraform-provider-apigw/apigw/mock"
+)
+
+// testAccPrefix is the prefix of the names of objects created by acceptance
+// tests, which sweepers delete.
+const testAccPrefix = "tf-acc"
+
+var testAccProviders map[string]terraform.ResourceProvider
+var testAccProvider *schema.Provider
+
+// testAccMockEnv is the environment of acceptance tests run against the mock
+// gateway. Against a live gateway, APIGW_DEFAULT_PLATFORM and
+// APIGW_DEFAULT_PROJECT select where the tests run, the APIGW_TEST_*
+// variables name the project and solutions of the platform.
+var testAccMockEnv = map[string]string{
+    "APIGW_APIKEY":			"acctest",
+    "APIGW_DEFAULT_PLATFORM":		"openstack-mock",
+    "APIGW_DEFAULT_PROJECT":		mock.DefaultProject,
+    "APIGW_POLL_MIN_TIMEOUT":		"0",
+    "APIGW_TEST_CONTAINER_SOLUTION":	"container",
+    "APIGW_TEST_PROJECT_NAME":		"mock",
+    "APIGW_TEST_VCS_SOLUTION":		"vcs",
+    "APIGW_TEST_WAF_SOLUTION":		"waf",
+}
+
+func init() {
+    testAccProvider = Provider().(*schema.Provider)
+    testAccProviders = map[string]terraform.ResourceProvider{
+        "apigw": testAccProvider,
+    }
+}
+
+// TestMain runs the acceptance tests against the mock gateway unless
+// APIGW_URL is set, and runs the sweepers if -sweep is given.
+func TestMain(m *testing.M) {
+    if os.Getenv(resource.TestEnvVar) != "" && os.Getenv("APIGW_URL") == "" {
+        server := mock.NewServer(testAccMockEnv["APIGW_APIKEY"])
+        os.Setenv("APIGW_URL", server.URL + "/")
+        for key, value := range testAccMockEnv {
+            os.Setenv(key, value)
+        }
+    }
+
+    resource.TestMain(m)
+}
+
+func TestProvider(t *testing.T) {
+    if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func testAccPreCheck(t *testing.T) {
+    for _, key := range []string{"APIGW_URL", "APIGW_DEFAULT_PLATFORM", "APIGW_DEFAULT_PROJECT"} {
+        if os.Getenv(key) == "" {
+            t.Fatalf("%s must be set for acceptance tests", key)
+        }
+    }
+}
+
+// testAccPreCheckEnv skips the test unless the environment variable key is
+// set, e.g. to name a solution of the platform.
+func testAccPreCheckEnv(t *testing.T, key string) {
+    testAccPreCheck(t)
+    if os.Getenv(key) == "" {
+        t.Skipf("%s must be set for this acceptance test", key)
+    }
+}
+
+// testAccName returns the name of the objects created by the test t. It is
+// random, unless the requests are recorded or replayed, which needs the same
+// requests on every run.
+func testAccName(t *testing.T) string {
+    if os.Getenv("APIGW_RECORD") == "" && os.Getenv("APIGW_REPLAY") == "" {
+        return acctest.RandomWithPrefix(testAccPrefix)
+    }
+    name := strings.ToLower(strings.TrimPrefix(t.Name(), "TestAcc"))
+    return testAccPrefix + "-" + strings.Replace(name, "_", "-", -1)
+}
+
+func testAccPlatform() string {
+    return os.Getenv("APIGW_DEFAULT_PLATFORM")
+}
+
+func testAccProject() string {
+    return os.Getenv("APIGW_DEFAULT_PROJECT")
+}
+
+// testAccSolutionConfig looks up the solution named by the environment
+// variable key as data.apigw_solution.<name>.
+func testAccSolutionConfig(name, key string) string {
+    return fmt.Sprintf(`
+data "apigw_solution" %q {
+  name = %q
+}
+`, name, os.Getenv(key))
+}
+
+// testAccImportStateID returns the import ID of the resource name, made of
+// the given attributes separated by slashes.
+func testAccImportStateID(name string, attributes ...string) resource.ImportStateIdFunc {
+    return func(s *terraform.State) (string, error) {
+        rs, ok := s.RootModule().Resources[name]
+        if !ok {
+            return "", fmt.Errorf("Not found: %s", name)
+        }
+
+        values := make([]string, len(attributes))
+        for i, attribute := range attributes {
+            if attribute == "id" {
+                values[i] = rs.Primary.ID
+            } else {
+                values[i] = rs.Primary.Attributes[attribute]
+            }
+        }
+        return strings.Join(values, "/"), nil
+    }
+}
+
+// testAccCheckDestroy returns a CheckDestroy function verifying that the
+// resources of type kind are gone. exists looks a resource up on the
+// gateway.
+func testAccCheckDestroy(
+        kind string,
+        exists func(api *client.Client, rs *terraform.ResourceState) (bool, error)) resource.TestCheckFunc {
+    return func(s *terraform.State) error {
+        config := testAccProvider.Meta().(*PConfig)
+        for _, rs := range s.RootModule().Resources {
+            if rs.Type != kind {
+                continue
+            }
+
+            found, err := exists(config.API, rs)
+            if err != nil {
+                return err
+            }
+            if found {
+                return fmt.Errorf("%s %s still exists", kind, rs.Primary.ID)
+            }
+        }
+        return nil
+    }
+}
+
+// testAccFound interprets the error of reading an object: it exists if err
+// is nil and is gone if err is a 404.
+func testAccFound(err error) (bool, error) {
+    if IsNotFound(err) {
+        return false, nil
+    }
+    return err == nil, err
+}
+
+// sharedConfig returns a configuration for sweepers, which run without
+// provider block.
+func sharedConfig() (*PConfig, error) {
+    config := &PConfig{
+        Config: Config{
+            Profile:		os.Getenv("APIGW_PROFILE"),
+            CredentialsFile:	os.Getenv("APIGW_CREDENTIALS_FILE"),
+            APIGW_APIKEY:	os.Getenv("APIGW_APIKEY"),
+            APIGW_URL:		os.Getenv("APIGW_URL"),
+            RecordFile:		os.Getenv("APIGW_RECORD"),
+            ReplayFile:		os.Getenv("APIGW_REPLAY"),
+        },
+    }
+    if err := config.LoadAndValidate(); err != nil {
+        return nil, err
+    }
+    return config, nil
+}
+
+// sweepable reports whether an object named name was leaked by acceptance
+// tests.
+func sweepable(name string) bool {
+    return strings.HasPrefix(name, testAccPrefix + "-")
+}
@@ -0,0 +1,132 @@
+package apigw
+
+import (
+    "context"
+    "math"
+    "sync"
+    "time"
+)
+
+// tokenBucket allows rate requests per second on average with bursts of up
+// to burst requests.
+type tokenBucket struct {
+    mu		sync.Mutex
+    rate	float64
+    burst	float64
+    tokens	float64
+    last	time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+    burst := math.Max(1, math.Ceil(rate))
+    return &tokenBucket{
+        rate:	rate,
+        burst:	burst,
+        tokens:	burst,
+        last:	time.Now(),
+    }
+}
+
+// wait blocks until a token is available and takes it, or until ctx is
+// done, in which case the token is given back.
+func (b *tokenBucket) wait(ctx context.Context) error {
+    b.mu.Lock()
+    now := time.Now()
+    b.tokens = math.Min(b.burst, b.tokens + now.Sub(b.last).Seconds() * b.rate)
+    b.last = now
+    b.tokens--
+
+    var delay time.Duration
+    if b.tokens < 0 {
+        delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+    }
+    b.mu.Unlock()
+
+    if delay <= 0 {
+        return nil
+    }
+
+    timer := time.NewTimer(delay)
+    defer timer.Stop()
+    select {
+    case <-timer.C:
+        return nil
+    case <-ctx.Done():
+        b.mu.Lock()
+        b.tokens++
+        b.mu.Unlock()
+        return ctx.Err()
+    }
+}
+
+// requestLimiter throttles requests per platform, i.e. per value of the
+// x-api-host header, so that one busy platform does not slow down the others.
+// A zero requestsPerSecond or maxConcurrent disables the respective limit.
+type requestLimiter struct {
+    mu			sync.Mutex
+    requestsPerSecond	float64
+    maxConcurrent	int
+    buckets		map[string]*tokenBucket
+    slots		map[string]chan struct{}
+}
+
+func newRequestLimiter(requestsPerSecond float64, maxConcurrent int) *requestLimiter {
+    return &requestLimiter{
+        requestsPerSecond:	requestsPerSecond,
+        maxConcurrent:		maxConcurrent,
+        buckets:		make(map[string]*tokenBucket),
+        slots:			make(map[string]chan struct{}),
+    }
+}
+
+// acquire blocks until a request to platform may be sent, or until ctx is
+// done. Unless it fails, the returned function must be called once the
+// request has completed.
+func (l *requestLimiter) acquire(ctx context.Context, platform string) (func(), error) {
+    if l == nil {
+        return func() {}, nil
+    }
+
+    l.mu.Lock()
+    var bucket *tokenBucket
+    if l.requestsPerSecond > 0 {
+        bucket = l.buckets[platform]
+        if bucket == nil {
+            bucket = newTokenBucket(l.requestsPerSecond)
+            l.buckets[platform] = bucket
+        }
+    }
+
+    var slots chan struct{}
+    if l.maxConcurrent > 0 {
+        slots = l.slots[platform]
+        if slots == nil {
+            slots = make(chan struct{}, l.maxConcurrent)
+            l.slots[platform] = slots
+        }
+    }
+    l.mu.Unlock()
+
+    release := func() {
+        if slots != nil {
+            <-slots
+        }
+    }
+
+    if slots != nil {
+        select {
+        case slots <- struct{}{}:
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        }
+    }
+
+    if bucket != nil {
+        if err := bucket.wait(ctx); err != nil {
+            release()
+            return nil, err
+        }
+    }
+
+    return release, nil
+}
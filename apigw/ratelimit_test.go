@@ -0,0 +1,141 @@
+package apigw
+
+import (
+    "context"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestTokenBucketRate(t *testing.T) {
+    ctx := context.Background()
+    bucket := newTokenBucket(50)
+
+    // A full bucket lets a burst of requests through at once.
+    start := time.Now()
+    for i := 0; i < 50; i++ {
+        if err := bucket.wait(ctx); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if elapsed := time.Since(start); elapsed > 100 * time.Millisecond {
+        t.Errorf("burst of 50 took %s", elapsed)
+    }
+
+    // Then 25 more requests take 25/50 seconds.
+    start = time.Now()
+    for i := 0; i < 25; i++ {
+        if err := bucket.wait(ctx); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if elapsed := time.Since(start); elapsed < 400 * time.Millisecond || elapsed > time.Second {
+        t.Errorf("25 requests at 50 per second took %s, want about 500ms", elapsed)
+    }
+}
+
+func TestTokenBucketCancelled(t *testing.T) {
+    bucket := newTokenBucket(1)
+    if err := bucket.wait(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Millisecond)
+    defer cancel()
+    if err := bucket.wait(ctx); err != context.DeadlineExceeded {
+        t.Fatalf("wait of an empty bucket = %v, want %v", err, context.DeadlineExceeded)
+    }
+
+    // The token of the cancelled wait is given back.
+    bucket.mu.Lock()
+    tokens := bucket.tokens
+    bucket.mu.Unlock()
+    if tokens < -0.1 {
+        t.Errorf("tokens after a cancelled wait = %f, want about 0", tokens)
+    }
+}
+
+func TestRequestLimiterConcurrency(t *testing.T) {
+    limiter := newRequestLimiter(0, 2)
+    ctx := context.Background()
+
+    var mu sync.Mutex
+    running, maxRunning := 0, 0
+    var wg sync.WaitGroup
+    for i := 0; i < 6; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            release, err := limiter.acquire(ctx, "a")
+            if err != nil {
+                t.Error(err)
+                return
+            }
+            defer release()
+
+            mu.Lock()
+            running++
+            if running > maxRunning {
+                maxRunning = running
+            }
+            mu.Unlock()
+
+            time.Sleep(20 * time.Millisecond)
+
+            mu.Lock()
+            running--
+            mu.Unlock()
+        }()
+    }
+    wg.Wait()
+
+    if maxRunning != 2 {
+        t.Errorf("%d concurrent requests, want 2", maxRunning)
+    }
+}
+
+func TestRequestLimiterPerPlatform(t *testing.T) {
+    limiter := newRequestLimiter(0, 1)
+    ctx := context.Background()
+
+    release, err := limiter.acquire(ctx, "a")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Another platform is not limited by the requests to a.
+    releaseB, err := limiter.acquire(ctx, "b")
+    if err != nil {
+        t.Fatal(err)
+    }
+    releaseB()
+
+    timeout, cancel := context.WithTimeout(ctx, 10 * time.Millisecond)
+    defer cancel()
+    if _, err := limiter.acquire(timeout, "a"); err != context.DeadlineExceeded {
+        t.Errorf("acquire of a busy platform = %v, want %v", err, context.DeadlineExceeded)
+    }
+
+    release()
+    release, err = limiter.acquire(ctx, "a")
+    if err != nil {
+        t.Fatalf("acquire after the release = %v", err)
+    }
+    release()
+}
+
+func TestRequestLimiterDisabled(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    // Without limits, requests are neither counted nor delayed.
+    for _, limiter := range []*requestLimiter{nil, newRequestLimiter(0, 0)} {
+        for i := 0; i < 100; i++ {
+            release, err := limiter.acquire(ctx, "a")
+            if err != nil {
+                t.Fatalf("acquire of %v = %v", limiter, err)
+            }
+            release()
+        }
+    }
+}
@@ -1,25 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type AutoScalingPolicyCreateBody struct {
-    Description		string	`json:"description,omitempty"`
-    MeterName		string	`json:"meter_name"`
-    Name		string	`json:"name"`
-    Project		string	`json:"project"`
-    ScaledownThreshold	int	`json:"scaledown_threshold"`
-    ScaleMaxSize	int	`json:"scale_max_size"`
-    ScaleupThreshold	int	`json:"scaleup_threshold"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceAutoScalingPolicy() *schema.Resource {
     return &schema.Resource{
@@ -27,6 +16,10 @@ func resourceAutoScalingPolicy() *schema.Resource {
         Read:   resourceAutoScalingPolicyRead,
         Delete: resourceAutoScalingPolicyDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformProjectID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Delete: schema.DefaultTimeout(15 * time.Minute),
         },
@@ -94,6 +87,8 @@ func resourceAutoScalingPolicy() *schema.Resource {
 
 func resourceAutoScalingPolicyCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     description := d.Get("description").(string)
     meterName := d.Get("meter_name").(string)
     name := d.Get("name").(string)
@@ -102,9 +97,8 @@ func resourceAutoScalingPolicyCreate(d *schema.ResourceData, meta interface{}) e
     scaleMaxSize := d.Get("scale_max_size").(int)
     scaledownThreshold := d.Get("scaledown_threshold").(int)
     scaleupThreshold := d.Get("scaleup_threshold").(int)
-    resourcePath := fmt.Sprintf("api/v4/%s/auto_scaling_policies/", platform)
 
-    body := AutoScalingPolicyCreateBody {
+    opts := client.AutoScalingPolicyCreateOpts {
         Description:		description,
         MeterName:		meterName,
         Name:			name,
@@ -114,22 +108,13 @@ func resourceAutoScalingPolicyCreate(d *schema.ResourceData, meta interface{}) e
         ScaleupThreshold:	scaleupThreshold,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    policy, err := config.API.AutoScalingPolicies.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_auto_scaling_policy %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    d.SetId(fmt.Sprintf("%d", int(data["id"].(float64))))
+    d.SetId(policy.ID.String())
     d.Set("name", name)
     d.Set("platform", platform)
     d.Set("project", project)
@@ -138,52 +123,42 @@ func resourceAutoScalingPolicyCreate(d *schema.ResourceData, meta interface{}) e
 
 func resourceAutoScalingPolicyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     policyID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/auto_scaling_policies/%s/", platform, policyID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
- 
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve auto scaling policy %s on %s: %v", policyID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    policy, err := config.API.AutoScalingPolicies.Get(ctx, platform, policyID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve auto scaling policy json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf(
+            "Unable to retrieve auto scaling policy %s on %s", policyID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_auto_scaling_policy %s", d.Id())
-    d.Set("description", data["description"])
-    d.Set("meter_name", data["meter_name"])
-    d.Set("scale_max_size", data["scale_max_size"])
-    d.Set("scaledown_threshold", data["scaledown_threshold"])
-    d.Set("scaleup_threshold", data["scaleup_threshold"])
-    d.Set("user", data["user"])
+    d.Set("name", policy.Name)
+    d.Set("description", policy.Description)
+    d.Set("meter_name", policy.MeterName)
+    d.Set("scale_max_size", policy.ScaleMaxSize)
+    d.Set("scaledown_threshold", policy.ScaledownThreshold)
+    d.Set("scaleup_threshold", policy.ScaleupThreshold)
+    d.Set("user", policy.User)
     return nil
 }
 
 func resourceAutoScalingPolicyDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     policyID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/auto_scaling_policies/%s/", platform, policyID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.AutoScalingPolicies.Delete(ctx, platform, policyID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete auto scaling policy %s: on %s %v", policyID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED"},
-        Refresh:    policyStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = policyWaiter(ctx, config, platform, policyID).Wait(
+        []string{"ACTIVE"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_auto_scaling_policy %s to become Deleted: %v", policyID, err)
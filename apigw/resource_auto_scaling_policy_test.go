@@ -0,0 +1,96 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_auto_scaling_policy", &resource.Sweeper{
+        Name:		"apigw_auto_scaling_policy",
+        Dependencies:	[]string{"apigw_vcs"},
+        F:		testSweepAutoScalingPolicies,
+    })
+}
+
+func testSweepAutoScalingPolicies(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    policies, err := config.API.AutoScalingPolicies.List(context.Background(), platform, client.AutoScalingPolicyListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list auto scaling policies on %s: %v", platform, err)
+    }
+
+    for _, policy := range policies {
+        if !sweepable(policy.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_auto_scaling_policy %s", policy.ID)
+        if err := config.API.AutoScalingPolicies.Delete(context.Background(), platform, policy.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete auto scaling policy %s on %s: %v", policy.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccAutoScalingPolicy_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckAutoScalingPolicyDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccAutoScalingPolicyConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_auto_scaling_policy.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_auto_scaling_policy.test", "meter_name", "cpu_util"),
+                    resource.TestCheckResourceAttr("apigw_auto_scaling_policy.test", "scale_max_size", "3"),
+                    resource.TestCheckResourceAttr("apigw_auto_scaling_policy.test", "scaleup_threshold", "80"),
+                    resource.TestCheckResourceAttr("apigw_auto_scaling_policy.test", "scaledown_threshold", "20"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_auto_scaling_policy.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID(
+                    "apigw_auto_scaling_policy.test", "platform", "project", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckAutoScalingPolicyDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_auto_scaling_policy",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.AutoScalingPolicies.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccAutoScalingPolicyConfig is an auto scaling policy on the CPU load,
+// available to other resources as apigw_auto_scaling_policy.test.
+func testAccAutoScalingPolicyConfig(name string) string {
+    return fmt.Sprintf(`
+resource "apigw_auto_scaling_policy" "test" {
+  name                = %q
+  description         = "acceptance test"
+  meter_name          = "cpu_util"
+  scale_max_size      = 3
+  scaleup_threshold   = 80
+  scaledown_threshold = 20
+}
+`, name)
+}
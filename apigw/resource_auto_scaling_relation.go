@@ -1,23 +1,15 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
+    "strconv"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type AutoScalingRelationCreateBody struct {
-    AutoScalingPolicy	string	`json:"auto_scaling_policy"`
-    Loadbalancer	string	`json:"loadbalancer,omitempty"`
-    ProtocolPort	int	`json:"protocol_port,omitempty"`
-    ScaledownAction	string	`json:"scaledown_action,omitempty"`
-    ScaleupAction	string	`json:"scaleup_action,omitempty"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceAutoScalingRelation() *schema.Resource {
     return &schema.Resource{
@@ -25,6 +17,10 @@ func resourceAutoScalingRelation() *schema.Resource {
         Read:   resourceAutoScalingRelationRead,
         Delete: resourceAutoScalingRelationDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: resourceAutoScalingRelationImport,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(15 * time.Minute),
             Delete: schema.DefaultTimeout(15 * time.Minute),
@@ -88,6 +84,8 @@ func resourceAutoScalingRelation() *schema.Resource {
 
 func resourceAutoScalingRelationCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     autoScalingPolicy := d.Get("auto_scaling_policy").(string)
     loadbalancer := d.Get("loadbalancer").(string)
     platform := d.Get("platform").(string)
@@ -95,9 +93,8 @@ func resourceAutoScalingRelationCreate(d *schema.ResourceData, meta interface{})
     scaledownAction := d.Get("scaledown_action").(string)
     scaleupAction := d.Get("scaleup_action").(string)
     server := d.Get("server").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/servers/%s/auto_scaling_policy/", platform, server)
 
-    body := AutoScalingRelationCreateBody {
+    opts := client.AutoScalingPolicyAttachOpts {
         AutoScalingPolicy:	autoScalingPolicy,
         Loadbalancer:		loadbalancer,
         ProtocolPort:		protocolPort,
@@ -105,9 +102,7 @@ func resourceAutoScalingRelationCreate(d *schema.ResourceData, meta interface{})
         ScaleupAction:		scaleupAction,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    _, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    err := config.API.Servers.AttachAutoScalingPolicy(ctx, platform, server, opts)
 
     if err != nil {
         return fmt.Errorf(
@@ -121,16 +116,8 @@ func resourceAutoScalingRelationCreate(d *schema.ResourceData, meta interface{})
 
     d.SetId(fmt.Sprintf("%s/%s", server, autoScalingPolicy))
 
-    newPath := fmt.Sprintf("api/v4/%s/servers/%s/", platform, server)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"ASSOCIATING"},
-        Target:     []string{"ASSOCIATED", "ERROR"},
-        Refresh:    relationStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = relationWaiter(ctx, config, platform, server).Wait(
+        []string{"DISASSOCIATED", "ASSOCIATING"}, []string{"ASSOCIATED"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
             "Error waiting for apigw_auto_scaling_relation with policy %s and server %s to become Ready: %v",
@@ -152,36 +139,84 @@ func resourceAutoScalingRelationCreate(d *schema.ResourceData, meta interface{})
 
 func resourceAutoScalingRelationRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     serverID := d.Get("server").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/servers/%s/", platform, serverID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    server, err := config.API.Servers.Get(ctx, platform, serverID)
 
     if err != nil {
-        return fmt.Errorf(
-            "Unable to retrieve server %s on %s: %v", serverID, platform, err)
+        return checkDeleted(d, err, fmt.Sprintf(
+            "Unable to retrieve server %s on %s", serverID, platform))
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    log.Printf("[DEBUG] Retrieved apigw_auto_scaling_relation by server %s", serverID)
+    info := server.AutoScalingPolicy
+    if info == nil || info.ID.String() != d.Get("auto_scaling_policy").(string) {
+        if d.IsNewResource() {
+            return fmt.Errorf(
+                "Unable to retrieve auto scaling relation of server %s on %s", serverID, platform)
+        }
+        log.Printf("[WARN] Auto scaling relation %s is gone: removing it from state", d.Id())
+        d.SetId("")
+        return nil
+    }
+    d.Set("status", info.Status)
+    d.Set("status_reason", info.StatusReason)
+    return nil
+}
 
+// resourceAutoScalingRelationImport imports an auto scaling relation by an ID
+// of the form <platform>/<server>/<auto_scaling_policy>, optionally followed
+// by the settings of the relation which the gateway does not return as
+// arguments, e.g. <platform>/<server>/<auto_scaling_policy>/protocol_port=80.
+func resourceAutoScalingRelationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    id, arguments, err := splitImportArguments(d.Id(), 3)
     if err != nil {
-        return fmt.Errorf("Unable to retrieve server json data: %v", err)
+        return nil, err
     }
 
-    log.Printf("[DEBUG] Retrieved apigw_auto_scaling_relation by server %s", serverID)
-    info := data["auto_scaling_policy"].(map[string]interface{})
-    d.Set("status", info["status"].(string))
-    d.Set("status_reason", info["status_reason"].(string))
-    return nil
+    values, err := parseImportID(id, "platform", "server", "auto_scaling_policy")
+    if err != nil {
+        return nil, err
+    }
+
+    // Like Create, the settings which are not given are stored empty.
+    d.Set("loadbalancer", "")
+    d.Set("protocol_port", 0)
+    d.Set("scaledown_action", "")
+    d.Set("scaleup_action", "")
+    for key, value := range arguments {
+        switch key {
+        case "loadbalancer", "scaledown_action", "scaleup_action":
+            d.Set(key, value)
+        case "protocol_port":
+            port, err := strconv.Atoi(value)
+            if err != nil {
+                return nil, fmt.Errorf("Unexpected protocol_port %q in ID %q: %v", value, d.Id(), err)
+            }
+            d.Set(key, port)
+        default:
+            return nil, fmt.Errorf(
+                "Unexpected argument %q in ID %q, expected loadbalancer, protocol_port, scaledown_action or scaleup_action",
+                key, d.Id())
+        }
+    }
+
+    d.Set("platform", values[0])
+    d.Set("server", values[1])
+    d.Set("auto_scaling_policy", values[2])
+    d.SetId(fmt.Sprintf("%s/%s", values[1], values[2]))
+    return []*schema.ResourceData{d}, nil
 }
 
 func resourceAutoScalingRelationDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     serverID := d.Get("server").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/servers/%s/auto_scaling_policy/", platform, serverID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Servers.DetachAutoScalingPolicy(ctx, platform, serverID)
 
     if err != nil {
         return fmt.Errorf(
@@ -193,16 +228,8 @@ func resourceAutoScalingRelationDelete(d *schema.ResourceData, meta interface{})
         )
     }
 
-    newPath := fmt.Sprintf("api/v4/%s/servers/%s/", platform, serverID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DISASSOCIATING"},
-        Target:     []string{"DELETED", "ERROR"},
-        Refresh:    relationStateRefreshForDeletedFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = relationWaiter(ctx, config, platform, serverID).Wait(
+        []string{"ASSOCIATED", "DISASSOCIATING"}, []string{"DISASSOCIATED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_auto_scaling_relation to become Deleted: %v", err)
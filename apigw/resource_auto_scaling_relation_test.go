@@ -0,0 +1,64 @@
+package apigw
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func TestAccAutoScalingRelation_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckAutoScalingRelationDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccAutoScalingRelationConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_auto_scaling_relation.test", "status", "ASSOCIATED"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_auto_scaling_relation.test", "server", "apigw_vcs.test", "servers.0.id"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_auto_scaling_relation.test", "auto_scaling_policy",
+                        "apigw_auto_scaling_policy.test", "id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_auto_scaling_relation.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID(
+                    "apigw_auto_scaling_relation.test", "platform", "server", "auto_scaling_policy"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+// testAccCheckAutoScalingRelationDestroy verifies that the servers are not
+// associated with the policies anymore, or are gone.
+func testAccCheckAutoScalingRelationDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_auto_scaling_relation",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            server, err := api.Servers.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.Attributes["server"])
+            if found, err := testAccFound(err); !found {
+                return false, err
+            }
+            relation := server.AutoScalingPolicy
+            return relation != nil && relation.ID.String() == rs.Primary.Attributes["auto_scaling_policy"], nil
+        })(s)
+}
+
+func testAccAutoScalingRelationConfig(name string) string {
+    return testAccVCSConfig(name) + testAccAutoScalingPolicyConfig(name) + `
+resource "apigw_auto_scaling_relation" "test" {
+  server              = apigw_vcs.test.servers.0.id
+  auto_scaling_policy = apigw_auto_scaling_policy.test.id
+}
+`
+}
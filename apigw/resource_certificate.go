@@ -0,0 +1,176 @@
+package apigw
+
+import (
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func resourceCertificate() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCertificateCreate,
+        Read:   resourceCertificateRead,
+        Delete: resourceCertificateDelete,
+
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
+        Timeouts: &schema.ResourceTimeout{
+            Delete: schema.DefaultTimeout(10 * time.Minute),
+        },
+
+        CustomizeDiff: certificateCustomizeDiff,
+
+        Schema: map[string]*schema.Schema{
+            "certificate": {
+                Type:			schema.TypeString,
+                Required:		true,
+                ForceNew:		true,
+                ValidateFunc:		validateCertificate,
+                DiffSuppressFunc:	suppressEquivalentPEM,
+            },
+
+            "chain": {
+                Type:			schema.TypeString,
+                Optional:		true,
+                ForceNew:		true,
+                ValidateFunc:		validateCertificateChain,
+                DiffSuppressFunc:	suppressEquivalentPEM,
+            },
+
+            "create_time": {
+                Type:		schema.TypeString,
+                Computed:	true,
+                ForceNew:	true,
+            },
+
+            "expiry": {
+                Type:		schema.TypeString,
+                Computed:	true,
+            },
+
+            "name": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "platform": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            // The gateway does not return the private key, so it is empty
+            // after an import and must not replace the certificate.
+            "private_key": {
+                Type:			schema.TypeString,
+                Required:		true,
+                ForceNew:		true,
+                Sensitive:		true,
+                DiffSuppressFunc:	suppressImportedSecret,
+            },
+
+            "project": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+        },
+    }
+}
+
+func resourceCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
+    certificate := d.Get("certificate").(string)
+    chain := d.Get("chain").(string)
+    name := d.Get("name").(string)
+    platform := d.Get("platform").(string)
+    privateKey := d.Get("private_key").(string)
+    project := d.Get("project").(string)
+
+    opts := client.CertificateCreateOpts {
+        Certificate:	certificate,
+        Chain:		chain,
+        Name:		name,
+        PrivateKey:	privateKey,
+        Project:	project,
+    }
+
+    cert, err := config.API.Certificates.Create(ctx, platform, opts)
+
+    if err != nil {
+        return fmt.Errorf("Error creating apigw_certificate %s on %s: %v", name, platform, err)
+    }
+
+    d.SetId(cert.ID.String())
+    d.Set("platform", platform)
+    return resourceCertificateRead(d, meta)
+}
+
+func resourceCertificateRead(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
+    certID := d.Id()
+    platform := d.Get("platform").(string)
+    cert, err := config.API.Certificates.Get(ctx, platform, certID)
+
+    if err != nil {
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve certificate %s on %s", certID, platform))
+    }
+
+    log.Printf("[DEBUG] Retrieved apigw_certificate %s", d.Id())
+    d.Set("certificate", cert.Certificate)
+    d.Set("chain", cert.Chain)
+    d.Set("create_time", cert.CreateTime)
+    d.Set("expiry", cert.Expiration)
+    d.Set("name", cert.Name)
+    d.Set("project", cert.Project.String())
+    return nil
+}
+
+func resourceCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
+    certID := d.Id()
+    platform := d.Get("platform").(string)
+    err := config.API.Certificates.Delete(ctx, platform, certID)
+
+    if err != nil {
+        return fmt.Errorf("Unable to delete certificate %s on %s: %v", certID, platform, err)
+    }
+
+    _, err = certificateWaiter(ctx, config, platform, certID).Wait(
+        []string{"ACTIVE"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
+    if err != nil {
+        return fmt.Errorf(
+            "Error waiting for apigw_certificate %s to become DELETED: %v", certID, err)
+    }
+
+    d.SetId("")
+
+    return nil
+}
+
+// certificateErrors translates the gateway errors of certificate requests.
+type certificateErrors struct{}
+
+func (certificateErrors) Error409(e ErrUnexpectedResponseCode) error {
+    if e.Method == "DELETE" {
+        e.Info = fmt.Sprintf("The certificate is still used by a loadbalancer or listener, replace it there before deleting it, e.g. with create_before_destroy: %s", e.Message())
+    }
+    return ErrDefault409{e}
+}
+
+func init() {
+    registerErrorTranslator("certificates", certificateErrors{})
+}
@@ -0,0 +1,170 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "regexp"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_certificate", &resource.Sweeper{
+        Name:		"apigw_certificate",
+        Dependencies:	[]string{"apigw_loadbalancer"},
+        F:		testSweepCertificates,
+    })
+}
+
+func testSweepCertificates(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    certs, err := config.API.Certificates.List(context.Background(), platform, client.CertificateListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list certificates on %s: %v", platform, err)
+    }
+
+    for _, cert := range certs {
+        if !sweepable(cert.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_certificate %s", cert.ID)
+        if err := config.API.Certificates.Delete(context.Background(), platform, cert.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete certificate %s on %s: %v", cert.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccCertificate_basic(t *testing.T) {
+    name := testAccName(t)
+    notAfter := time.Now().Add(365 * 24 * time.Hour).UTC().Truncate(time.Second)
+    cert, key := testCertificatePEM(t, "www.example.com", notAfter)
+    _, otherKey := testCertificatePEM(t, "www.example.com", notAfter)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckCertificateDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config:		testAccCertificateConfig(name, cert, otherKey),
+                ExpectError:	regexp.MustCompile("private_key does not match certificate"),
+            },
+            {
+                Config: testAccCertificateConfig(name, cert, key),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_certificate.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_certificate.test", "certificate", cert),
+                    resource.TestCheckResourceAttr(
+                        "apigw_certificate.test", "expiry", notAfter.Format(time.RFC3339)),
+                ),
+            },
+            {
+                ResourceName:			"apigw_certificate.test",
+                ImportState:			true,
+                ImportStateIdFunc:		testAccImportStateID("apigw_certificate.test", "platform", "id"),
+                ImportStateVerify:		true,
+                ImportStateVerifyIgnore:	[]string{"private_key"},
+            },
+        },
+    })
+}
+
+func TestAccLoadBalancerListener_terminatedHTTPS(t *testing.T) {
+    name := testAccName(t)
+    var listenerID string
+    notAfter := time.Now().Add(365 * 24 * time.Hour)
+    cert, key := testCertificatePEM(t, "www.example.com", notAfter)
+    renewed, renewedKey := testCertificatePEM(t, "www.example.com", notAfter.Add(24 * time.Hour))
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckCertificateDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config:		testAccLoadBalancerHTTPSConfig(name, cert, key, ""),
+                ExpectError:	regexp.MustCompile("certificate is required with protocol TERMINATED_HTTPS"),
+            },
+            {
+                Config: testAccLoadBalancerHTTPSConfig(name, cert, key, "apigw_certificate.test.id"),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer_listener.https", &listenerID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_loadbalancer_listener.https", "certificate", "apigw_certificate.test", "id"),
+                ),
+            },
+            {
+                // A renewed certificate replaces the old one on the listener
+                // before the old one is deleted.
+                Config: testAccLoadBalancerHTTPSConfig(name, renewed, renewedKey, "apigw_certificate.test.id"),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer_listener.https", &listenerID),
+                    resource.TestCheckResourceAttr("apigw_certificate.test", "certificate", renewed),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_loadbalancer_listener.https", "certificate", "apigw_certificate.test", "id"),
+                ),
+            },
+        },
+    })
+}
+
+func testAccCheckCertificateDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_certificate",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Certificates.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccCertificateConfig is a certificate with the given PEM certificate
+// and private key, which is replaced before it is deleted.
+func testAccCertificateConfig(name, cert, key string) string {
+    return fmt.Sprintf(`
+resource "apigw_certificate" "test" {
+  name        = %q
+  certificate = <<EOT
+%sEOT
+  private_key = <<EOT
+%sEOT
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`, name, cert, key)
+}
+
+// testAccLoadBalancerHTTPSConfig is an HTTP loadbalancer with a listener
+// terminating HTTPS on port 443 with the given certificate expression.
+func testAccLoadBalancerHTTPSConfig(name, cert, key, certificate string) string {
+    attr := ""
+    if certificate != "" {
+        attr = "  certificate   = " + certificate + "\n"
+    }
+    return testAccCertificateConfig(name, cert, key) + testAccLoadBalancerConfig(name, "ROUND_ROBIN", "") +
+        fmt.Sprintf(`
+resource "apigw_loadbalancer_listener" "https" {
+  loadbalancer  = apigw_loadbalancer.test.id
+  protocol      = "TERMINATED_HTTPS"
+  protocol_port = 443
+  lb_method     = "ROUND_ROBIN"
+%s
+  members {
+    ip   = "10.10.0.11"
+    port = 8080
+  }
+}
+`, attr)
+}
@@ -0,0 +1,45 @@
+package apigw
+
+import (
+    "fmt"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccContainer_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_CONTAINER_SOLUTION") },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckSiteDestroy("apigw_container"),
+        Steps: []resource.TestStep{
+            {
+                Config: testAccContainerConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_container.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_container.test", "status", "Ready"),
+                    resource.TestCheckResourceAttr("apigw_container.test", "pod.#", "1"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_container.test", "solution", "data.apigw_solution.container", "id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_container.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_container.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccContainerConfig(name string) string {
+    return testAccSolutionConfig("container", "APIGW_TEST_CONTAINER_SOLUTION") + fmt.Sprintf(`
+resource "apigw_container" "test" {
+  name     = %q
+  solution = data.apigw_solution.container.id
+}
+`, name)
+}
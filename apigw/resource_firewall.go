@@ -1,30 +1,15 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
     "strconv"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
-
-type FirewallCreateBody struct {
-    AssociateNetworks	[]int	`json:"associate_networks,omitempty"`
-    Desc		string	`json:"desc,omitempty"`
-    Name		string	`json:"name"`
-    Project		string	`json:"project"`
-    Rules		[]int	`json:"rules,omitempty"`
-}
 
-type FirewallUpdateBody struct {
-    AssociateNetworks   *[]int	`json:"associate_networks,omitempty"`
-    Desc                string	`json:"desc,omitempty"`
-    Rules               *[]int	`json:"rules,omitempty"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceFirewall() *schema.Resource {
     return &schema.Resource{
@@ -33,6 +18,10 @@ func resourceFirewall() *schema.Resource {
         Update:	resourceFirewallUpdate,
         Delete: resourceFirewallDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(10 * time.Minute),
             Update: schema.DefaultTimeout(10 * time.Minute),
@@ -109,6 +98,8 @@ func resourceFirewall() *schema.Resource {
 
 func resourceFirewallCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
@@ -138,9 +129,8 @@ func resourceFirewallCreate(d *schema.ResourceData, meta interface{}) error {
 
 
     desc := d.Get("desc").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/firewalls/", platform)
 
-    body := FirewallCreateBody {
+    opts := client.FirewallCreateOpts {
         AssociateNetworks:	networkIDArray,
         Desc:			desc,
         Name:			name,
@@ -148,37 +138,20 @@ func resourceFirewallCreate(d *schema.ResourceData, meta interface{}) error {
         Rules:			ruleIDArray,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    firewall, err := config.API.Firewalls.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_firewall %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    firewallID := firewall.ID.String()
+    d.SetId(firewallID)
 
-    if err != nil {
-        return err
-    }
-
-    firewallID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", firewallID))
-
-    newPath := fmt.Sprintf("%s/%d/", resourcePath, firewallID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"PENDING_UPDATE", "PENDING_DELETE",},
-        Target:     []string{"ACTIVE", "ERROR", "INACTIVE"},
-        Refresh:    firewallStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = firewallWaiter(ctx, config, platform, firewallID).Wait(
+        []string{"PENDING_UPDATE", "PENDING_DELETE"}, []string{"ACTIVE", "INACTIVE"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
-            "Error waiting for apigw_firewall %d to become ACTIVE: %v", firewallID, err)
+            "Error waiting for apigw_firewall %s to become ACTIVE: %v", firewallID, err)
     }
 
     d.Set("name", name)
@@ -189,42 +162,40 @@ func resourceFirewallCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceFirewallRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     firewallID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/firewalls/%s/", platform, firewallID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve firewall %s on %s: %v", firewallID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    firewall, err := config.API.Firewalls.Get(ctx, platform, firewallID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve firewall json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve firewall %s on %s", firewallID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_firewall %s", d.Id())
-    networkInfo := flattenFirewallObjectInfo(data["associate_networks"].([]interface{}))
+    d.Set("name", firewall.Name)
+    d.Set("project", firewall.Project.String())
+    networkInfo := flattenFirewallObjectInfo(firewall.AssociateNetworks)
     d.Set("associate_networks", networkInfo)
-    d.Set("create_time", data["create_time"])
-    d.Set("desc", data["desc"])
-    ruleInfo := flattenFirewallObjectInfo(data["rules"].([]interface{}))
+    d.Set("create_time", firewall.CreateTime)
+    d.Set("desc", firewall.Desc)
+    ruleInfo := flattenFirewallObjectInfo(firewall.Rules)
     d.Set("rules", ruleInfo)
-    d.Set("status", data["status"])
-    d.Set("status_reason", data["status_reason"])
-    d.Set("user", data["user"].(map[string]interface{}))
+    d.Set("status", firewall.Status)
+    d.Set("status_reason", firewall.StatusReason)
+    d.Set("user", firewall.User)
     return nil
 }
 
 func resourceFirewallUpdate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutUpdate)
+    defer cancel()
     a_change := d.HasChange("associate_networks")
     d_change := d.HasChange("desc")
     r_change := d.HasChange("rules")
     if a_change || d_change || r_change {
-        var body FirewallUpdateBody
+        var opts client.FirewallUpdateOpts
         if a_change {
             _, newAssociateNetworks := d.GetChange("associate_networks")
             networks := newAssociateNetworks.([]interface{})
@@ -239,7 +210,7 @@ func resourceFirewallUpdate(d *schema.ResourceData, meta interface{}) error {
                 networkIDArray[i] = IDInt
             }
 
-            body.AssociateNetworks = &networkIDArray
+            opts.AssociateNetworks = &networkIDArray
         } else if r_change {
             _, newRules := d.GetChange("rules")
             rules := newRules.([]interface{})
@@ -254,35 +225,24 @@ func resourceFirewallUpdate(d *schema.ResourceData, meta interface{}) error {
                 ruleIDArray[i] = IDInt
             }
 
-            body.Rules = &ruleIDArray
+            opts.Rules = &ruleIDArray
         }
 
         if d_change {
             _, newDesc := d.GetChange("desc")
-            body.Desc = newDesc.(string)
+            opts.Desc = newDesc.(string)
         }
 
         firewallID := d.Id()
         platform := d.Get("platform").(string)
-        resourcePath := fmt.Sprintf("api/v4/%s/firewalls/%s/", platform, firewallID)
-
-        buf := new(bytes.Buffer)
-        json.NewEncoder(buf).Encode(body)
-        _, err := config.doNormalRequest(platform, resourcePath, "PATCH", buf)
+        err := config.API.Firewalls.Update(ctx, platform, firewallID, opts)
 
         if err != nil {
             return fmt.Errorf("Error updating apigw_firewall %s on %s: %v", firewallID, platform, err)
         }
 
-        stateConf := &resource.StateChangeConf{
-            Pending:    []string{"PENDING_UPDATE", "PENDING_DELETE",},
-            Target:     []string{"ACTIVE", "ERROR", "INACTIVE"},
-            Refresh:    firewallStateRefreshFunc(config, platform, resourcePath),
-            Timeout:    d.Timeout(schema.TimeoutUpdate),
-            Delay:      10 * time.Second,
-        }
-
-        _, err = stateConf.WaitForState()
+        _, err = firewallWaiter(ctx, config, platform, firewallID).Wait(
+            []string{"PENDING_UPDATE", "PENDING_DELETE"}, []string{"ACTIVE", "INACTIVE"}, d.Timeout(schema.TimeoutUpdate))
         if err != nil {
             return fmt.Errorf(
                 "Error waiting for apigw_firewall %s to become ACTIVE: %v", firewallID, err)
@@ -294,25 +254,18 @@ func resourceFirewallUpdate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceFirewallDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     platform := d.Get("platform").(string)
     firewallID := d.Id()
-    resourcePath := fmt.Sprintf("api/v4/%s/firewalls/%s/", platform, firewallID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Firewalls.Delete(ctx, platform, firewallID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete firewall %s: on %s %v", firewallID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING", "PENDING_UPDATE", "PENDING_DELETE"},
-        Target:     []string{"DELETED", "ERROR"},
-        Refresh:    firewallStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-        MinTimeout: 3 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = firewallWaiter(ctx, config, platform, firewallID).Wait(
+        []string{"DELETING", "PENDING_UPDATE", "PENDING_DELETE"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_firewall %s to become DELETED: %v", firewallID, err)
@@ -322,3 +275,17 @@ func resourceFirewallDelete(d *schema.ResourceData, meta interface{}) error {
 
     return nil
 }
+
+// firewallErrors translates the gateway errors of firewall requests.
+type firewallErrors struct{}
+
+func (firewallErrors) Error409(e ErrUnexpectedResponseCode) error {
+    if e.Method == "DELETE" {
+        e.Info = fmt.Sprintf("The firewall is still associated with networks, remove the associations before deleting it: %s", e.Message())
+    }
+    return ErrDefault409{e}
+}
+
+func init() {
+    registerErrorTranslator("firewalls", firewallErrors{})
+}
@@ -1,35 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
-
-type FirewallRuleCreateBody struct {
-    Action			string	`json:"action,omitempty"`
-    DestinationIPAddress	string	`json:"destination_ip_address,omitempty"`
-    DestinationPort		string	`json:"destination_port,omitempty"`
-    Name			string	`json:"name"`
-    Project			string	`json:"project"`
-    Protocol			string	`json:"protocol,omitempty"`
-    SourceIPAddress		string	`json:"source_ip_address,omitempty"`
-    SourcePort			string	`json:"source_port,omitempty"`
-}
 
-type FirewallRuleUpdateBody struct {
-    Action			string	`json:"action,omitempty"`
-    DestinationIPAddress	string	`json:"destination_ip_address,omitempty"`
-    DestinationPort		string	`json:"destination_port,omitempty"`
-    Protocol			string	`json:"protocol,omitempty"`
-    SourceIPAddress		string	`json:"source_ip_address,omitempty"`
-    SourcePort			string	`json:"source_port,omitempty"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceFirewallRule() *schema.Resource {
     return &schema.Resource{
@@ -38,6 +17,10 @@ func resourceFirewallRule() *schema.Resource {
         Update: resourceFirewallRuleUpdate,
         Delete: resourceFirewallRuleDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Delete: schema.DefaultTimeout(10 * time.Minute),
         },
@@ -111,6 +94,8 @@ func resourceFirewallRule() *schema.Resource {
 
 func resourceFirewallRuleCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     action := d.Get("action").(string)
     destinationIPAddress := d.Get("destination_ip_address").(string)
     destinationPort :=  d.Get("destination_port").(string)
@@ -120,9 +105,8 @@ func resourceFirewallRuleCreate(d *schema.ResourceData, meta interface{}) error
     protocol := d.Get("protocol").(string)
     sourceIPAddress := d.Get("source_ip_address").(string)
     sourcePort :=  d.Get("source_port").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/firewall_rules/", platform)
 
-    body := FirewallRuleCreateBody {
+    opts := client.FirewallRuleCreateOpts {
         Action:			action,
         DestinationIPAddress:	destinationIPAddress,
         DestinationPort:	destinationPort,
@@ -133,22 +117,13 @@ func resourceFirewallRuleCreate(d *schema.ResourceData, meta interface{}) error
         SourcePort:	 	sourcePort,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    rule, err := config.API.FirewallRules.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_firewall_rule %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    d.SetId(fmt.Sprintf("%d", int(data["id"].(float64))))
+    d.SetId(rule.ID.String())
     d.Set("name", name)
     d.Set("platform", platform)
     d.Set("project", project)
@@ -157,76 +132,71 @@ func resourceFirewallRuleCreate(d *schema.ResourceData, meta interface{}) error
 
 func resourceFirewallRuleRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     ruleID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/firewall_rules/%s/", platform, ruleID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve firewall rule %s on %s: %v", ruleID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    rule, err := config.API.FirewallRules.Get(ctx, platform, ruleID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve firewall rule json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve firewall rule %s on %s", ruleID, platform))
     }
 
-    log.Printf("[DEBUG] Retrieved apigw_firewall_rule %d", d.Id())
-    d.Set("action", data["action"])
-    d.Set("create_time", data["create_time"])
-    d.Set("destination_ip_address", data["destination_ip_address"])
-    d.Set("destination_port", data["destination_port"])
-    d.Set("protocol", data["protocol"])
-    d.Set("source_ip_address", data["source_ip_address"])
-    d.Set("source_port", data["source_port"])
+    log.Printf("[DEBUG] Retrieved apigw_firewall_rule %s", d.Id())
+    d.Set("name", rule.Name)
+    d.Set("project", rule.Project.String())
+    d.Set("ip_version", rule.IPVersion)
+    d.Set("action", rule.Action)
+    d.Set("create_time", rule.CreateTime)
+    d.Set("destination_ip_address", rule.DestinationIPAddress)
+    d.Set("destination_port", rule.DestinationPort)
+    d.Set("protocol", rule.Protocol)
+    d.Set("source_ip_address", rule.SourceIPAddress)
+    d.Set("source_port", rule.SourcePort)
     return nil
 }
 
 func resourceFirewallRuleUpdate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutUpdate)
+    defer cancel()
     if d.HasChange("action") || d.HasChange("destination_ip_address") ||
             d.HasChange("destination_port") || d.HasChange("protocol") ||
             d.HasChange("source_ip_address") || d.HasChange("source_port") {
-        var body FirewallRuleUpdateBody
+        var opts client.FirewallRuleUpdateOpts
         if d.HasChange("action") {
             _, newAction := d.GetChange("action")
-            body.Action = newAction.(string)
+            opts.Action = newAction.(string)
         }
 
         if d.HasChange("destination_ip_address") {
             _, newDestinationIPAddress := d.GetChange("destination_ip_address")
-            body.DestinationIPAddress = newDestinationIPAddress.(string)
+            opts.DestinationIPAddress = newDestinationIPAddress.(string)
         }
 
         if d.HasChange("destination_port") {
             _, newDestinationPort := d.GetChange("destination_port")
-            body.DestinationPort = newDestinationPort.(string)
+            opts.DestinationPort = newDestinationPort.(string)
         }
 
         if d.HasChange("protocol") {
             _, newProtocol := d.GetChange("protocol")
-            body.Protocol = newProtocol.(string)
+            opts.Protocol = newProtocol.(string)
         }
 
         if d.HasChange("source_ip_address") {
             _, newSourceIPAddress := d.GetChange("source_ip_address")
-            body.SourceIPAddress = newSourceIPAddress.(string)
+            opts.SourceIPAddress = newSourceIPAddress.(string)
         }
 
         if d.HasChange("source_port") {
             _, newSourcePort := d.GetChange("source_port")
-            body.SourcePort = newSourcePort.(string)
+            opts.SourcePort = newSourcePort.(string)
         }
 
         ruleID := d.Id()
         platform := d.Get("platform").(string)
-        resourcePath := fmt.Sprintf("api/v4/%s/firewall_rules/%s/", platform, ruleID)
-
-        buf := new(bytes.Buffer)
-        json.NewEncoder(buf).Encode(body)
-        _, err := config.doNormalRequest(platform, resourcePath, "PATCH", buf)
+        err := config.API.FirewallRules.Update(ctx, platform, ruleID, opts)
 
         if err != nil {
             return fmt.Errorf("Error updating apigw_firewall_rule %s on %s: %v", ruleID, platform, err)
@@ -238,25 +208,18 @@ func resourceFirewallRuleUpdate(d *schema.ResourceData, meta interface{}) error
 
 func resourceFirewallRuleDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     ruleID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/firewall_rules/%s/", platform, ruleID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.FirewallRules.Delete(ctx, platform, ruleID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete firewall rule %s: on %s %v", ruleID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED"},
-        Refresh:    firewallRuleStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-        MinTimeout: 3 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = firewallRuleWaiter(ctx, config, platform, ruleID).Wait(
+        []string{"ACTIVE"}, []string{"DELETED"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
             "Error waiting for apigw_firewall_rule %s to become DELETED: %v", ruleID, err)
@@ -266,3 +229,17 @@ func resourceFirewallRuleDelete(d *schema.ResourceData, meta interface{}) error
 
     return nil
 }
+
+// firewallRuleErrors translates the gateway errors of firewall rule requests.
+type firewallRuleErrors struct{}
+
+func (firewallRuleErrors) Error409(e ErrUnexpectedResponseCode) error {
+    if e.Method == "DELETE" {
+        e.Info = fmt.Sprintf("The firewall rule is still used by a firewall, remove it from the firewall before deleting it: %s", e.Message())
+    }
+    return ErrDefault409{e}
+}
+
+func init() {
+    registerErrorTranslator("firewall_rules", firewallRuleErrors{})
+}
@@ -0,0 +1,97 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_firewall_rule", &resource.Sweeper{
+        Name:		"apigw_firewall_rule",
+        Dependencies:	[]string{"apigw_firewall"},
+        F:		testSweepFirewallRules,
+    })
+}
+
+func testSweepFirewallRules(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    rules, err := config.API.FirewallRules.List(context.Background(), platform, client.FirewallRuleListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list firewall rules on %s: %v", platform, err)
+    }
+
+    for _, rule := range rules {
+        if !sweepable(rule.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_firewall_rule %s", rule.ID)
+        if err := config.API.FirewallRules.Delete(context.Background(), platform, rule.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete firewall rule %s on %s: %v", rule.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccFirewallRule_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckFirewallRuleDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccFirewallRuleConfig(name, "test", "22"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_firewall_rule.test", "name", name + "-test"),
+                    resource.TestCheckResourceAttr("apigw_firewall_rule.test", "action", "allow"),
+                    resource.TestCheckResourceAttr("apigw_firewall_rule.test", "protocol", "tcp"),
+                    resource.TestCheckResourceAttr("apigw_firewall_rule.test", "destination_port", "22"),
+                ),
+            },
+            {
+                Config: testAccFirewallRuleConfig(name, "test", "443"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_firewall_rule.test", "destination_port", "443"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_firewall_rule.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_firewall_rule.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckFirewallRuleDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_firewall_rule",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.FirewallRules.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccFirewallRuleConfig is a rule allowing TCP to port, available to
+// other resources as apigw_firewall_rule.<resourceName>.
+func testAccFirewallRuleConfig(name, resourceName, port string) string {
+    return fmt.Sprintf(`
+resource "apigw_firewall_rule" %q {
+  name             = "%s-%s"
+  protocol         = "tcp"
+  destination_port = %q
+}
+`, resourceName, name, resourceName, port)
+}
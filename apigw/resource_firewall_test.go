@@ -0,0 +1,119 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_firewall", &resource.Sweeper{
+        Name:	"apigw_firewall",
+        F:	testSweepFirewalls,
+    })
+}
+
+func testSweepFirewalls(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    firewalls, err := config.API.Firewalls.List(context.Background(), platform, client.FirewallListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list firewalls on %s: %v", platform, err)
+    }
+
+    for _, firewall := range firewalls {
+        if !sweepable(firewall.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_firewall %s", firewall.ID)
+        if err := config.API.Firewalls.Delete(context.Background(), platform, firewall.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete firewall %s on %s: %v", firewall.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccFirewall_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckFirewallDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccFirewallConfig(name, "ssh", "[]", "[apigw_firewall_rule.ssh.id]"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "desc", "ssh"),
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "status", "INACTIVE"),
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "rules.#", "1"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_firewall.test", "rules.0", "apigw_firewall_rule.ssh", "id"),
+                ),
+            },
+            {
+                Config: testAccFirewallConfig(
+                    name, "ssh and https", "[]", "[apigw_firewall_rule.ssh.id, apigw_firewall_rule.https.id]"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "desc", "ssh and https"),
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "rules.#", "2"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_firewall.test", "rules.1", "apigw_firewall_rule.https", "id"),
+                ),
+            },
+            {
+                Config: testAccFirewallConfig(
+                    name,
+                    "ssh and https",
+                    "[apigw_network.test.id]",
+                    "[apigw_firewall_rule.ssh.id, apigw_firewall_rule.https.id]"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttr("apigw_firewall.test", "associate_networks.#", "1"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_firewall.test", "associate_networks.0", "apigw_network.test", "id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_firewall.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_firewall.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckFirewallDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_firewall",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Firewalls.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccFirewallConfig is a firewall with the given description, associated
+// networks and rules, given as HCL lists.
+func testAccFirewallConfig(name, desc, networks, rules string) string {
+    config := testAccNetworkConfig(name) +
+        testAccFirewallRuleConfig(name, "ssh", "22") +
+        testAccFirewallRuleConfig(name, "https", "443")
+    return config + fmt.Sprintf(`
+resource "apigw_firewall" "test" {
+  name               = %q
+  desc               = %q
+  associate_networks = %s
+  rules              = %s
+}
+`, name, desc, networks, rules)
+}
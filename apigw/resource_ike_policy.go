@@ -1,25 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type IKEPolicyCreateBody struct {
-    AuthAlgorithm	string		`json:"auth_algorithm,omitempty"`
-    EncryptionAlgorithm	string		`json:"encryption_algorithm,omitempty"`
-    IKEVersion		string		`json:"ike_version,omitempty"`
-    Lifetime		int		`json:"lifetime,omitempty"`
-    Name		string		`json:"name"`
-    PFS			string		`json:"pfs,omitempty"`
-    Project		string		`json:"project"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceIKEPolicy() *schema.Resource {
     return &schema.Resource{
@@ -27,6 +16,10 @@ func resourceIKEPolicy() *schema.Resource {
         Read:   resourceIKEPolicyRead,
         Delete: resourceIKEPolicyDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformProjectID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Delete: schema.DefaultTimeout(3 * time.Minute),
         },
@@ -99,6 +92,8 @@ func resourceIKEPolicy() *schema.Resource {
 
 func resourceIKEPolicyCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     authAlgorithm := d.Get("auth_algorithm").(string)
     encryptionAlgorithm := d.Get("encryption_algorithm").(string)
     ikeVersion := d.Get("ike_version").(string)
@@ -107,9 +102,8 @@ func resourceIKEPolicyCreate(d *schema.ResourceData, meta interface{}) error {
     pfs := d.Get("pfs").(string)
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/ike_policies/", platform)
 
-    body := IKEPolicyCreateBody {
+    opts := client.IKEPolicyCreateOpts {
         AuthAlgorithm:		authAlgorithm,
         EncryptionAlgorithm:	encryptionAlgorithm,
         IKEVersion:		ikeVersion,
@@ -119,23 +113,13 @@ func resourceIKEPolicyCreate(d *schema.ResourceData, meta interface{}) error {
         Project:		project,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    policy, err := config.API.IKEPolicies.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_ike_policy %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    policyID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", policyID))
+    d.SetId(policy.ID.String())
     d.Set("name", name)
     d.Set("platform", platform)
     d.Set("project", project)
@@ -144,52 +128,41 @@ func resourceIKEPolicyCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceIKEPolicyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     policyID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/ike_policies/%s/", platform, policyID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    policy, err := config.API.IKEPolicies.Get(ctx, platform, policyID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve IKE policy %s on %s: %v", policyID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve IKE policy json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve IKE policy %s on %s", policyID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_ike_policy %s", d.Id())
-    d.Set("auth_algorithm", data["auth_algorithm"])
-    d.Set("encryption_algorithm", data["encryption_algorithm"])
-    d.Set("ike_version", data["ike_version"])
-    d.Set("lifetime", data["lifetime"])
-    d.Set("pfs", data["pfs"])
-    d.Set("user", data["user"])
+    d.Set("name", policy.Name)
+    d.Set("auth_algorithm", policy.AuthAlgorithm)
+    d.Set("encryption_algorithm", policy.EncryptionAlgorithm)
+    d.Set("ike_version", policy.IKEVersion)
+    d.Set("lifetime", policy.Lifetime)
+    d.Set("pfs", policy.PFS)
+    d.Set("user", policy.User)
     return nil
 }
 
 func resourceIKEPolicyDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     policyID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/ike_policies/%s/", platform, policyID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.IKEPolicies.Delete(ctx, platform, policyID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete IKE policy %s: on %s %v", policyID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED"},
-        Refresh:    IKEStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = ikePolicyWaiter(ctx, config, platform, policyID).Wait(
+        []string{"ACTIVE"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_ike_policy %s to become DELETED: %v", policyID, err)
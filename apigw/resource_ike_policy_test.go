@@ -0,0 +1,88 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_ike_policy", &resource.Sweeper{
+        Name:		"apigw_ike_policy",
+        Dependencies:	[]string{"apigw_vpn"},
+        F:		testSweepIKEPolicies,
+    })
+}
+
+func testSweepIKEPolicies(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    policies, err := config.API.IKEPolicies.List(context.Background(), platform, client.IKEPolicyListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list IKE policies on %s: %v", platform, err)
+    }
+
+    for _, policy := range policies {
+        if !sweepable(policy.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_ike_policy %s", policy.ID)
+        if err := config.API.IKEPolicies.Delete(context.Background(), platform, policy.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete IKE policy %s on %s: %v", policy.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccIKEPolicy_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckIKEPolicyDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccIKEPolicyConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_ike_policy.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_ike_policy.test", "lifetime", "7200"),
+                    resource.TestCheckResourceAttrSet("apigw_ike_policy.test", "auth_algorithm"),
+                    resource.TestCheckResourceAttrSet("apigw_ike_policy.test", "encryption_algorithm"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_ike_policy.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_ike_policy.test", "platform", "project", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckIKEPolicyDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_ike_policy",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.IKEPolicies.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+func testAccIKEPolicyConfig(name string) string {
+    return fmt.Sprintf(`
+resource "apigw_ike_policy" "test" {
+  name     = %q
+  lifetime = 7200
+}
+`, name)
+}
@@ -1,26 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type IPSecPolicyCreateBody struct {
-    AuthAlgorithm	string	`json:"auth_algorithm,omitempty"`
-    EncapsulationMode	string	`json:"encapsulation_mode,omitempty"`
-    EncryptionAlgorithm	string	`json:"encryption_algorithm,omitempty"`
-    TransformProtocol	string	`json:"transform_protocol,omitempty"`
-    Lifetime		int	`json:"lifetime,omitempty"`
-    Name		string	`json:"name"`
-    PFS			string	`json:"pfs,omitempty"`
-    Project		string	`json:"project"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceIPSecPolicy() *schema.Resource {
     return &schema.Resource{
@@ -28,6 +16,10 @@ func resourceIPSecPolicy() *schema.Resource {
         Read:   resourceIPSecPolicyRead,
         Delete: resourceIPSecPolicyDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformProjectID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Delete: schema.DefaultTimeout(3 * time.Minute),
         },
@@ -107,6 +99,8 @@ func resourceIPSecPolicy() *schema.Resource {
 
 func resourceIPSecPolicyCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     authAlgorithm := d.Get("auth_algorithm").(string)
     encapsulationMode := d.Get("encapsulation_mode").(string)
     encryptionAlgorithm := d.Get("encryption_algorithm").(string)
@@ -116,9 +110,8 @@ func resourceIPSecPolicyCreate(d *schema.ResourceData, meta interface{}) error {
     pfs := d.Get("pfs").(string)
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/ipsec_policies/", platform)
 
-    body := IPSecPolicyCreateBody {
+    opts := client.IPSecPolicyCreateOpts {
         AuthAlgorithm:		authAlgorithm,
         EncapsulationMode:	encapsulationMode,
         EncryptionAlgorithm:	encryptionAlgorithm,
@@ -129,23 +122,13 @@ func resourceIPSecPolicyCreate(d *schema.ResourceData, meta interface{}) error {
         Project:		project,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    policy, err := config.API.IPSecPolicies.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_ipsec_policy %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    policyID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", policyID))
+    d.SetId(policy.ID.String())
     d.Set("name", name)
     d.Set("platform", platform)
     d.Set("project", project)
@@ -154,53 +137,42 @@ func resourceIPSecPolicyCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceIPSecPolicyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     policyID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/ipsec_policies/%s/", platform, policyID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    policy, err := config.API.IPSecPolicies.Get(ctx, platform, policyID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve IP Sec policy %s on %s: %v", policyID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve IP Sec policy json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve IP Sec policy %s on %s", policyID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_ipsec_policy %s", d.Id())
-    d.Set("auth_algorithm", data["auth_algorithm"])
-    d.Set("encapsulation_mode", data["encapsulation_mode"])
-    d.Set("encryption_algorithm", data["encryption_algorithm"])
-    d.Set("transform_protocol", data["transform_protocol"])
-    d.Set("lifetime", data["lifetime"])
-    d.Set("pfs", data["pfs"])
-    d.Set("user", data["user"])
+    d.Set("name", policy.Name)
+    d.Set("auth_algorithm", policy.AuthAlgorithm)
+    d.Set("encapsulation_mode", policy.EncapsulationMode)
+    d.Set("encryption_algorithm", policy.EncryptionAlgorithm)
+    d.Set("transform_protocol", policy.TransformProtocol)
+    d.Set("lifetime", policy.Lifetime)
+    d.Set("pfs", policy.PFS)
+    d.Set("user", policy.User)
     return nil
 }
 
 func resourceIPSecPolicyDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     policyID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/ipsec_policies/%s/", platform, policyID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.IPSecPolicies.Delete(ctx, platform, policyID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete IKE policy %s: on %s %v", policyID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED"},
-        Refresh:    IPSecStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = ipsecPolicyWaiter(ctx, config, platform, policyID).Wait(
+        []string{"ACTIVE"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_ipsec_policy %s to become DELETED: %v", policyID, err)
@@ -0,0 +1,89 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_ipsec_policy", &resource.Sweeper{
+        Name:		"apigw_ipsec_policy",
+        Dependencies:	[]string{"apigw_vpn"},
+        F:		testSweepIPSecPolicies,
+    })
+}
+
+func testSweepIPSecPolicies(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    policies, err := config.API.IPSecPolicies.List(context.Background(), platform, client.IPSecPolicyListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list IPSec policies on %s: %v", platform, err)
+    }
+
+    for _, policy := range policies {
+        if !sweepable(policy.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_ipsec_policy %s", policy.ID)
+        if err := config.API.IPSecPolicies.Delete(context.Background(), platform, policy.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete IPSec policy %s on %s: %v", policy.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccIPSecPolicy_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckIPSecPolicyDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccIPSecPolicyConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_ipsec_policy.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_ipsec_policy.test", "lifetime", "7200"),
+                    resource.TestCheckResourceAttrSet("apigw_ipsec_policy.test", "auth_algorithm"),
+                    resource.TestCheckResourceAttrSet("apigw_ipsec_policy.test", "encryption_algorithm"),
+                    resource.TestCheckResourceAttrSet("apigw_ipsec_policy.test", "transform_protocol"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_ipsec_policy.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_ipsec_policy.test", "platform", "project", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckIPSecPolicyDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_ipsec_policy",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.IPSecPolicies.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+func testAccIPSecPolicyConfig(name string) string {
+    return fmt.Sprintf(`
+resource "apigw_ipsec_policy" "test" {
+  name     = %q
+  lifetime = 7200
+}
+`, name)
+}
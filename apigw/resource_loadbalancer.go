@@ -1,42 +1,15 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
+    "context"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
-
-type LoadBalancerCreateBody struct {
-    Delay		int	`json:"delay,omitempty"`
-    Desc		string	`json:"desc,omitempty"`
-    ExpectedCodes	string	`json:"expected_codes,omitempty"`
-    HTTPMethod		string	`json:"http_method,omitempty"`
-    LBMethod		string	`json:"lb_method"`
-    MaxRetries		int	`json:"max_retries,omitempty"`
-    MonitorType		string	`json:"monitor_type,omitempty"`
-    Name		string	`json:"name"`
-    PrivateNet		string	`json:"private_net"`
-    Protocol		string	`json:"protocol"`
-    ProtocolPort	int	`json:"protocol_port"`
-    Timeout		int	`json:"timeout,omitempty"`
-    URLPath		string	`json:"url_path,omitempty"`
-}
-
-type LoadBalancerUpdateBody struct {
-    LBMethod	string		`json:"lb_method,omitempty"`
-    Members	*[]MemberData	`json:"members,omitempty"`
-}
 
-type MemberData struct {
-    IP		string	`json:"ip,omitempty"`
-    Port	int	`json:"port,omitempty"`
-    Weight	int	`json:"weight,omitempty"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceLoadBalancer() *schema.Resource {
     return &schema.Resource{
@@ -45,18 +18,30 @@ func resourceLoadBalancer() *schema.Resource {
         Update:	resourceLoadBalancerUpdate,
         Delete: resourceLoadBalancerDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(15 * time.Minute),
             Update: schema.DefaultTimeout(15 * time.Minute),
             Delete: schema.DefaultTimeout(15 * time.Minute),
         },
 
+        CustomizeDiff: lbCertificateCustomizeDiff,
+
         Schema: map[string]*schema.Schema{
             "active_connections": {
                 Type:		schema.TypeInt,
                 Computed:	true,
             },
 
+            // Required with protocol TERMINATED_HTTPS.
+            "certificate": {
+                Type:		schema.TypeString,
+                Optional:	true,
+            },
+
             "create_time": {
                 Type:		schema.TypeString,
                 Computed:	true,
@@ -74,89 +59,23 @@ func resourceLoadBalancer() *schema.Resource {
                 Required:	true,
             },
 
+            // Computed, so that members added by apigw_loadbalancer_member
+            // are kept while members is not set. As an attribute, members = []
+            // removes all members.
             "members": {
-                Type:		schema.TypeList,
+                Type:		schema.TypeSet,
                 Optional:	true,
-                Elem: &schema.Resource{
-                    Schema: map[string]*schema.Schema{
-                        "ip": {
-                            Type:	schema.TypeString,
-                            Optional:	true,
-                        },
-
-                        "port": {
-                            Type:	schema.TypeInt,
-                            Optional:	true,
-                            Default:	80,
-                        },
-
-                        "status": {
-                            Type:       schema.TypeString,
-                            Computed:   true,
-                        },
-
-                        "weight": {
-                            Type:	schema.TypeInt,
-                            Optional:	true,
-                            Default:	1,
-                        },
-                    },
-                },
-
-                DiffSuppressFunc: lbMembersDiffFunc,
+                Computed:	true,
+                ConfigMode:	schema.SchemaConfigModeAttr,
+                Set:		lbMemberHash,
+                Elem:		lbMemberResource(),
             },
 
             "monitor": {
                 Type:		schema.TypeList,
                 Optional:	true,
-                Computed:	true,
-                Elem: &schema.Resource{
-                    Schema: map[string]*schema.Schema{
-                        "delay": {
-                            Type:	schema.TypeInt,
-                            Optional:	true,
-                            Computed:	true,
-                        },
-
-                        "expected_codes": {
-                            Type:	schema.TypeString,
-                            Optional:	true,
-                            Computed:	true,
-                        },
-
-                        "http_method": {
-                            Type:	schema.TypeString,
-                            Optional:	true,
-                            Computed:	true,
-                        },
-
-                        "max_retries": {
-                            Type:	schema.TypeInt,
-                            Optional:	true,
-                            Computed:	true,
-                        },
-
-                        "monitor_type": {
-                            Type:	schema.TypeString,
-                            Optional:	true,
-                            Computed:	true,
-                        },
-
-                        "timeout": {
-                            Type:	schema.TypeInt,
-                            Optional:	true,
-                            Computed:	true,
-                        },
-                    
-                        "url_path": {
-                            Type:	schema.TypeString,
-                            Optional:	true,
-                            Computed:	true,
-                        },
-                    },
-                },
-
                 MaxItems:	1,
+                Elem:		lbMonitorResource(),
             },
 
             "name": {
@@ -231,6 +150,9 @@ func resourceLoadBalancer() *schema.Resource {
 
 func resourceLoadBalancerCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
+    certificate := d.Get("certificate").(string)
     desc := d.Get("desc").(string)
     lbMethod := d.Get("lb_method").(string)
     name := d.Get("name").(string)
@@ -238,101 +160,79 @@ func resourceLoadBalancerCreate(d *schema.ResourceData, meta interface{}) error
     privateNet := d.Get("private_net").(string)
     protocol := d.Get("protocol").(string)
     protocolPort := d.Get("protocol_port").(int)
-    resourcePath := fmt.Sprintf("api/v4/%s/loadbalancers/", platform)
 
-    body := LoadBalancerCreateBody {
+    opts := client.LoadBalancerCreateOpts {
+        Certificate:	certificate,
         Desc:		desc,
         LBMethod:	lbMethod,
         Name:		name,
         PrivateNet:	privateNet,
         Protocol:	protocol,
         ProtocolPort:	protocolPort,
+        IdempotencyKey:	client.NewIdempotencyKey(),
     }
 
-    monitorArray := d.Get("monitor").([]interface{})
-    if len(monitorArray) != 0 {
-        info := monitorArray[0].(map[string]interface{})
-        body.Delay = info["delay"].(int)
-        body.ExpectedCodes = info["expected_codes"].(string)
-        body.HTTPMethod = info["http_method"].(string)
-        body.MaxRetries = info["max_retries"].(int)
-        body.MonitorType = info["monitor_type"].(string)
-        body.Timeout = info["timeout"].(int)
-        body.URLPath = info["url_path"].(string)
+    if monitor := expandLBMonitorInfo(d.Get("monitor").([]interface{})); monitor != nil {
+        opts.Delay = monitor.Delay
+        opts.ExpectedCodes = monitor.ExpectedCodes
+        opts.HTTPMethod = monitor.HTTPMethod
+        opts.MaxRetries = monitor.MaxRetries
+        opts.MonitorType = monitor.MonitorType
+        opts.Timeout = monitor.Timeout
+        opts.URLPath = monitor.URLPath
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    orphans := newOrphanLookup(ctx, config, "apigw_loadbalancer", name, platform,
+        func(ctx context.Context) ([]string, error) {
+            lbs, err := config.API.LoadBalancers.List(ctx, platform,
+                client.LoadBalancerListOpts{Name: name, PrivateNet: privateNet})
+            var ids []string
+            for _, lb := range lbs {
+                if lb.Name == name {
+                    ids = append(ids, lb.ID.String())
+                }
+            }
+            return ids, err
+        })
 
-    if err != nil {
-        return fmt.Errorf("Error creating apigw_loadbalancer %s on %s: %v", name, platform, err)
+    var lbID string
+    lb, err := config.API.LoadBalancers.Create(ctx, platform, opts)
+    if err == nil {
+        lbID = lb.ID.String()
+    } else {
+        lbID, err = orphans.adopt(ctx, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
     if err != nil {
-        return err
+        return fmt.Errorf("Error creating apigw_loadbalancer %s on %s: %v", name, platform, err)
     }
 
-    lbID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", lbID))
+    d.SetId(lbID)
 
-    newPath := fmt.Sprintf("%s/%d/", resourcePath, lbID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"BUILD",},
-        Target:     []string{"ACTIVE", "DOWN", "ERROR"},
-        Refresh:    lbStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = lbWaiter(ctx, config, platform, lbID).Wait(
+        []string{"BUILD"}, []string{"ACTIVE", "DOWN"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
             "Error waiting for apigw_loadbalancer %s to become ACTIVE: %v", lbID, err)
     }
 
     // Update LB if user define member data
-    members := d.Get("members").([]interface{})
-    if len(members) > 0 {
-        memberArray := make([]MemberData, len(members))
-        for i, member := range members {
-            detail := member.(map[string]interface{})
-            memberBody := MemberData{
-                IP:     detail["ip"].(string),
-                Port:   detail["port"].(int),
-                Weight: detail["weight"].(int),
-            }
-
-            memberArray[i] = memberBody
-        }
-
-        body := LoadBalancerUpdateBody {
+    if memberArray := expandLBMembersInfo(d.Get("members").(*schema.Set)); len(memberArray) > 0 {
+        updateOpts := client.LoadBalancerUpdateOpts {
             Members:	&memberArray,
         }
 
-        buf = new(bytes.Buffer)
-        json.NewEncoder(buf).Encode(body)
-        _, err = config.doNormalRequest(platform, newPath, "PATCH", buf)
+        err = config.API.LoadBalancers.Update(ctx, platform, lbID, updateOpts)
 
         if err != nil {
-            return fmt.Errorf("Error updating apigw_loadbalancer %d on %s: %v", lbID, platform, err)
-        }
-
-        stateConf := &resource.StateChangeConf{
-            Pending:    []string{"UPDATING"},
-            Target:     []string{"ACTIVE", "ERROR"},
-            Refresh:    lbStateRefreshFunc(config, platform, newPath),
-            Timeout:    d.Timeout(schema.TimeoutUpdate),
-            Delay:      10 * time.Second,
+            return fmt.Errorf("Error updating apigw_loadbalancer %s on %s: %v", lbID, platform, err)
         }
 
-        _, err = stateConf.WaitForState()
+        _, err = lbWaiter(ctx, config, platform, lbID).Wait(
+            []string{"UPDATING"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutUpdate))
         if err != nil {
             return fmt.Errorf(
-                "Error waiting for apigw_loadbalancer %d to become ACTIVE: %v", lbID, err)
+                "Error waiting for apigw_loadbalancer %s to become ACTIVE: %v", lbID, err)
         }
     }
 
@@ -348,99 +248,109 @@ func resourceLoadBalancerCreate(d *schema.ResourceData, meta interface{}) error
 
 func resourceLoadBalancerRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     lbID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/loadbalancers/%s/", platform, lbID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    lb, err := config.API.LoadBalancers.Get(ctx, platform, lbID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve loadbalancer %s on %s: %v", lbID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve loadbalancer json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve loadbalancer %s on %s", lbID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_loadbalancer %s", d.Id())
-    d.Set("active_connections", data["active_connections"])
-    d.Set("create_time", data["create_time"])
-    d.Set("lb_method", data["lb_method"])
-    d.Set("members", data["members"])
-    if monitor, ok := data["monitor"].(map[string]interface{}); ok {
-        monitorInfo := flattenLBMonitorInfo(monitor)
+    d.Set("name", lb.Name)
+    d.Set("desc", lb.Desc)
+    d.Set("private_net", lb.PrivateNet.String())
+    d.Set("protocol", lb.Protocol)
+    d.Set("protocol_port", lb.ProtocolPort)
+    d.Set("active_connections", lb.ActiveConnections)
+    d.Set("certificate", lb.Certificate.String())
+    d.Set("create_time", lb.CreateTime)
+    d.Set("lb_method", lb.LBMethod)
+    d.Set("members", flattenLBMembersInfo(lb.Members))
+    if lb.Monitor != nil {
+        monitorInfo := flattenLBMonitorInfo(lb.Monitor)
         d.Set("monitor", monitorInfo)
     } else {
         d.Set("monitor", nil)
     }
 
-    d.Set("status", data["status"])
-    d.Set("status_reason", data["status_reason"])
-    d.Set("total_connections", data["total_connections"])
-    d.Set("user", data["user"].(map[string]interface{}))
-    d.Set("vip", data["vip"])
-    if waf, ok := data["waf"].(map[string]interface{}); ok {
-        waf["id"] = fmt.Sprintf("%d", int(waf["id"].(float64)))
-        d.Set("waf", waf)
-    } else {
-        d.Set("waf", data["waf"])
-    }
+    d.Set("status", lb.Status)
+    d.Set("status_reason", lb.StatusReason)
+    d.Set("total_connections", lb.TotalConnections)
+    d.Set("user", lb.User)
+    d.Set("vip", lb.VIP)
+    d.Set("waf", lb.WAF)
 
     return nil
 }
 
 func resourceLoadBalancerUpdate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
-    var body LoadBalancerUpdateBody
+    ctx, cancel := config.operationContext(d, schema.TimeoutUpdate)
+    defer cancel()
+    var opts client.LoadBalancerUpdateOpts
+    if d.HasChange("certificate") {
+        _, newCertificate := d.GetChange("certificate")
+        opts.Certificate = newCertificate.(string)
+    }
+
     if d.HasChange("lb_method") {
         _, newLBMethod := d.GetChange("lb_method")
-        body.LBMethod = newLBMethod.(string)
+        opts.LBMethod = newLBMethod.(string)
     }
 
     if d.HasChange("members") {
-        _, newMembers := d.GetChange("members")
-        members := newMembers.([]interface{})
-        memberArray := make([]MemberData, len(members))
-        for i, member := range members {
-            detail := member.(map[string]interface{})
-            memberBody := MemberData{
-                IP:	detail["ip"].(string),
-                Port:	detail["port"].(int),
-                Weight:	detail["weight"].(int),
-            }
-             
-            memberArray[i] = memberBody
-        }
+        memberArray := expandLBMembersInfo(d.Get("members").(*schema.Set))
+        opts.Members = &memberArray
+    }
 
-        body.Members = &memberArray
+    // A removed monitor block deletes the monitor, a changed one is updated.
+    deleteMonitor := false
+    if d.HasChange("monitor") {
+        monitor := expandLBMonitorInfo(d.Get("monitor").([]interface{}))
+        if monitor == nil {
+            deleteMonitor = true
+        } else {
+            opts.LoadBalancerMonitorUpdateOpts = lbMonitorUpdateOpts(d, monitor)
+        }
     }
 
     lbID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/loadbalancers/%s/", platform, lbID)
+    key := lbMutexKey(platform, lbID)
+    osMutexKV.Lock(key)
+    defer osMutexKV.Unlock(key)
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    _, err := config.doNormalRequest(platform, resourcePath, "PATCH", buf)
+    if opts != (client.LoadBalancerUpdateOpts{}) {
+        err := config.API.LoadBalancers.Update(ctx, platform, lbID, opts)
 
-    if err != nil {
-        return fmt.Errorf("Error updating apigw_loadbalancer %s on %s: %v", lbID, platform, err)
-    }
+        if err != nil {
+            return fmt.Errorf("Error updating apigw_loadbalancer %s on %s: %v", lbID, platform, err)
+        }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"UPDATING"},
-        Target:     []string{"ACTIVE", "ERROR"},
-        Refresh:    lbStateRefreshFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutUpdate),
-        Delay:      10 * time.Second,
+        _, err = lbWaiter(ctx, config, platform, lbID).Wait(
+            []string{"UPDATING"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutUpdate))
+        if err != nil {
+            return fmt.Errorf(
+                "Error waiting for apigw_loadbalancer %s to become ACTIVE: %v", lbID, err)
+        }
     }
 
-    _, err = stateConf.WaitForState()
-    if err != nil {
-        return fmt.Errorf(
-            "Error waiting for apigw_loadbalancer %s to become ACTIVE: %v", lbID, err)
+    if deleteMonitor {
+        err := config.API.LoadBalancers.DeleteMonitor(ctx, platform, lbID)
+
+        if err != nil && !IsNotFound(err) {
+            return fmt.Errorf("Unable to delete monitor of apigw_loadbalancer %s on %s: %v", lbID, platform, err)
+        }
+
+        _, err = lbWaiter(ctx, config, platform, lbID).Wait(
+            []string{"UPDATING"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutUpdate))
+        if err != nil {
+            return fmt.Errorf(
+                "Error waiting for apigw_loadbalancer %s to become ACTIVE: %v", lbID, err)
+        }
     }
 
     return resourceLoadBalancerRead(d, meta)
@@ -448,24 +358,18 @@ func resourceLoadBalancerUpdate(d *schema.ResourceData, meta interface{}) error
 
 func resourceLoadBalancerDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     platform := d.Get("platform").(string)
     lbID := d.Id()
-    resourcePath := fmt.Sprintf("api/v4/%s/loadbalancers/%s/", platform, lbID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.LoadBalancers.Delete(ctx, platform, lbID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete loadbalancer %s: on %s %v", lbID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED", "ERROR"},
-        Refresh:    lbStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = lbWaiter(ctx, config, platform, lbID).Wait(
+        []string{"DELETING"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_loadbalancer %s to become DELETED: %v", lbID, err)
@@ -475,3 +379,17 @@ func resourceLoadBalancerDelete(d *schema.ResourceData, meta interface{}) error
 
     return nil
 }
+
+// loadBalancerErrors translates the gateway errors of loadbalancer requests.
+type loadBalancerErrors struct{}
+
+func (loadBalancerErrors) Error409(e ErrUnexpectedResponseCode) error {
+    if e.Method == "DELETE" {
+        e.Info = fmt.Sprintf("The loadbalancer still has listeners, delete them before deleting it: %s", e.Message())
+    }
+    return ErrDefault409{e}
+}
+
+func init() {
+    registerErrorTranslator("loadbalancers", loadBalancerErrors{})
+}
@@ -0,0 +1,314 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func resourceLoadBalancerListener() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceLoadBalancerListenerCreate,
+        Read:   resourceLoadBalancerListenerRead,
+        Update:	resourceLoadBalancerListenerUpdate,
+        Delete: resourceLoadBalancerListenerDelete,
+
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(15 * time.Minute),
+            Update: schema.DefaultTimeout(15 * time.Minute),
+            Delete: schema.DefaultTimeout(15 * time.Minute),
+        },
+
+        CustomizeDiff: lbCertificateCustomizeDiff,
+
+        Schema: map[string]*schema.Schema{
+            // Required with protocol TERMINATED_HTTPS.
+            "certificate": {
+                Type:		schema.TypeString,
+                Optional:	true,
+            },
+
+            "create_time": {
+                Type:		schema.TypeString,
+                Computed:	true,
+                ForceNew:	true,
+            },
+
+            "lb_method": {
+                Type:		schema.TypeString,
+                Required:	true,
+            },
+
+            "loadbalancer": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "members": {
+                Type:		schema.TypeSet,
+                Optional:	true,
+                Set:		lbMemberHash,
+                Elem:		lbMemberResource(),
+            },
+
+            "monitor": {
+                Type:		schema.TypeList,
+                Optional:	true,
+                MaxItems:	1,
+                Elem:		lbMonitorResource(),
+            },
+
+            "platform": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "protocol": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "protocol_port": {
+                Type:		schema.TypeInt,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "status": {
+                Type:		schema.TypeString,
+                Computed:	true,
+            },
+
+            "status_reason": {
+                Type:		schema.TypeString,
+                Computed:	true,
+            },
+        },
+    }
+}
+
+func resourceLoadBalancerListenerCreate(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
+    certificate := d.Get("certificate").(string)
+    lbID := d.Get("loadbalancer").(string)
+    lbMethod := d.Get("lb_method").(string)
+    platform := d.Get("platform").(string)
+    protocol := d.Get("protocol").(string)
+    protocolPort := d.Get("protocol_port").(int)
+
+    opts := client.ListenerCreateOpts {
+        Certificate:	certificate,
+        LBMethod:	lbMethod,
+        LoadBalancer:	lbID,
+        Members:	expandLBMembersInfo(d.Get("members").(*schema.Set)),
+        Protocol:	protocol,
+        ProtocolPort:	protocolPort,
+        IdempotencyKey:	client.NewIdempotencyKey(),
+    }
+
+    if monitor := expandLBMonitorInfo(d.Get("monitor").([]interface{})); monitor != nil {
+        opts.Delay = monitor.Delay
+        opts.ExpectedCodes = monitor.ExpectedCodes
+        opts.HTTPMethod = monitor.HTTPMethod
+        opts.MaxRetries = monitor.MaxRetries
+        opts.MonitorType = monitor.MonitorType
+        opts.Timeout = monitor.Timeout
+        opts.URLPath = monitor.URLPath
+    }
+
+    // A listener is identified by its port, which is unique within its
+    // loadbalancer.
+    name := fmt.Sprintf("%s:%d", lbID, protocolPort)
+    orphans := newOrphanLookup(ctx, config, "apigw_loadbalancer_listener", name, platform,
+        func(ctx context.Context) ([]string, error) {
+            listeners, err := config.API.Listeners.List(ctx, platform,
+                client.ListenerListOpts{LoadBalancer: lbID})
+            var ids []string
+            for _, listener := range listeners {
+                if listener.ProtocolPort == protocolPort {
+                    ids = append(ids, listener.ID.String())
+                }
+            }
+            return ids, err
+        })
+
+    key := lbMutexKey(platform, lbID)
+    osMutexKV.Lock(key)
+    defer osMutexKV.Unlock(key)
+
+    var listenerID string
+    listener, err := config.API.Listeners.Create(ctx, platform, opts)
+    if err == nil {
+        listenerID = listener.ID.String()
+    } else {
+        listenerID, err = orphans.adopt(ctx, err)
+    }
+
+    if err != nil {
+        return fmt.Errorf("Error creating apigw_loadbalancer_listener %s on %s: %v", name, platform, err)
+    }
+
+    d.SetId(listenerID)
+
+    _, err = listenerWaiter(ctx, config, platform, listenerID).Wait(
+        []string{"BUILD"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutCreate))
+    if err != nil {
+        return fmt.Errorf(
+            "Error waiting for apigw_loadbalancer_listener %s to become ACTIVE: %v", listenerID, err)
+    }
+
+    d.Set("platform", platform)
+    return resourceLoadBalancerListenerRead(d, meta)
+}
+
+func resourceLoadBalancerListenerRead(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
+    listenerID := d.Id()
+    platform := d.Get("platform").(string)
+    listener, err := config.API.Listeners.Get(ctx, platform, listenerID)
+
+    if err != nil {
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve listener %s on %s", listenerID, platform))
+    }
+
+    log.Printf("[DEBUG] Retrieved apigw_loadbalancer_listener %s", d.Id())
+    d.Set("certificate", listener.Certificate.String())
+    d.Set("create_time", listener.CreateTime)
+    d.Set("lb_method", listener.LBMethod)
+    d.Set("loadbalancer", listener.LoadBalancer.String())
+    d.Set("members", flattenLBMembersInfo(listener.Members))
+    d.Set("monitor", flattenLBMonitorInfo(listener.Monitor))
+    d.Set("protocol", listener.Protocol)
+    d.Set("protocol_port", listener.ProtocolPort)
+    d.Set("status", listener.Status)
+    d.Set("status_reason", listener.StatusReason)
+    return nil
+}
+
+func resourceLoadBalancerListenerUpdate(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutUpdate)
+    defer cancel()
+    var opts client.ListenerUpdateOpts
+    if d.HasChange("certificate") {
+        _, newCertificate := d.GetChange("certificate")
+        opts.Certificate = newCertificate.(string)
+    }
+
+    if d.HasChange("lb_method") {
+        _, newLBMethod := d.GetChange("lb_method")
+        opts.LBMethod = newLBMethod.(string)
+    }
+
+    if d.HasChange("members") {
+        memberArray := expandLBMembersInfo(d.Get("members").(*schema.Set))
+        opts.Members = &memberArray
+    }
+
+    // A removed monitor block deletes the monitor, a changed one is updated.
+    deleteMonitor := false
+    if d.HasChange("monitor") {
+        monitor := expandLBMonitorInfo(d.Get("monitor").([]interface{}))
+        if monitor == nil {
+            deleteMonitor = true
+        } else {
+            opts.LoadBalancerMonitorUpdateOpts = lbMonitorUpdateOpts(d, monitor)
+        }
+    }
+
+    listenerID := d.Id()
+    platform := d.Get("platform").(string)
+    key := lbMutexKey(platform, d.Get("loadbalancer").(string))
+    osMutexKV.Lock(key)
+    defer osMutexKV.Unlock(key)
+
+    if opts != (client.ListenerUpdateOpts{}) {
+        err := config.API.Listeners.Update(ctx, platform, listenerID, opts)
+
+        if err != nil {
+            return fmt.Errorf("Error updating apigw_loadbalancer_listener %s on %s: %v", listenerID, platform, err)
+        }
+
+        _, err = listenerWaiter(ctx, config, platform, listenerID).Wait(
+            []string{"UPDATING"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutUpdate))
+        if err != nil {
+            return fmt.Errorf(
+                "Error waiting for apigw_loadbalancer_listener %s to become ACTIVE: %v", listenerID, err)
+        }
+    }
+
+    if deleteMonitor {
+        err := config.API.Listeners.DeleteMonitor(ctx, platform, listenerID)
+
+        if err != nil && !IsNotFound(err) {
+            return fmt.Errorf("Unable to delete monitor of apigw_loadbalancer_listener %s on %s: %v", listenerID, platform, err)
+        }
+
+        _, err = listenerWaiter(ctx, config, platform, listenerID).Wait(
+            []string{"UPDATING"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutUpdate))
+        if err != nil {
+            return fmt.Errorf(
+                "Error waiting for apigw_loadbalancer_listener %s to become ACTIVE: %v", listenerID, err)
+        }
+    }
+
+    return resourceLoadBalancerListenerRead(d, meta)
+}
+
+func resourceLoadBalancerListenerDelete(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
+    platform := d.Get("platform").(string)
+    listenerID := d.Id()
+    key := lbMutexKey(platform, d.Get("loadbalancer").(string))
+    osMutexKV.Lock(key)
+    defer osMutexKV.Unlock(key)
+
+    err := config.API.Listeners.Delete(ctx, platform, listenerID)
+
+    if err != nil {
+        return fmt.Errorf("Unable to delete listener %s on %s: %v", listenerID, platform, err)
+    }
+
+    _, err = listenerWaiter(ctx, config, platform, listenerID).Wait(
+        []string{"DELETING"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
+    if err != nil {
+        return fmt.Errorf(
+            "Error waiting for apigw_loadbalancer_listener %s to become DELETED: %v", listenerID, err)
+    }
+
+    d.SetId("")
+
+    return nil
+}
+
+// listenerErrors translates the gateway errors of listener requests.
+type listenerErrors struct{}
+
+func (listenerErrors) Error409(e ErrUnexpectedResponseCode) error {
+    if e.Method == "POST" {
+        e.Info = fmt.Sprintf("The port is already used by the loadbalancer or another of its listeners: %s", e.Message())
+    }
+    return ErrDefault409{e}
+}
+
+func init() {
+    registerErrorTranslator("listeners", listenerErrors{})
+}
@@ -0,0 +1,155 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_loadbalancer_listener", &resource.Sweeper{
+        Name:	"apigw_loadbalancer_listener",
+        F:	testSweepLoadBalancerListeners,
+    })
+}
+
+func testSweepLoadBalancerListeners(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    lbs, err := config.API.LoadBalancers.List(context.Background(), platform, client.LoadBalancerListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list loadbalancers on %s: %v", platform, err)
+    }
+
+    for _, lb := range lbs {
+        if !sweepable(lb.Name) {
+            continue
+        }
+        listeners, err := config.API.Listeners.List(context.Background(), platform,
+            client.ListenerListOpts{LoadBalancer: lb.ID.String()})
+        if err != nil {
+            return fmt.Errorf("Unable to list listeners of loadbalancer %s on %s: %v", lb.ID, platform, err)
+        }
+        for _, listener := range listeners {
+            log.Printf("[INFO] Sweeping apigw_loadbalancer_listener %s", listener.ID)
+            if err := config.API.Listeners.Delete(context.Background(), platform, listener.ID.String()); err != nil {
+                log.Printf("[ERROR] Unable to delete listener %s on %s: %v", listener.ID, platform, err)
+            }
+        }
+    }
+    return nil
+}
+
+func TestAccLoadBalancerListener_basic(t *testing.T) {
+    name := testAccName(t)
+    var lbID, listenerID string
+    members := `
+  members {
+    ip   = "10.10.0.11"
+    port = 8443
+  }
+`
+    reweighted := `
+  members {
+    ip     = "10.10.0.11"
+    port   = 8443
+    weight = 3
+  }
+  members {
+    ip   = "10.10.0.12"
+    port = 8443
+  }
+`
+    monitor := `
+  monitor {
+    monitor_type = "TCP"
+    delay        = 5
+    timeout      = 3
+    max_retries  = 3
+  }
+`
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckLoadBalancerListenerDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccLoadBalancerListenerConfig(name, "ROUND_ROBIN", members + monitor),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer_listener.https", &listenerID),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_loadbalancer_listener.https", "loadbalancer", "apigw_loadbalancer.test", "id"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "protocol", "TCP"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "protocol_port", "443"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "members.#", "1"),
+                    testAccCheckLoadBalancerMembersAttr(
+                        "apigw_loadbalancer_listener.https", "10.10.0.11", "status", "ONLINE"),
+                    resource.TestCheckResourceAttr(
+                        "apigw_loadbalancer_listener.https", "monitor.0.monitor_type", "TCP"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "members.#", "0"),
+                ),
+            },
+            {
+                // The pool, lb_method and monitor are changed in place.
+                Config: testAccLoadBalancerListenerConfig(name, "LEAST_CONNECTIONS", reweighted),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer_listener.https", &listenerID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "lb_method", "LEAST_CONNECTIONS"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "members.#", "2"),
+                    testAccCheckLoadBalancerMembersAttr(
+                        "apigw_loadbalancer_listener.https", "10.10.0.11", "weight", "3"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_listener.https", "monitor.#", "0"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_loadbalancer_listener.https",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_loadbalancer_listener.https", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+            {
+                // Removing the listener keeps the loadbalancer.
+                Config: testAccLoadBalancerConfig(name, "ROUND_ROBIN", ""),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    testAccCheckLoadBalancerListenerDestroy,
+                ),
+            },
+        },
+    })
+}
+
+func testAccCheckLoadBalancerListenerDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_loadbalancer_listener",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Listeners.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccLoadBalancerListenerConfig is an HTTP loadbalancer with a TCP
+// listener on port 443, with the given lb_method and members and monitor
+// blocks.
+func testAccLoadBalancerListenerConfig(name, lbMethod, blocks string) string {
+    return testAccLoadBalancerConfig(name, "ROUND_ROBIN", "") + fmt.Sprintf(`
+resource "apigw_loadbalancer_listener" "https" {
+  loadbalancer  = apigw_loadbalancer.test.id
+  protocol      = "TCP"
+  protocol_port = 443
+  lb_method     = %q
+%s}
+`, lbMethod, blocks)
+}
@@ -0,0 +1,218 @@
+package apigw
+
+import (
+    "fmt"
+    "log"
+    "net"
+    "strconv"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func resourceLoadBalancerMember() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceLoadBalancerMemberCreate,
+        Read:   resourceLoadBalancerMemberRead,
+        Update:	resourceLoadBalancerMemberUpdate,
+        Delete: resourceLoadBalancerMemberDelete,
+
+        Importer: &schema.ResourceImporter{
+            State: resourceLoadBalancerMemberImport,
+        },
+
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(15 * time.Minute),
+            Update: schema.DefaultTimeout(15 * time.Minute),
+            Delete: schema.DefaultTimeout(15 * time.Minute),
+        },
+
+        Schema: map[string]*schema.Schema{
+            "ip": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "loadbalancer": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "platform": {
+                Type:		schema.TypeString,
+                Required:	true,
+                ForceNew:	true,
+            },
+
+            "port": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                ForceNew:	true,
+                Default:	80,
+            },
+
+            "status": {
+                Type:		schema.TypeString,
+                Computed:	true,
+            },
+
+            "weight": {
+                Type:		schema.TypeInt,
+                Optional:	true,
+                Default:	1,
+            },
+        },
+    }
+}
+
+// lbMemberID returns the ID of the member with ip and port of the
+// loadbalancer lbID, of the form <loadbalancer>/<ip>:<port>.
+func lbMemberID(lbID string, ip string, port int) string {
+    return fmt.Sprintf("%s/%s", lbID, net.JoinHostPort(ip, strconv.Itoa(port)))
+}
+
+func resourceLoadBalancerMemberCreate(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
+    ip := d.Get("ip").(string)
+    lbID := d.Get("loadbalancer").(string)
+    platform := d.Get("platform").(string)
+    port := d.Get("port").(int)
+    weight := d.Get("weight").(int)
+
+    err := updateLBMembers(ctx, config, platform, lbID, d.Timeout(schema.TimeoutCreate),
+        func(members []client.LoadBalancerMember) ([]client.LoadBalancerMember, error) {
+            if findLBMember(members, ip, port) >= 0 {
+                return nil, fmt.Errorf(
+                    "Member %s of apigw_loadbalancer %s already exists, it must be imported",
+                    net.JoinHostPort(ip, strconv.Itoa(port)), lbID)
+            }
+            member := client.LoadBalancerMember{
+                IP:	ip,
+                Port:	port,
+                Weight:	weight,
+            }
+            return append(members, member), nil
+        })
+
+    if err != nil {
+        return fmt.Errorf("Error creating apigw_loadbalancer_member on %s: %v", platform, err)
+    }
+
+    d.SetId(lbMemberID(lbID, ip, port))
+    return resourceLoadBalancerMemberRead(d, meta)
+}
+
+func resourceLoadBalancerMemberRead(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
+    ip := d.Get("ip").(string)
+    lbID := d.Get("loadbalancer").(string)
+    platform := d.Get("platform").(string)
+    port := d.Get("port").(int)
+    lb, err := config.API.LoadBalancers.Get(ctx, platform, lbID)
+
+    if err != nil {
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve loadbalancer %s on %s", lbID, platform))
+    }
+
+    log.Printf("[DEBUG] Retrieved apigw_loadbalancer_member %s", d.Id())
+    i := findLBMember(lb.Members, ip, port)
+    if i < 0 {
+        if d.IsNewResource() {
+            return fmt.Errorf("Member %s not found", d.Id())
+        }
+        log.Printf("[WARN] Loadbalancer member %s is gone: removing it from state", d.Id())
+        d.SetId("")
+        return nil
+    }
+
+    d.Set("status", lb.Members[i].Status)
+    d.Set("weight", lb.Members[i].Weight)
+    return nil
+}
+
+func resourceLoadBalancerMemberUpdate(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutUpdate)
+    defer cancel()
+    ip := d.Get("ip").(string)
+    lbID := d.Get("loadbalancer").(string)
+    platform := d.Get("platform").(string)
+    port := d.Get("port").(int)
+    weight := d.Get("weight").(int)
+
+    err := updateLBMembers(ctx, config, platform, lbID, d.Timeout(schema.TimeoutUpdate),
+        func(members []client.LoadBalancerMember) ([]client.LoadBalancerMember, error) {
+            i := findLBMember(members, ip, port)
+            if i < 0 {
+                return nil, fmt.Errorf("Member %s not found", d.Id())
+            }
+            members[i].Weight = weight
+            return members, nil
+        })
+
+    if err != nil {
+        return fmt.Errorf("Error updating apigw_loadbalancer_member %s on %s: %v", d.Id(), platform, err)
+    }
+
+    return resourceLoadBalancerMemberRead(d, meta)
+}
+
+func resourceLoadBalancerMemberDelete(d *schema.ResourceData, meta interface{}) error {
+    config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
+    ip := d.Get("ip").(string)
+    lbID := d.Get("loadbalancer").(string)
+    platform := d.Get("platform").(string)
+    port := d.Get("port").(int)
+
+    err := updateLBMembers(ctx, config, platform, lbID, d.Timeout(schema.TimeoutDelete),
+        func(members []client.LoadBalancerMember) ([]client.LoadBalancerMember, error) {
+            i := findLBMember(members, ip, port)
+            if i < 0 {
+                return members, nil
+            }
+            return append(members[:i], members[i+1:]...), nil
+        })
+
+    if err != nil && !IsNotFound(err) {
+        return fmt.Errorf("Unable to delete loadbalancer member %s on %s: %v", d.Id(), platform, err)
+    }
+
+    d.SetId("")
+
+    return nil
+}
+
+// resourceLoadBalancerMemberImport imports a loadbalancer member by an ID of
+// the form <platform>/<loadbalancer>/<ip>:<port>.
+func resourceLoadBalancerMemberImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "loadbalancer", "ip:port")
+    if err != nil {
+        return nil, err
+    }
+
+    ip, portValue, err := net.SplitHostPort(values[2])
+    if err != nil {
+        return nil, fmt.Errorf("Unexpected member %q: %v", values[2], err)
+    }
+    port, err := strconv.Atoi(portValue)
+    if err != nil {
+        return nil, fmt.Errorf("Unexpected port of member %q: %v", values[2], err)
+    }
+
+    d.Set("platform", values[0])
+    d.Set("loadbalancer", values[1])
+    d.Set("ip", ip)
+    d.Set("port", port)
+    d.SetId(lbMemberID(values[1], ip, port))
+    return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,91 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func TestAccLoadBalancerMember_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckLoadBalancerMemberDestroy,
+        Steps: []resource.TestStep{
+            {
+                // Both members are added in parallel.
+                Config: testAccLoadBalancerMemberConfig(name, 2),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_member.a", "ip", "10.10.0.11"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_member.a", "port", "8080"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_member.a", "weight", "1"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_member.a", "status", "ONLINE"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_member.b", "weight", "2"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_loadbalancer_member.a", "loadbalancer", "apigw_loadbalancer.test", "id"),
+                ),
+            },
+            {
+                // The loadbalancer keeps the members it does not manage.
+                Config:		testAccLoadBalancerMemberConfig(name, 2),
+                PlanOnly:	true,
+            },
+            {
+                Config: testAccLoadBalancerMemberConfig(name, 3),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_member.a", "weight", "1"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer_member.b", "weight", "3"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_loadbalancer_member.b",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_loadbalancer_member.b", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckLoadBalancerMemberDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_loadbalancer_member",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            lb, err := api.LoadBalancers.Get(context.Background(),
+                rs.Primary.Attributes["platform"], rs.Primary.Attributes["loadbalancer"])
+            if found, err := testAccFound(err); !found {
+                return false, err
+            }
+            port, err := strconv.Atoi(rs.Primary.Attributes["port"])
+            if err != nil {
+                return false, err
+            }
+            return findLBMember(lb.Members, rs.Primary.Attributes["ip"], port) >= 0, nil
+        })(s)
+}
+
+// testAccLoadBalancerMemberConfig is a loadbalancer without members blocks
+// and two members, the second one with weight.
+func testAccLoadBalancerMemberConfig(name string, weight int) string {
+    return testAccLoadBalancerConfig(name, "ROUND_ROBIN", "") + fmt.Sprintf(`
+resource "apigw_loadbalancer_member" "a" {
+  loadbalancer = apigw_loadbalancer.test.id
+  ip           = "10.10.0.11"
+  port         = 8080
+}
+
+resource "apigw_loadbalancer_member" "b" {
+  loadbalancer = apigw_loadbalancer.test.id
+  ip           = "10.10.0.12"
+  port         = 8080
+  weight       = %d
+}
+`, weight)
+}
@@ -0,0 +1,331 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_loadbalancer", &resource.Sweeper{
+        Name:		"apigw_loadbalancer",
+        Dependencies:	[]string{"apigw_loadbalancer_listener"},
+        F:		testSweepLoadBalancers,
+    })
+}
+
+func testSweepLoadBalancers(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    lbs, err := config.API.LoadBalancers.List(context.Background(), platform, client.LoadBalancerListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list loadbalancers on %s: %v", platform, err)
+    }
+
+    for _, lb := range lbs {
+        if !sweepable(lb.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_loadbalancer %s", lb.ID)
+        if err := config.API.LoadBalancers.Delete(context.Background(), platform, lb.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete loadbalancer %s on %s: %v", lb.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccLoadBalancer_basic(t *testing.T) {
+    name := testAccName(t)
+    var lbID string
+    members := `
+  members {
+    ip   = "10.10.0.11"
+    port = 8080
+  }
+  members {
+    ip     = "10.10.0.12"
+    port   = 8080
+    weight = 2
+  }
+`
+    reordered := `
+  members {
+    ip     = "10.10.0.12"
+    port   = 8080
+    weight = 2
+  }
+  members {
+    ip   = "10.10.0.11"
+    port = 8080
+  }
+`
+    reweighted := `
+  members {
+    ip     = "10.10.0.11"
+    port   = 8080
+    weight = 3
+  }
+  members {
+    ip     = "10.10.0.12"
+    port   = 8080
+    weight = 2
+  }
+`
+    changed := `
+  members {
+    ip   = "10.10.0.13"
+    port = 8080
+  }
+`
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckLoadBalancerDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccLoadBalancerConfig(name, "ROUND_ROBIN", members),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "lb_method", "ROUND_ROBIN"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "members.#", "2"),
+                    testAccCheckLoadBalancerMembersAttr("apigw_loadbalancer.test", "10.10.0.11", "weight", "1"),
+                    testAccCheckLoadBalancerMembersAttr("apigw_loadbalancer.test", "10.10.0.11", "status", "ONLINE"),
+                    testAccCheckLoadBalancerMembersAttr("apigw_loadbalancer.test", "10.10.0.12", "weight", "2"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.monitor_type", "HTTP"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_loadbalancer.test", "private_net", "apigw_network.test", "id"),
+                ),
+            },
+            {
+                // Reordering members is not a change.
+                Config:		testAccLoadBalancerConfig(name, "ROUND_ROBIN", reordered),
+                PlanOnly:	true,
+            },
+            {
+                // Reweighting a member replaces it in members, which is
+                // applied by a single update of the loadbalancer.
+                Config: testAccLoadBalancerConfig(name, "ROUND_ROBIN", reweighted),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "members.#", "2"),
+                    testAccCheckLoadBalancerMembersAttr("apigw_loadbalancer.test", "10.10.0.11", "weight", "3"),
+                    testAccCheckLoadBalancerMembersAttr("apigw_loadbalancer.test", "10.10.0.12", "weight", "2"),
+                ),
+            },
+            {
+                Config: testAccLoadBalancerConfig(name, "LEAST_CONNECTIONS", changed),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "lb_method", "LEAST_CONNECTIONS"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "members.#", "1"),
+                    testAccCheckLoadBalancerMembersAttr("apigw_loadbalancer.test", "10.10.0.13", "weight", "1"),
+                ),
+            },
+            {
+                // Without members blocks the members are kept, as they may
+                // be managed by apigw_loadbalancer_member.
+                Config: testAccLoadBalancerConfig(name, "LEAST_CONNECTIONS", ""),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "members.#", "1"),
+                ),
+            },
+            {
+                Config: testAccLoadBalancerConfig(name, "LEAST_CONNECTIONS", "\n  members = []\n"),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "members.#", "0"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_loadbalancer.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_loadbalancer.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func TestAccLoadBalancer_monitor(t *testing.T) {
+    name := testAccName(t)
+    var lbID string
+    monitor := `
+  monitor {
+    monitor_type = "HTTP"
+    delay        = 5
+    timeout      = 3
+    max_retries  = 3
+    http_method  = "GET"
+    url_path     = "/"
+  }
+`
+    changed := `
+  monitor {
+    monitor_type = "HTTP"
+    delay        = 10
+    timeout      = 5
+    max_retries  = 2
+    http_method  = "HEAD"
+    url_path     = "/health"
+  }
+`
+    tcp := `
+  monitor {
+    monitor_type = "TCP"
+    delay        = 10
+    timeout      = 5
+    max_retries  = 0
+  }
+`
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckLoadBalancerDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccLoadBalancerMonitorConfig(name, monitor),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.delay", "5"),
+                ),
+            },
+            {
+                Config: testAccLoadBalancerMonitorConfig(name, changed),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.delay", "10"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.timeout", "5"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.max_retries", "2"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.http_method", "HEAD"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.url_path", "/health"),
+                ),
+            },
+            {
+                // A monitor field is also updated to 0.
+                Config: testAccLoadBalancerMonitorConfig(name, tcp),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.monitor_type", "TCP"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.max_retries", "0"),
+                ),
+            },
+            {
+                // Removing the monitor block deletes the monitor.
+                Config: testAccLoadBalancerMonitorConfig(name, ""),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.#", "0"),
+                ),
+            },
+            {
+                Config: testAccLoadBalancerMonitorConfig(name, monitor),
+                Check: resource.ComposeTestCheckFunc(
+                    testAccCheckLoadBalancerNotRecreated("apigw_loadbalancer.test", &lbID),
+                    resource.TestCheckResourceAttr("apigw_loadbalancer.test", "monitor.0.monitor_type", "HTTP"),
+                ),
+            },
+        },
+    })
+}
+
+// testAccCheckLoadBalancerMembersAttr checks the attribute key of the member
+// with ip in the members set of the loadbalancer name.
+func testAccCheckLoadBalancerMembersAttr(name, ip, key, value string) resource.TestCheckFunc {
+    return func(s *terraform.State) error {
+        rs, ok := s.RootModule().Resources[name]
+        if !ok {
+            return fmt.Errorf("Not found: %s", name)
+        }
+        for attr, v := range rs.Primary.Attributes {
+            if !strings.HasPrefix(attr, "members.") || !strings.HasSuffix(attr, ".ip") || v != ip {
+                continue
+            }
+            attr = strings.TrimSuffix(attr, "ip") + key
+            if got := rs.Primary.Attributes[attr]; got != value {
+                return fmt.Errorf("%s: %s = %q, want %q", name, attr, got, value)
+            }
+            return nil
+        }
+        return fmt.Errorf("%s: no member %s", name, ip)
+    }
+}
+
+// testAccCheckLoadBalancerNotRecreated remembers the ID of the loadbalancer
+// name in id on its first call, and fails if it changes afterwards.
+func testAccCheckLoadBalancerNotRecreated(name string, id *string) resource.TestCheckFunc {
+    return func(s *terraform.State) error {
+        rs, ok := s.RootModule().Resources[name]
+        if !ok {
+            return fmt.Errorf("Not found: %s", name)
+        }
+        if *id == "" {
+            *id = rs.Primary.ID
+        } else if rs.Primary.ID != *id {
+            return fmt.Errorf("%s was recreated as %s, want it updated in place", *id, rs.Primary.ID)
+        }
+        return nil
+    }
+}
+
+func testAccCheckLoadBalancerDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_loadbalancer",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.LoadBalancers.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccLoadBalancerConfig is an HTTP loadbalancer on a network, with the
+// given members blocks.
+func testAccLoadBalancerConfig(name, lbMethod, members string) string {
+    return testAccNetworkConfig(name) + fmt.Sprintf(`
+resource "apigw_loadbalancer" "test" {
+  name          = %q
+  private_net   = apigw_network.test.id
+  protocol      = "HTTP"
+  protocol_port = 80
+  lb_method     = %q
+
+  monitor {
+    monitor_type   = "HTTP"
+    delay          = 5
+    timeout        = 3
+    max_retries    = 3
+    http_method    = "GET"
+    url_path       = "/"
+    expected_codes = "200"
+  }
+%s}
+`, name, lbMethod, members)
+}
+
+// testAccLoadBalancerMonitorConfig is an HTTP loadbalancer on a network
+// without members, with the given monitor block.
+func testAccLoadBalancerMonitorConfig(name, monitor string) string {
+    return testAccNetworkConfig(name) + fmt.Sprintf(`
+resource "apigw_loadbalancer" "test" {
+  name          = %q
+  private_net   = apigw_network.test.id
+  protocol      = "HTTP"
+  protocol_port = 80
+  lb_method     = "ROUND_ROBIN"
+%s}
+`, name, monitor)
+}
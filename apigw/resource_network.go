@@ -1,25 +1,15 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
+    "context"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type NetworkCreateBody struct {
-    CIDR	string		`json:"cidr"`
-    DNSDomain	string		`json:"dns_domain,omitempty"`
-    Gateway	string		`json:"gateway"`
-    Name	string		`json:"name"`
-    Nameservers	[]string	`json:"nameservers,omitempty"`
-    Project	string		`json:"project"`
-    WithRouter	bool		`json:"with_router,omitempty"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceNetwork() *schema.Resource {
     return &schema.Resource{
@@ -27,6 +17,10 @@ func resourceNetwork() *schema.Resource {
         Read:   resourceNetworkRead,
         Delete: resourceNetworkDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(10 * time.Minute),
             Delete: schema.DefaultTimeout(10 * time.Minute),
@@ -137,6 +131,8 @@ func resourceNetwork() *schema.Resource {
 
 func resourceNetworkCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     cidr := d.Get("cidr").(string)
     dnsDomain := d.Get("dns_domain").(string)
     gateway := d.Get("gateway").(string)
@@ -145,9 +141,8 @@ func resourceNetworkCreate(d *schema.ResourceData, meta interface{}) error {
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
     withRouter := d.Get("with_router").(bool)
-    resourcePath := fmt.Sprintf("api/v4/%s/networks/", platform)
 
-    body := NetworkCreateBody {
+    opts := client.NetworkCreateOpts {
         CIDR:		cidr,
         DNSDomain:	dnsDomain,
         Gateway:	gateway,
@@ -155,39 +150,40 @@ func resourceNetworkCreate(d *schema.ResourceData, meta interface{}) error {
         Nameservers:	flattenNetworkNameServersInfo(nameServers),
         Project:	project,
         WithRouter:	withRouter,
+        IdempotencyKey:	client.NewIdempotencyKey(),
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
-
-    if err != nil {
-        return fmt.Errorf("Error creating apigw_network %s on %s: %v", name, platform, err)
+    orphans := newOrphanLookup(ctx, config, "apigw_network", name, platform,
+        func(ctx context.Context) ([]string, error) {
+            networks, err := config.API.Networks.List(ctx, platform, client.NetworkListOpts{Project: project})
+            var ids []string
+            for _, network := range networks {
+                if network.Name == name {
+                    ids = append(ids, network.ID.String())
+                }
+            }
+            return ids, err
+        })
+
+    var networkID string
+    network, err := config.API.Networks.Create(ctx, platform, opts)
+    if err == nil {
+        networkID = network.ID.String()
+    } else {
+        networkID, err = orphans.adopt(ctx, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
     if err != nil {
-        return err
+        return fmt.Errorf("Error creating apigw_network %s on %s: %v", name, platform, err)
     }
 
-    networkID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", networkID))
+    d.SetId(networkID)
 
-    newPath := fmt.Sprintf("%s%d/", resourcePath, networkID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"BUILD"},
-        Target:     []string{"ACTIVE", "ERROR"},
-        Refresh:    networkStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = networkWaiter(ctx, config, platform, networkID).Wait(
+        []string{"BUILD"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
-            "Error waiting for apigw_network %d to become ACTIVE: %v", networkID, err)
+            "Error waiting for apigw_network %s to become ACTIVE: %v", networkID, err)
     }
 
     d.Set("name", name)
@@ -198,64 +194,50 @@ func resourceNetworkCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceNetworkRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     networkID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/networks/%s/", platform, networkID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    network, err := config.API.Networks.Get(ctx, platform, networkID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve network %s on %s: %v", networkID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve network json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve network %s on %s", networkID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_network %s", d.Id())
-    d.Set("cidr", data["cidr"])
-    d.Set("create_time", data["create_time"])
-    if data["dns_domain"] != nil {
-        d.Set("dns_domain", data["dns_domain"])
+    d.Set("name", network.Name)
+    d.Set("project", network.Project.String())
+    d.Set("gateway", network.Gateway)
+    d.Set("cidr", network.CIDR)
+    d.Set("create_time", network.CreateTime)
+    if network.DNSDomain != "" {
+        d.Set("dns_domain", network.DNSDomain)
     }
-    d.Set("ext_net", data["ext_net"])
-    if firewall, ok := data["firewall"].(map[string]interface{}); ok {
-        firewall["id"] = fmt.Sprintf("%v", firewall["id"])
-        d.Set("firewall", firewall)
-    } else {
-        d.Set("firewall", data["firewall"])
-    }
-    d.Set("ip_version", data["ip_version"])
-    d.Set("nameservers", data["nameservers"])
-    d.Set("status", data["status"])
-    d.Set("status_reason", data["status_reason"])
-    d.Set("user", data["user"])
-    d.Set("with_router", data["with_router"])
+    d.Set("ext_net", network.ExtNet)
+    d.Set("firewall", network.Firewall)
+    d.Set("ip_version", network.IPVersion)
+    d.Set("nameservers", network.Nameservers)
+    d.Set("status", network.Status)
+    d.Set("status_reason", network.StatusReason)
+    d.Set("user", network.User)
+    d.Set("with_router", network.WithRouter)
     return nil
 }
 
 func resourceNetworkDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     networkID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/networks/%s/", platform, networkID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Networks.Delete(ctx, platform, networkID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete network %s: on %s %v", networkID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED", "ERROR"},
-        Refresh:    networkStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = networkWaiter(ctx, config, platform, networkID).Wait(
+        []string{"DELETING"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_network %s to become DELETED: %v", networkID, err)
@@ -265,3 +247,17 @@ func resourceNetworkDelete(d *schema.ResourceData, meta interface{}) error {
 
     return nil
 }
+
+// networkErrors translates the gateway errors of network requests.
+type networkErrors struct{}
+
+func (networkErrors) Error409(e ErrUnexpectedResponseCode) error {
+    if e.Method == "DELETE" {
+        e.Info = fmt.Sprintf("The network still has attached servers, load balancers or VPN services, remove them before deleting it: %s", e.Message())
+    }
+    return ErrDefault409{e}
+}
+
+func init() {
+    registerErrorTranslator("networks", networkErrors{})
+}
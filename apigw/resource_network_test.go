@@ -0,0 +1,92 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_network", &resource.Sweeper{
+        Name:		"apigw_network",
+        Dependencies:	[]string{"apigw_firewall", "apigw_loadbalancer", "apigw_vpn"},
+        F:		testSweepNetworks,
+    })
+}
+
+func testSweepNetworks(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    networks, err := config.API.Networks.List(context.Background(), platform, client.NetworkListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list networks on %s: %v", platform, err)
+    }
+
+    for _, network := range networks {
+        if !sweepable(network.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_network %s", network.ID)
+        if err := config.API.Networks.Delete(context.Background(), platform, network.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete network %s on %s: %v", network.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccNetwork_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckNetworkDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccNetworkConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_network.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_network.test", "platform", testAccPlatform()),
+                    resource.TestCheckResourceAttr("apigw_network.test", "project", testAccProject()),
+                    resource.TestCheckResourceAttr("apigw_network.test", "cidr", "10.10.0.0/24"),
+                    resource.TestCheckResourceAttr("apigw_network.test", "status", "ACTIVE"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_network.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_network.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckNetworkDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_network",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Networks.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccNetworkConfig is a network named name, on which other resources can
+// be created as apigw_network.test.
+func testAccNetworkConfig(name string) string {
+    return fmt.Sprintf(`
+resource "apigw_network" "test" {
+  name    = %q
+  cidr    = "10.10.0.0/24"
+  gateway = "10.10.0.1"
+}
+`, name)
+}
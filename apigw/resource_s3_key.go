@@ -1,24 +1,22 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
-type KeyCreateBody struct {
-    Name	string	`json:"name"`
-}
-
 func resourceS3Key() *schema.Resource {
     return &schema.Resource{
         Create: resourceS3KeyCreate,
         Read:   resourceS3KeyRead,
         Delete: resourceS3KeyDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: resourceS3KeyImport,
+        },
+
         Schema: map[string]*schema.Schema{
             "access_key": {
                 Type:		schema.TypeString,
@@ -53,30 +51,18 @@ func resourceS3Key() *schema.Resource {
 
 func resourceS3KeyCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/projects/%s/key/", platform, project)
 
-    body := KeyCreateBody {
-        Name:	name,
-    }
-
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    err := config.API.Keys.Create(ctx, platform, project, name)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_s3_key %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
     d.SetId(fmt.Sprintf("%s-%s", project, name))
     d.Set("name", name)
     d.Set("platform", platform)
@@ -86,50 +72,56 @@ func resourceS3KeyCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceS3KeyRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/projects/%s/key/", platform, projectID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
- 
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve s3 key on %s: %v", platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    keys, err := config.API.Keys.List(ctx, platform, projectID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve s3 key json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve s3 key on %s", platform))
     }
 
     log.Printf("[DEBUG] Retrieved s3_key %s", d.Id())
-    privateS3List := data["private"].([]interface{})
-    for _, v := range privateS3List {
-        key := v.(map[string]interface{})
-        name := key["name"].(string)
-        if name == d.Get("name").(string) {
-            d.Set("access_key", key["access_key"])
-            d.Set("secret_key", key["secret_key"])
-            break
+    for _, key := range keys.Private {
+        if key.Name == d.Get("name").(string) {
+            d.Set("access_key", key.AccessKey)
+            d.Set("secret_key", key.SecretKey)
+            return nil
         }
     }
+
+    if d.IsNewResource() {
+        return fmt.Errorf("Unable to retrieve s3 key %s on %s", d.Get("name").(string), platform)
+    }
+    log.Printf("[WARN] S3 key %s is gone: removing it from state", d.Id())
+    d.SetId("")
     return nil
 }
 
+// resourceS3KeyImport imports an S3 key by an ID of the form
+// <platform>/<project>/<name>.
+func resourceS3KeyImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "project", "name")
+    if err != nil {
+        return nil, err
+    }
+
+    d.Set("platform", values[0])
+    d.Set("project", values[1])
+    d.Set("name", values[2])
+    d.SetId(fmt.Sprintf("%s-%s", values[1], values[2]))
+    return []*schema.ResourceData{d}, nil
+}
+
 func resourceS3KeyDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     name := d.Get("name").(string)
     projectID := d.Get("project").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/projects/%s/key/", platform, projectID)
-    body := KeyCreateBody {
-        Name:   name,
-    }
-
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", buf)
+    err := config.API.Keys.Delete(ctx, platform, projectID, name)
 
     if err != nil {
         return fmt.Errorf("Unable to delete s3 key: on %s %v", platform, err)
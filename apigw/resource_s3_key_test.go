@@ -0,0 +1,102 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_s3_key", &resource.Sweeper{
+        Name:	"apigw_s3_key",
+        F:	testSweepS3Keys,
+    })
+}
+
+func testSweepS3Keys(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    projects, err := config.API.Projects.List(context.Background(), platform)
+    if err != nil {
+        return fmt.Errorf("Unable to list projects on %s: %v", platform, err)
+    }
+
+    for _, project := range projects {
+        keys, err := config.API.Keys.List(context.Background(), platform, project.ID.String())
+        if err != nil {
+            return fmt.Errorf("Unable to retrive keys of project %s on %s: %v", project.ID, platform, err)
+        }
+
+        for _, key := range keys.Private {
+            if !sweepable(key.Name) {
+                continue
+            }
+            log.Printf("[INFO] Sweeping apigw_s3_key %s of project %s", key.Name, project.ID)
+            if err := config.API.Keys.Delete(context.Background(), platform, project.ID.String(), key.Name); err != nil {
+                log.Printf("[ERROR] Unable to delete s3 key %s on %s: %v", key.Name, platform, err)
+            }
+        }
+    }
+    return nil
+}
+
+func TestAccS3Key_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckS3KeyDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccS3KeyConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_s3_key.test", "name", name),
+                    resource.TestCheckResourceAttrSet("apigw_s3_key.test", "access_key"),
+                    resource.TestCheckResourceAttrSet("apigw_s3_key.test", "secret_key"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_s3_key.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_s3_key.test", "platform", "project", "name"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckS3KeyDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_s3_key",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            keys, err := api.Keys.List(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.Attributes["project"])
+            if err != nil {
+                return false, err
+            }
+            for _, key := range keys.Private {
+                if key.Name == rs.Primary.Attributes["name"] {
+                    return true, nil
+                }
+            }
+            return false, nil
+        })(s)
+}
+
+// testAccS3KeyConfig is an S3 key, available to other resources as
+// apigw_s3_key.test.
+func testAccS3KeyConfig(name string) string {
+    return fmt.Sprintf(`
+resource "apigw_s3_key" "test" {
+  name = %q
+}
+`, name)
+}
@@ -1,22 +1,13 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type SecurityGroupRuleCreateBody struct {
-    Direction	string	`json:"direction,omitempty"`
-    Protocol	string	`json:"protocol,omitempty"`
-    RemoteIPPrefix	string	`json:"remote_ip_prefix,omitempty"`
-    PortRangeMin	int	`json:"port_range_min,omitempty"`
-    PortRangeMax	int	`json:"port_range_max,omitempty"`
-    Project	string	`json:"project"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceSecurityGroupRule() *schema.Resource {
     return &schema.Resource{
@@ -24,6 +15,10 @@ func resourceSecurityGroupRule() *schema.Resource {
         Read:   resourceSecurityGroupRuleRead,
         Delete: resourceSecurityGroupRuleDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: resourceSecurityGroupRuleImport,
+        },
+
         Schema: map[string]*schema.Schema{
             "platform": {
                 Type:		schema.TypeString,
@@ -43,33 +38,41 @@ func resourceSecurityGroupRule() *schema.Resource {
                 ForceNew:	true,
             },
 
+            // The gateway defaults the rule fields which are not set, e.g.
+            // direction to ingress. They are computed, so that the defaults
+            // read back, also by an import, do not replace the rule.
             "direction": {
                 Type:		schema.TypeString,
                 Optional:	true,
+                Computed:	true,
                 ForceNew:	true,
             },
 
             "protocol": {
                 Type:		schema.TypeString,
                 Optional:	true,
+                Computed:	true,
                 ForceNew:	true,
             },
 
             "remote_ip_prefix": {
                 Type:		schema.TypeString,
                 Optional:	true,
+                Computed:	true,
                 ForceNew:	true,
             },
 
             "port_range_min": {
                 Type:		schema.TypeInt,
                 Optional:	true,
+                Computed:	true,
                 ForceNew:	true,
             },
 
             "port_range_max": {
                 Type:		schema.TypeInt,
                 Optional:	true,
+                Computed:	true,
                 ForceNew:	true,
             },
 
@@ -83,6 +86,8 @@ func resourceSecurityGroupRule() *schema.Resource {
 
 func resourceSecurityGroupRuleCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     platform := d.Get("platform").(string)
     projectID := d.Get("project").(string)
     securityGroupID := d.Get("security_group").(string)
@@ -96,20 +101,17 @@ func resourceSecurityGroupRuleCreate(d *schema.ResourceData, meta interface{}) e
     } else if port_range_max == 0 && port_range_min != 0 {
         port_range_max = port_range_min
     }
-    resourcePath := fmt.Sprintf("api/v4/%s/security_groups/%s/", platform, securityGroupID)
 
-    body := SecurityGroupRuleCreateBody {
+    opts := client.SecurityGroupRuleCreateOpts {
         Direction:		direction,
         Protocol:		protocol,
         RemoteIPPrefix:		remote_ip_prefix,
         PortRangeMin:		port_range_min,
         PortRangeMax:		port_range_max,
-        Project:	projectID,
+        Project:		projectID,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    _, err := config.doNormalRequest(platform, resourcePath, "PATCH", buf)
+    err := config.API.SecurityGroups.AddRule(ctx, platform, securityGroupID, opts)
 
     if err != nil {
         return fmt.Errorf(
@@ -125,67 +127,71 @@ func resourceSecurityGroupRuleCreate(d *schema.ResourceData, meta interface{}) e
 
 func resourceSecurityGroupRuleRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     platform := d.Get("platform").(string)
     projectID := d.Get("project").(string)
     securityGroupID := d.Get("security_group").(string)
-    resourcePath := fmt.Sprintf(
-        "api/v4/%s/security_groups/?project=%s&sg=%s",
-        platform,
-        projectID,
-        securityGroupID,
-    )
-
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    sg, err := config.API.SecurityGroups.Get(ctx, platform, projectID, securityGroupID)
 
     if err != nil {
-        return fmt.Errorf(
-            "Unable to retrieve security group %s on %s: %v", securityGroupID, platform, err)
+        return checkDeleted(d, err, fmt.Sprintf(
+            "Unable to retrieve security group %s on %s", securityGroupID, platform))
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    for i := range sg.Rules {
+        sg_rule := &sg.Rules[i]
+        // The rule is looked up by its attributes until it has an ID.
+        if d.Id() != "" && sg_rule.ID.String() == d.Id() ||
+                d.Id() == "" && foundSecurityGroupRule(sg_rule, d) {
+            securityGroupRuleID := sg_rule.ID.String()
+            log.Printf("[DEBUG] Retrieved apigw_security_group_rule %s", securityGroupRuleID)
+            d.SetId(securityGroupRuleID)
+            d.Set("direction", sg_rule.Direction)
+            d.Set("protocol", sg_rule.Protocol)
+            d.Set("remote_ip_prefix", sg_rule.RemoteIPPrefix)
+            d.Set("ethertype", sg_rule.Ethertype)
+            if sg_rule.PortRangeMin != nil {
+                d.Set("port_range_min", *sg_rule.PortRangeMin)
+            }
+            if sg_rule.PortRangeMax != nil {
+                d.Set("port_range_max", *sg_rule.PortRangeMax)
+            }
+            return nil
+        }
+    }
 
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve security group json data: %v", err)
+    if d.IsNewResource() {
+        return fmt.Errorf("Unable to retrieve security group rule from %s on %s", securityGroupID, platform)
     }
+    log.Printf("[WARN] Security group rule %s is gone: removing it from state", d.Id())
+    d.SetId("")
+    return nil
+}
 
-    if security_group_rules, ok := data["security_group_rules"].([]interface{}); ok {
-        for _, security_group_rule := range security_group_rules {
-            sg_rule := security_group_rule.(map[string]interface{})
-            if foundSecurityGroupRule(sg_rule, d) {
-                securityGroupRuleID := sg_rule["id"].(string)
-                log.Printf("[DEBUG] Retrieved apigw_security_group_rule %s", securityGroupRuleID)
-                d.SetId(securityGroupRuleID)
-                d.Set("direction", sg_rule["direction"].(string))
-                d.Set("protocol", sg_rule["protocol"].(string))
-                d.Set("remote_ip_prefix", sg_rule["remote_ip_prefix"].(string))
-                d.Set("ethertype", sg_rule["ethertype"].(string))
-                if port_range_min, ok := sg_rule["port_range_min"].(float64); ok {
-                    d.Set("port_range_min", int(port_range_min))
-                }
-                if port_range_max, ok := sg_rule["port_range_max"].(float64); ok {
-                    d.Set("port_range_max", int(port_range_max))
-                }
-                return nil
-            }
-        }
+// resourceSecurityGroupRuleImport imports a security group rule by an ID of
+// the form <platform>/<project>/<security_group>/<rule>.
+func resourceSecurityGroupRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "project", "security_group", "rule")
+    if err != nil {
+        return nil, err
     }
-    return fmt.Errorf("Unable to retrieve security group rule from %s on %s", securityGroupID, platform)
+
+    d.Set("platform", values[0])
+    d.Set("project", values[1])
+    d.Set("security_group", values[2])
+    d.SetId(values[3])
+    return []*schema.ResourceData{d}, nil
 }
 
 func resourceSecurityGroupRuleDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     platform := d.Get("platform").(string)
     projectID := d.Get("project").(string)
     securityGroupRuleID := d.Id()
-    resourcePath := fmt.Sprintf(
-        "api/v4/%s/security_group_rules/%s/?project=%s",
-        platform,
-        securityGroupRuleID,
-        projectID,
-    )
-
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.SecurityGroups.DeleteRule(ctx, platform, projectID, securityGroupRuleID)
 
     if err != nil {
         return fmt.Errorf(
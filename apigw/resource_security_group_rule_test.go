@@ -0,0 +1,100 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func TestAccSecurityGroupRule_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckSecurityGroupRuleDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccSecurityGroupRuleConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_security_group_rule.ssh", "direction", "ingress"),
+                    resource.TestCheckResourceAttr("apigw_security_group_rule.ssh", "port_range_min", "22"),
+                    resource.TestCheckResourceAttr("apigw_security_group_rule.ssh", "port_range_max", "22"),
+                    resource.TestCheckResourceAttr("apigw_security_group_rule.ssh", "remote_ip_prefix", "0.0.0.0/0"),
+                    resource.TestCheckResourceAttr("apigw_security_group_rule.web", "port_range_min", "8000"),
+                    resource.TestCheckResourceAttr("apigw_security_group_rule.web", "port_range_max", "8080"),
+                    resource.TestCheckResourceAttr(
+                        "apigw_security_group_rule.web", "remote_ip_prefix", "192.0.2.0/24"),
+                    testAccCheckSecurityGroupRulesDiffer(
+                        "apigw_security_group_rule.ssh", "apigw_security_group_rule.web"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_security_group_rule.web",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID(
+                    "apigw_security_group_rule.web", "platform", "project", "security_group", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+// testAccCheckSecurityGroupRulesDiffer verifies that two rules of the same
+// security group were matched to different rules of the gateway.
+func testAccCheckSecurityGroupRulesDiffer(a, b string) resource.TestCheckFunc {
+    return func(s *terraform.State) error {
+        resources := s.RootModule().Resources
+        if resources[a].Primary.ID == resources[b].Primary.ID {
+            return fmt.Errorf("%s and %s are both rule %s", a, b, resources[a].Primary.ID)
+        }
+        return nil
+    }
+}
+
+func testAccCheckSecurityGroupRuleDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_security_group_rule",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            sg, err := api.SecurityGroups.Get(context.Background(), 
+                rs.Primary.Attributes["platform"],
+                rs.Primary.Attributes["project"],
+                rs.Primary.Attributes["security_group"])
+            if found, err := testAccFound(err); !found {
+                return false, err
+            }
+            for _, rule := range sg.Rules {
+                if rule.ID.String() == rs.Primary.ID {
+                    return true, nil
+                }
+            }
+            return false, nil
+        })(s)
+}
+
+// testAccSecurityGroupRuleConfig adds two rules to the security group of a
+// VCS, which are told apart by their attributes only.
+func testAccSecurityGroupRuleConfig(name string) string {
+    return testAccVCSConfig(name) + `
+data "apigw_security_group" "test" {
+  vcs = apigw_vcs.test.id
+}
+
+resource "apigw_security_group_rule" "ssh" {
+  security_group = data.apigw_security_group.test.id
+  port_range_min = 22
+}
+
+resource "apigw_security_group_rule" "web" {
+  security_group   = data.apigw_security_group.test.id
+  protocol         = "tcp"
+  port_range_min   = 8000
+  port_range_max   = 8080
+  remote_ip_prefix = "192.0.2.0/24"
+}
+`
+}
@@ -1,22 +1,15 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
+    "context"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type VCSCreateBody struct {
-    Desc	string	`json:"desc,omitempty"`
-    Name	string	`json:"name"`
-    Project	string	`json:"project"`
-    Solution	string	`json:"solution"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceVCS() *schema.Resource {
     return &schema.Resource{
@@ -24,6 +17,10 @@ func resourceVCS() *schema.Resource {
         Read:   resourceVCSRead,
         Delete: resourceVCSDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformIDExtraProperty,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(30 * time.Minute),
             Delete: schema.DefaultTimeout(30 * time.Minute),
@@ -132,60 +129,62 @@ func resourceVCS() *schema.Resource {
 
 func resourceVCSCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     desc := d.Get("desc").(string)
     extra_property := d.Get("extra_property").(map[string]interface{})
-    headers := make(map[string]string)
+    extraProperties := make(map[string]string)
     for key, value := range extra_property {
-        header := fmt.Sprintf("x-extra-property-%s", key)
-        headers[header] = fmt.Sprintf("%v", value)
+        extraProperties[key] = fmt.Sprintf("%v", value)
     }
-    headers["x-extra-property-volume-size"] = "0"
-    headers["x-extra-property-volume-type"] = "hdd"
+    extraProperties["volume-size"] = "0"
+    extraProperties["volume-type"] = "hdd"
 
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
     solution := d.Get("solution").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/", platform)
 
-    body := VCSCreateBody {
-        Desc:		desc,
-        Name:		name,
-        Project:	project,
-        Solution:	solution,
+    opts := client.SiteCreateOpts {
+        Desc:			desc,
+        Name:			name,
+        Project:		project,
+        Solution:		solution,
+        ExtraProperties:	extraProperties,
+        IdempotencyKey:		client.NewIdempotencyKey(),
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doCreateSiteRequest(platform, resourcePath, "POST", buf, headers)
-
-    if err != nil {
-        return fmt.Errorf("Error creating apigw_vcs %s on %s: %v", name, platform, err)
+    orphans := newOrphanLookup(ctx, config, "apigw_vcs", name, platform,
+        func(ctx context.Context) ([]string, error) {
+            sites, err := config.API.Sites.List(ctx, platform, client.SiteListOpts{Project: project, Name: name})
+            var ids []string
+            for _, site := range sites {
+                if site.Name == name {
+                    ids = append(ids, site.ID.String())
+                }
+            }
+            return ids, err
+        })
+
+    var siteID string
+    site, err := config.API.Sites.Create(ctx, platform, opts)
+    if err == nil {
+        siteID = site.ID.String()
+    } else {
+        siteID, err = orphans.adopt(ctx, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
     if err != nil {
-        return err
+        return fmt.Errorf("Error creating apigw_vcs %s on %s: %v", name, platform, err)
     }
 
-    siteID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", siteID))
-
-    newPath := fmt.Sprintf("%s%d/", resourcePath, siteID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"Initializing", "Queueing"},
-        Target:     []string{"Ready"},
-        Refresh:    siteStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
+    d.SetId(siteID)
 
-    _, err = stateConf.WaitForState()
+    _, err = siteWaiter(ctx, config, platform, siteID).Wait(
+        []string{"Initializing", "Queueing"}, []string{"Ready"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
-            "Error waiting for apigw_vcs %d to become Ready: %v", siteID, err)
+            "Error waiting for apigw_vcs %s to become Ready: %v", siteID, err)
     }
 
     d.Set("name", name)
@@ -197,54 +196,45 @@ func resourceVCSCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceVCSRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     siteID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/%s/", platform, siteID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    site, err := config.API.Sites.Get(ctx, platform, siteID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve vcs %s on %s: %v", siteID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve vcs json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve vcs %s on %s", siteID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_vcs %s", d.Id())
-    d.Set("create_time", data["create_time"])
-    d.Set("desc", data["desc"])
-    d.Set("public_ip", data["ext_net"])
-    serversInfo := flattenSiteServersInfo(data["servers"].([]interface{}))
+    d.Set("name", site.Name)
+    d.Set("project", site.Project.String())
+    d.Set("solution", site.Solution.String())
+    d.Set("create_time", site.CreateTime)
+    d.Set("desc", site.Desc)
+    d.Set("public_ip", site.ExtNet)
+    serversInfo := flattenSiteServersInfo(site.Servers)
     d.Set("servers", serversInfo)
-    d.Set("status", data["status"])
-    d.Set("status_reason", data["status_reason"])
-    d.Set("user", data["user"])
+    d.Set("status", site.Status)
+    d.Set("status_reason", site.StatusReason)
+    d.Set("user", site.User)
     return nil
 }
 
 func resourceVCSDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     siteID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/%s/", platform, siteID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Sites.Delete(ctx, platform, siteID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete VCS %s: on %s %v", siteID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"Deleting"},
-        Target:     []string{"Deleted"},
-        Refresh:    siteStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = siteWaiter(ctx, config, platform, siteID).Wait(
+        []string{"Deleting"}, []string{"Deleted"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_vcs %s to become Deleted: %v", siteID, err)
@@ -1,22 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type ServerImageCreateBody struct {
-    Desc	string	`json:"desc,omitempty"`
-    Name	string	`json:"name"`
-    OS		string	`json:"os"`
-    OSVersion	string	`json:"os_version"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceVCSImage() *schema.Resource {
     return &schema.Resource{
@@ -24,6 +16,10 @@ func resourceVCSImage() *schema.Resource {
         Read:   resourceVCSImageRead,
         Delete: resourceVCSImageDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: resourceVCSImageImport,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(30 * time.Minute),
             Delete: schema.DefaultTimeout(15 * time.Minute),
@@ -103,24 +99,23 @@ func resourceVCSImage() *schema.Resource {
 
 func resourceVCSImageCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     desc := d.Get("desc").(string)
     name := d.Get("name").(string)
     os := d.Get("os").(string)
     osVersion := d.Get("os_version").(string)
     platform := d.Get("platform").(string)
     server := d.Get("server").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/images/%s/save/", platform, server)
 
-    body := ServerImageCreateBody {
+    opts := client.ImageSaveOpts {
         Desc:		desc,
         Name:		name,
         OS:		os,
         OSVersion:	osVersion,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "PUT", buf)
+    image, err := config.API.Images.Save(ctx, platform, server, opts)
 
     if err != nil {
         return fmt.Errorf(
@@ -132,28 +127,13 @@ func resourceVCSImageCreate(d *schema.ResourceData, meta interface{}) error {
         )
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    imageID := image.ID.String()
+    d.SetId(imageID)
 
+    _, err = imageWaiter(ctx, config, platform, imageID).Wait(
+        []string{"QUEUED", "SAVING"}, []string{"ACTIVE"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
-        return err
-    }
-
-    imageID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", imageID))
-
-    newPath := fmt.Sprintf("api/v4/%s/images/%d/", platform, imageID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"QUEUED", "SAVING"},
-        Target:     []string{"ACTIVE", "ERROR"},
-        Refresh:    imageStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
-    if err != nil {
-        return fmt.Errorf("Error waiting for apigw_vcs_image %d to become ACTIVE: %v", imageID, err)
+        return fmt.Errorf("Error waiting for apigw_vcs_image %s to become ACTIVE: %v", imageID, err)
     }
 
     d.Set("desc", desc)
@@ -167,53 +147,60 @@ func resourceVCSImageCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceVCSImageRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     imageID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/images/%s/", platform, imageID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    image, err := config.API.Images.Get(ctx, platform, imageID)
 
     if err != nil {
-        return fmt.Errorf(
-            "Unable to retrieve image %s on %s: %v", imageID, platform, err)
+        return checkDeleted(d, err, fmt.Sprintf(
+            "Unable to retrieve image %s on %s", imageID, platform))
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    log.Printf("[DEBUG] Retrieved apigw_vcs_image %s", imageID)
+    d.Set("name", image.Name)
+    d.Set("desc", image.Desc)
+    d.Set("os", image.OS)
+    d.Set("os_version", image.OSVersion)
+    d.Set("create_time", image.CreateTime)
+    d.Set("is_enabled", image.IsEnabled)
+    d.Set("is_public", image.IsPublic)
+    d.Set("ref_img_id", image.RefImgID)
+    d.Set("status", image.Status)
+    d.Set("status_reason", image.StatusReason)
+    return nil
+}
 
+// resourceVCSImageImport imports an image by an ID of the form
+// <platform>/<server>/<id>, as the image does not tell which server it was
+// saved from.
+func resourceVCSImageImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "server", "id")
     if err != nil {
-        return fmt.Errorf("Unable to retrieve image json data: %s", err)
+        return nil, err
     }
 
-    log.Printf("[DEBUG] Retrieved apigw_vcs_image %s", imageID)
-    d.Set("create_time", data["create_time"].(string))
-    d.Set("is_enabled", data["is_enabled"].(bool))
-    d.Set("is_public", data["is_public"].(bool))
-    d.Set("ref_img_id", data["ref_img_id"].(string))
-    d.Set("status", data["status"].(string))
-    d.Set("status_reason", data["status_reason"].(string))
-    return nil
+    d.Set("platform", values[0])
+    d.Set("server", values[1])
+    d.SetId(values[2])
+    return []*schema.ResourceData{d}, nil
 }
 
 func resourceVCSImageDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     imageID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/images/%s/", platform, imageID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Images.Delete(ctx, platform, imageID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete VCS snapshot image %s on %s: %v", imageID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"ACTIVE"},
-        Target:     []string{"DELETED", "ERROR"},
-        Refresh:    imageStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = imageWaiter(ctx, config, platform, imageID).Wait(
+        []string{"ACTIVE"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_vcs_image to become DELETED: %v", err)
@@ -0,0 +1,59 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+// Images cannot be listed, leaked images are not swept.
+
+func TestAccVCSImage_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckVCSImageDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVCSImageConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_vcs_image.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_vcs_image.test", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttrSet("apigw_vcs_image.test", "ref_img_id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_vcs_image.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_vcs_image.test", "platform", "server", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckVCSImageDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_vcs_image",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Images.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+func testAccVCSImageConfig(name string) string {
+    return testAccVCSConfig(name) + fmt.Sprintf(`
+resource "apigw_vcs_image" "test" {
+  name       = %q
+  server     = apigw_vcs.test.servers.0.id
+  os         = "ubuntu"
+  os_version = "18.04"
+}
+`, name)
+}
@@ -0,0 +1,94 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    // VCS, WAF and containers are all sites, swept together.
+    resource.AddTestSweepers("apigw_vcs", &resource.Sweeper{
+        Name:	"apigw_vcs",
+        F:	testSweepSites,
+    })
+}
+
+func testSweepSites(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    sites, err := config.API.Sites.List(context.Background(), platform, client.SiteListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list sites on %s: %v", platform, err)
+    }
+
+    for _, site := range sites {
+        if !sweepable(site.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping site %s", site.ID)
+        if err := config.API.Sites.Delete(context.Background(), platform, site.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete site %s on %s: %v", site.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccVCS_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckSiteDestroy("apigw_vcs"),
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVCSConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_vcs.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_vcs.test", "status", "Ready"),
+                    resource.TestCheckResourceAttr("apigw_vcs.test", "servers.#", "1"),
+                    resource.TestCheckResourceAttrSet("apigw_vcs.test", "servers.0.id"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_vcs.test", "solution", "data.apigw_solution.vcs", "id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_vcs.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_vcs.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+// testAccCheckSiteDestroy returns a CheckDestroy function for the sites of
+// type kind.
+func testAccCheckSiteDestroy(kind string) resource.TestCheckFunc {
+    return testAccCheckDestroy(kind,
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Sites.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })
+}
+
+// testAccVCSConfig is a VCS named name, whose server other resources can use
+// as apigw_vcs.test.servers.0.id.
+func testAccVCSConfig(name string) string {
+    return testAccSolutionConfig("vcs", "APIGW_TEST_VCS_SOLUTION") + fmt.Sprintf(`
+resource "apigw_vcs" "test" {
+  name     = %q
+  solution = data.apigw_solution.vcs.id
+}
+`, name)
+}
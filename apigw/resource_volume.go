@@ -1,29 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
-
-type VolumeCreateBody struct {
-    Desc		string	`json:"desc,omitempty"`
-    Name		string	`json:"name"`
-    Project		string	`json:"project,omitempty"`
-    Size		int	`json:"size,omitempty"`
-    SrcSnapshot		string	`json:"src_snapshot,omitempty"`
-    VolumeType		string	`json:"volume_type,omitempty"`
-}
 
-type VolumeUpdateBody struct {
-    Status	string	`json:"status"`
-    Size	int	`json:"size"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceVolume() *schema.Resource {
     return &schema.Resource{
@@ -32,6 +17,10 @@ func resourceVolume() *schema.Resource {
         Update:	resourceVolumeUpdate,
         Delete: resourceVolumeDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(10 * time.Minute),
             Update: schema.DefaultTimeout(10 * time.Minute),
@@ -160,6 +149,8 @@ func resourceVolume() *schema.Resource {
 
 func resourceVolumeCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     desc := d.Get("desc").(string)
@@ -167,51 +158,33 @@ func resourceVolumeCreate(d *schema.ResourceData, meta interface{}) error {
     srcSnapshot := d.Get("src_snapshot").(string)
     size := d.Get("size").(int)
     project := d.Get("project").(string)
-    body := VolumeCreateBody {
+    opts := client.VolumeCreateOpts {
         Desc:		desc,
         Name:		name,
         VolumeType:	volumeType,
     }
 
     if srcSnapshot != "" {
-        body.SrcSnapshot = srcSnapshot
+        opts.SrcSnapshot = srcSnapshot
     } else {
-        body.Project = project
-        body.Size = size
+        opts.Project = project
+        opts.Size = size
     }
 
-    resourcePath := fmt.Sprintf("api/v4/%s/volumes/", platform)
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    volume, err := config.API.Volumes.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_volume %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    volumeID := volume.ID.String()
+    d.SetId(volumeID)
 
-    if err != nil {
-        return err
-    }
-
-    volumeID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", volumeID))
-
-    newPath := fmt.Sprintf("%s/%d/", resourcePath, volumeID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"CREATING", "DOWNLOADING",},
-        Target:     []string{"AVAILABLE", "ERROR"},
-        Refresh:    volumeStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = volumeWaiter(ctx, config, platform, volumeID).Wait(
+        []string{"CREATING", "DOWNLOADING"}, []string{"AVAILABLE"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
-            "Error waiting for apigw_volume %d to become AVAILABLE: %v", volumeID, err)
+            "Error waiting for apigw_volume %s to become AVAILABLE: %v", volumeID, err)
     }
 
     d.Set("name", name)
@@ -221,84 +194,67 @@ func resourceVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceVolumeRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     volumeID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/volumes/%s/", platform, volumeID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve volume %s on %s: %v", volumeID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    volume, err := config.API.Volumes.Get(ctx, platform, volumeID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve volume json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve volume %s on %s", volumeID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_volume %s", d.Id())
-    if host, ok := data["attached_host"].(interface{}); ok {
-        hostInfo := flattenVolumeHostInfo(host)
+    d.Set("name", volume.Name)
+    if volume.AttachedHost != nil {
+        hostInfo := flattenVolumeHostInfo(volume.AttachedHost)
         d.Set("attached_host", hostInfo)
     } else {
         var emptyHost interface{}
         d.Set("attached_host", emptyHost)
     }
 
-    d.Set("create_time", data["create_time"])
-    d.Set("desc", data["desc"])
-    d.Set("is_attached", data["is_attached"])
-    d.Set("is_bootable", data["is_bootable"])
-    d.Set("is_public", data["is_public"])
-    d.Set("mountpoint", data["mountpoint"])
-    projectInfo := data["project"].(map[string]interface{})
-    projectID := int(projectInfo["id"].(float64))
-    d.Set("project", fmt.Sprintf("%d", projectID))
-    d.Set("size", data["size"])
-    snapshotInfo := flattenVolumeSnapshotsInfo(data["snapshot_list"].([]interface{}))
+    d.Set("create_time", volume.CreateTime)
+    d.Set("desc", volume.Desc)
+    d.Set("is_attached", volume.IsAttached)
+    d.Set("is_bootable", volume.IsBootable)
+    d.Set("is_public", volume.IsPublic)
+    d.Set("mountpoint", volume.Mountpoint)
+    d.Set("project", volume.Project.String())
+    d.Set("size", volume.Size)
+    snapshotInfo := flattenVolumeSnapshotsInfo(volume.SnapshotList)
     d.Set("snapshot_list", snapshotInfo)
-    d.Set("src_snapshot", data["src_snapshot"])
-    d.Set("status", data["status"])
-    d.Set("status_reason", data["status_reason"])
-    d.Set("user", data["user"].(map[string]interface{}))
-    d.Set("volume_type", data["volume_type"])
-    d.Set("volume_uuid", data["volume_uuid"])
+    d.Set("src_snapshot", volume.SrcSnapshot.String())
+    d.Set("status", volume.Status)
+    d.Set("status_reason", volume.StatusReason)
+    d.Set("user", volume.User)
+    d.Set("volume_type", volume.VolumeType)
+    d.Set("volume_uuid", volume.VolumeUUID)
     return nil
 }
 
 func resourceVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutUpdate)
+    defer cancel()
     size_change := d.HasChange("size")
     if size_change {
         _, newSize := d.GetChange("size")
-        body := VolumeUpdateBody {
+        opts := client.VolumeActionOpts {
             Size:	newSize.(int),
             Status:	"extend",
         }
 
         volumeID := d.Id()
         platform := d.Get("platform").(string)
-        resourcePath := fmt.Sprintf("api/v4/%s/volumes/%s/action/", platform, volumeID)
-
-        buf := new(bytes.Buffer)
-        json.NewEncoder(buf).Encode(body)
-        _, err := config.doNormalRequest(platform, resourcePath, "PUT", buf)
+        err := config.API.Volumes.Action(ctx, platform, volumeID, opts)
 
         if err != nil {
             return fmt.Errorf("Error resizing apigw_volume %s on %v: %s", volumeID, platform, err)
         }
 
-        newPath := fmt.Sprintf("api/v4/%s/volumes/%s/", platform, volumeID)
-        stateConf := &resource.StateChangeConf{
-            Pending:    []string{"EXTENDING",},
-            Target:     []string{"AVAILABLE", "ERROR", "IN-USE"},
-            Refresh:    volumeStateRefreshFunc(config, platform, newPath),
-            Timeout:    d.Timeout(schema.TimeoutUpdate),
-            Delay:      10 * time.Second,
-        }
-
-        _, err = stateConf.WaitForState()
+        _, err = volumeWaiter(ctx, config, platform, volumeID).Wait(
+            []string{"EXTENDING"}, []string{"AVAILABLE", "IN-USE"}, d.Timeout(schema.TimeoutUpdate))
         if err != nil {
             return fmt.Errorf(
                 "Error waiting for apigw_volume %s to become AVAILABLE: %v", volumeID, err)
@@ -310,25 +266,18 @@ func resourceVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceVolumeDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     platform := d.Get("platform").(string)
     volumeID := d.Id()
-    resourcePath := fmt.Sprintf("api/v4/%s/volumes/%s/", platform, volumeID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Volumes.Delete(ctx, platform, volumeID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete volume %s: on %s %v", volumeID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING",},
-        Target:     []string{"DELETED", "ERROR"},
-        Refresh:    volumeStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-        MinTimeout: 3 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = volumeWaiter(ctx, config, platform, volumeID).Wait(
+        []string{"DELETING"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_volume %s to become DELETED: %v", volumeID, err)
@@ -338,3 +287,17 @@ func resourceVolumeDelete(d *schema.ResourceData, meta interface{}) error {
 
     return nil
 }
+
+// volumeErrors translates the gateway errors of volume requests.
+type volumeErrors struct{}
+
+func (volumeErrors) Error409(e ErrUnexpectedResponseCode) error {
+    if e.Method == "DELETE" {
+        e.Info = fmt.Sprintf("The volume is still attached to a server or has snapshots, detach it and delete its snapshots first: %s", e.Message())
+    }
+    return ErrDefault409{e}
+}
+
+func init() {
+    registerErrorTranslator("volumes", volumeErrors{})
+}
@@ -1,21 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type VolumeAttachmentCreateBody struct {
-    Mountpoint	string	`json:"mountpoint,omitempty"`
-    Server	string	`json:"server,omitempty"`
-    Status	string	`json:"status"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceVolumeAttachment() *schema.Resource {
     return &schema.Resource{
@@ -23,6 +16,10 @@ func resourceVolumeAttachment() *schema.Resource {
         Read:   resourceVolumeAttachmentRead,
         Delete: resourceVolumeAttachmentDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: resourceVolumeAttachmentImport,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(10 * time.Minute),
             Delete: schema.DefaultTimeout(10 * time.Minute),
@@ -58,21 +55,20 @@ func resourceVolumeAttachment() *schema.Resource {
 
 func resourceVolumeAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     mountpoint := d.Get("mountpoint").(string)
     platform := d.Get("platform").(string)
     server := d.Get("server").(string)
     volume := d.Get("volume").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/volumes/%s/action/", platform, volume)
 
-    body := VolumeAttachmentCreateBody {
+    opts := client.VolumeActionOpts {
         Mountpoint:	mountpoint,
         Server:		server,
         Status:		"attach",
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    _, err := config.doNormalRequest(platform, resourcePath, "PUT", buf)
+    err := config.API.Volumes.Action(ctx, platform, volume, opts)
 
     if err != nil {
         return fmt.Errorf(
@@ -86,16 +82,8 @@ func resourceVolumeAttachmentCreate(d *schema.ResourceData, meta interface{}) er
 
     d.SetId(fmt.Sprintf("%s/%s", server, volume))
 
-    newPath := fmt.Sprintf("api/v4/%s/volumes/%s/", platform, volume)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"ATTACHING"},
-        Target:     []string{"IN-USE", "ERROR"},
-        Refresh:    volumeStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = volumeWaiter(ctx, config, platform, volume).Wait(
+        []string{"ATTACHING"}, []string{"IN-USE"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
             "Error waiting for apigw_volume_attachment with volume %s and server %s to become IN-USE: %v",
@@ -113,44 +101,59 @@ func resourceVolumeAttachmentCreate(d *schema.ResourceData, meta interface{}) er
 
 func resourceVolumeAttachmentRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     volumeID := d.Get("volume").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/volumes/%s/", platform, volumeID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    volume, err := config.API.Volumes.Get(ctx, platform, volumeID)
 
     if err != nil {
-        return fmt.Errorf(
-            "Unable to retrieve volume %s on %s: %s", volumeID, platform, err)
+        return checkDeleted(d, err, fmt.Sprintf(
+            "Unable to retrieve volume %s on %s", volumeID, platform))
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve volume json data: %v", err)
+    if !volume.IsAttached && !d.IsNewResource() {
+        log.Printf("[WARN] Volume attachment %s is gone: removing it from state", d.Id())
+        d.SetId("")
+        return nil
     }
 
     log.Printf("[DEBUG] Retrieved apigw_volume_attachment by volume %s", volumeID)
-    mountpoints := data["mountpoint"].([]interface{})
-    d.Set("mountpoint", mountpoints[0].(string))
+    if len(volume.Mountpoint) > 0 {
+        d.Set("mountpoint", volume.Mountpoint[0])
+    }
     return nil
 }
 
+// resourceVolumeAttachmentImport imports a volume attachment by an ID of the
+// form <platform>/<server>/<volume>.
+func resourceVolumeAttachmentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "server", "volume")
+    if err != nil {
+        return nil, err
+    }
+
+    d.Set("platform", values[0])
+    d.Set("server", values[1])
+    d.Set("volume", values[2])
+    d.SetId(fmt.Sprintf("%s/%s", values[1], values[2]))
+    return []*schema.ResourceData{d}, nil
+}
+
 func resourceVolumeAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     serverID := d.Get("server").(string)
     volumeID := d.Get("volume").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/volumes/%s/action/", platform, volumeID)
 
-    body := VolumeAttachmentCreateBody {
+    opts := client.VolumeActionOpts {
         Server:	serverID,
         Status:	"detach",
     }
-    
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    _, err := config.doNormalRequest(platform, resourcePath, "PUT", buf)
+
+    err := config.API.Volumes.Action(ctx, platform, volumeID, opts)
 
     if err != nil {
         return fmt.Errorf(
@@ -162,16 +165,8 @@ func resourceVolumeAttachmentDelete(d *schema.ResourceData, meta interface{}) er
         )
     }
 
-    newPath := fmt.Sprintf("api/v4/%s/volumes/%s/", platform, volumeID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DETACHING"},
-        Target:     []string{"AVAILABLE", "ERROR"},
-        Refresh:    volumeStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = volumeWaiter(ctx, config, platform, volumeID).Wait(
+        []string{"DETACHING"}, []string{"AVAILABLE"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_volume_attachment to become AVAILABLE: %v", err)
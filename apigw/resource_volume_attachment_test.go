@@ -0,0 +1,62 @@
+package apigw
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func TestAccVolumeAttachment_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_VCS_SOLUTION") },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckVolumeAttachmentDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVolumeAttachmentConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_volume_attachment.test", "server", "apigw_vcs.test", "servers.0.id"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_volume_attachment.test", "volume", "apigw_volume.test", "id"),
+                    resource.TestCheckResourceAttrSet("apigw_volume_attachment.test", "mountpoint"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_volume_attachment.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID(
+                    "apigw_volume_attachment.test", "platform", "server", "volume"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+// testAccCheckVolumeAttachmentDestroy verifies that the attached volumes are
+// detached or gone.
+func testAccCheckVolumeAttachmentDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_volume_attachment",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            volume, err := api.Volumes.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.Attributes["volume"])
+            if found, err := testAccFound(err); !found {
+                return false, err
+            }
+            return volume.IsAttached, nil
+        })(s)
+}
+
+func testAccVolumeAttachmentConfig(name string) string {
+    return testAccVCSConfig(name) + testAccVolumeConfig(name, 1) + `
+resource "apigw_volume_attachment" "test" {
+  server = apigw_vcs.test.servers.0.id
+  volume = apigw_volume.test.id
+}
+`
+}
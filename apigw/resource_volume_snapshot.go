@@ -1,21 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type VolumeSnapshotCreateBody struct {
-    Desc		string	`json:"desc,omitempty"`
-    Name		string	`json:"name"`
-    Volume		string	`json:"volume"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceVolumeSnapshot() *schema.Resource {
     return &schema.Resource{
@@ -23,6 +16,10 @@ func resourceVolumeSnapshot() *schema.Resource {
         Read:   resourceVolumeSnapshotRead,
         Delete: resourceVolumeSnapshotDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(10 * time.Minute),
             Delete: schema.DefaultTimeout(10 * time.Minute),
@@ -94,48 +91,32 @@ func resourceVolumeSnapshot() *schema.Resource {
 
 func resourceVolumeSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     desc := d.Get("desc").(string)
     volume := d.Get("volume").(string)
-    body := VolumeSnapshotCreateBody {
+    opts := client.SnapshotCreateOpts {
         Desc:	desc,
         Name:	name,
         Volume:	volume,
     }
 
-    resourcePath := fmt.Sprintf("api/v4/%s/snapshots/", platform)
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    snapshot, err := config.API.Snapshots.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_snapshot %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    snapshotID := snapshot.ID.String()
+    d.SetId(snapshotID)
 
-    if err != nil {
-        return err
-    }
-
-    snapshotID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", snapshotID))
-
-    newPath := fmt.Sprintf("%s/%d/", resourcePath, snapshotID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"CREATING",},
-        Target:     []string{"AVAILABLE", "ERROR"},
-        Refresh:    snapshotStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = snapshotWaiter(ctx, config, platform, snapshotID).Wait(
+        []string{"CREATING"}, []string{"AVAILABLE"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
-            "Error waiting for apigw_volume_snapshot %d to become AVAILABLE: %v", snapshotID, err)
+            "Error waiting for apigw_volume_snapshot %s to become AVAILABLE: %v", snapshotID, err)
     }
 
     d.Set("name", name)
@@ -147,61 +128,44 @@ func resourceVolumeSnapshotCreate(d *schema.ResourceData, meta interface{}) erro
 
 func resourceVolumeSnapshotRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     snapshotID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/snapshots/%s/", platform, snapshotID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve volume snapshot %s on %s: %v", snapshotID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    snapshot, err := config.API.Snapshots.Get(ctx, platform, snapshotID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve volume snapshot json data: %s", err)
+        return checkDeleted(d, err, fmt.Sprintf(
+            "Unable to retrieve volume snapshot %s on %s", snapshotID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_volume_snapshot %s", d.Id())
-    if hostList, ok := data["attached_host"].([]interface{}); ok {
-        hostInfo := flattenVolumeHostInfo(hostList)
-        d.Set("attached_host", hostInfo)
-    } else {
-        var emptyList []interface{}
-         d.Set("attached_host", emptyList)
-    }
-
-    d.Set("create_time", data["create_time"])
-    d.Set("restore_volume", data["restore_volume"])
-    d.Set("snapshot_uuid", data["snapshot_uuid"])
-    d.Set("status", data["status"])
-    d.Set("status_reason", data["status_reason"])
-    d.Set("user", data["user"].(map[string]interface{}))
+    d.Set("name", snapshot.Name)
+    d.Set("desc", snapshot.Desc)
+    d.Set("volume", snapshot.Volume.String())
+    d.Set("create_time", snapshot.CreateTime)
+    d.Set("restore_volume", snapshot.RestoreVolume.String())
+    d.Set("snapshot_uuid", snapshot.SnapshotUUID)
+    d.Set("status", snapshot.Status)
+    d.Set("status_reason", snapshot.StatusReason)
+    d.Set("user", snapshot.User)
     return nil
 }
 
 func resourceVolumeSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     platform := d.Get("platform").(string)
     snapshotID := d.Id()
-    resourcePath := fmt.Sprintf("api/v4/%s/snapshots/%s/", platform, snapshotID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Snapshots.Delete(ctx, platform, snapshotID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete volume snapshot %s: on %s %v", snapshotID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING",},
-        Target:     []string{"DELETED", "ERROR"},
-        Refresh:    snapshotStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-        MinTimeout: 3 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = snapshotWaiter(ctx, config, platform, snapshotID).Wait(
+        []string{"DELETING"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_volume_snapshot %s to become DELETED: %v", snapshotID, err)
@@ -0,0 +1,89 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_volume_snapshot", &resource.Sweeper{
+        Name:	"apigw_volume_snapshot",
+        F:	testSweepVolumeSnapshots,
+    })
+}
+
+func testSweepVolumeSnapshots(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    snapshots, err := config.API.Snapshots.List(context.Background(), platform, client.SnapshotListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list snapshots on %s: %v", platform, err)
+    }
+
+    for _, snapshot := range snapshots {
+        if !sweepable(snapshot.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_volume_snapshot %s", snapshot.ID)
+        if err := config.API.Snapshots.Delete(context.Background(), platform, snapshot.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete snapshot %s on %s: %v", snapshot.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccVolumeSnapshot_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckVolumeSnapshotDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVolumeSnapshotConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_volume_snapshot.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_volume_snapshot.test", "status", "AVAILABLE"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_volume_snapshot.test", "volume", "apigw_volume.test", "id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_volume_snapshot.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_volume_snapshot.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckVolumeSnapshotDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_volume_snapshot",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Snapshots.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccVolumeSnapshotConfig is a snapshot of a volume, both named name.
+func testAccVolumeSnapshotConfig(name string) string {
+    return testAccVolumeConfig(name, 1) + fmt.Sprintf(`
+resource "apigw_volume_snapshot" "test" {
+  name   = %q
+  desc   = "acceptance test"
+  volume = apigw_volume.test.id
+}
+`, name)
+}
@@ -0,0 +1,97 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_volume", &resource.Sweeper{
+        Name:		"apigw_volume",
+        Dependencies:	[]string{"apigw_volume_snapshot", "apigw_vcs"},
+        F:		testSweepVolumes,
+    })
+}
+
+func testSweepVolumes(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    volumes, err := config.API.Volumes.List(context.Background(), platform, client.VolumeListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list volumes on %s: %v", platform, err)
+    }
+
+    for _, volume := range volumes {
+        if !sweepable(volume.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_volume %s", volume.ID)
+        if err := config.API.Volumes.Delete(context.Background(), platform, volume.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete volume %s on %s: %v", volume.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccVolume_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckVolumeDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVolumeConfig(name, 1),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_volume.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_volume.test", "size", "1"),
+                    resource.TestCheckResourceAttr("apigw_volume.test", "status", "AVAILABLE"),
+                ),
+            },
+            {
+                Config: testAccVolumeConfig(name, 2),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_volume.test", "size", "2"),
+                    resource.TestCheckResourceAttr("apigw_volume.test", "status", "AVAILABLE"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_volume.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_volume.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckVolumeDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_volume",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.Volumes.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccVolumeConfig is a volume of size GB named name, available to other
+// resources as apigw_volume.test.
+func testAccVolumeConfig(name string, size int) string {
+    return fmt.Sprintf(`
+resource "apigw_volume" "test" {
+  name    = %q
+  project = %q
+  size    = %d
+}
+`, name, testAccProject(), size)
+}
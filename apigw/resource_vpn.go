@@ -1,22 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type VPNCreateBody struct {
-    IKEPolicy		string	`json:"ike_policy"`
-    IPSecPolicy		string	`json:"ipsec_policy"`
-    Name		string	`json:"name"`
-    PrivateNetwork	string	`json:"private_network"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceVPN() *schema.Resource {
     return &schema.Resource{
@@ -24,6 +16,10 @@ func resourceVPN() *schema.Resource {
         Read:   resourceVPNRead,
         Delete: resourceVPNDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformID,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Delete: schema.DefaultTimeout(10 * time.Minute),
         },
@@ -88,37 +84,28 @@ func resourceVPN() *schema.Resource {
 
 func resourceVPNCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     ikePolicy := d.Get("ike_policy").(string)
     ipsecPolicy := d.Get("ipsec_policy").(string)
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     privateNetwork := d.Get("private_network").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/", platform)
 
-    body := VPNCreateBody {
+    opts := client.VPNServiceCreateOpts {
         IKEPolicy:	ikePolicy,
         IPSecPolicy:	ipsecPolicy,
         Name:		name,
         PrivateNetwork:	privateNetwork,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    vpn, err := config.API.VPNServices.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_vpn %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return err
-    }
-
-    vpnID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", vpnID))
+    d.SetId(vpn.ID.String())
     d.Set("ike_policy", ikePolicy)
     d.Set("ipsec_policy", ipsecPolicy)
     d.Set("name", name)
@@ -129,50 +116,42 @@ func resourceVPNCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceVPNRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     vpnID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/%s/", platform, vpnID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    vpn, err := config.API.VPNServices.Get(ctx, platform, vpnID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve vpn %s on %s: %v", vpnID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve vpn json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve vpn %s on %s", vpnID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_vpn %s", d.Id())
-    d.Set("local_address", data["local_address"])
-    d.Set("local_cidr", data["local_cidr"])
-    d.Set("status", data["status"])
-    d.Set("user", data["user"])
+    d.Set("name", vpn.Name)
+    d.Set("ike_policy", vpn.IKEPolicy.String())
+    d.Set("ipsec_policy", vpn.IPSecPolicy.String())
+    d.Set("private_network", vpn.PrivateNetwork.String())
+    d.Set("local_address", vpn.LocalAddress)
+    d.Set("local_cidr", vpn.LocalCIDR)
+    d.Set("status", vpn.Status)
+    d.Set("user", vpn.User)
     return nil
 }
 
 func resourceVPNDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     vpnID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/%s/", platform, vpnID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.VPNServices.Delete(ctx, platform, vpnID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete vpn %s: on %s %v", vpnID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED"},
-        Refresh:    vpnStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = vpnWaiter(ctx, config, platform, vpnID).Wait(
+        []string{"DELETING"}, []string{"DELETED"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf(
             "Error waiting for apigw_vpn %s to become DELETED: %v", vpnID, err)
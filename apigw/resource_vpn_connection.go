@@ -1,27 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type ConnectionCreateBody struct {
-    DPDAction	string		`json:"dpd_action,omitempty"`
-    DPDInterval	int		`json:"dpd_interval,omitempty"`
-    DPDTimeout	int		`json:"dpd_timeout,omitempty"`
-    Initiator	string		`json:"initiator,omitempty"`
-    MTU		int		`json:"mtu,omitempty"`
-    PeerAddress	string		`json:"peer_address"`
-    PeerCIDRs	[]string	`json:"peer_cidrs"`
-    PeerID	string		`json:"peer_id,omitempty"`
-    PSK		string		`json:"psk,omitempty"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceVPNConnection() *schema.Resource {
     return &schema.Resource{
@@ -29,6 +16,10 @@ func resourceVPNConnection() *schema.Resource {
         Read:   resourceVPNConnectionRead,
         Delete: resourceVPNConnectionDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: resourceVPNConnectionImport,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Delete: schema.DefaultTimeout(10 * time.Minute),
         },
@@ -95,10 +86,13 @@ func resourceVPNConnection() *schema.Resource {
                 ForceNew:       true,
             },
 
+            // The gateway does not return the pre-shared key, so it is empty
+            // after an import and must not replace the connection.
             "psk": {
-                Type:           schema.TypeString,
-                Required:       true,
-                ForceNew:       true,
+                Type:             schema.TypeString,
+                Required:         true,
+                ForceNew:         true,
+                DiffSuppressFunc: suppressImportedSecret,
             },
 
             "status": {
@@ -117,6 +111,8 @@ func resourceVPNConnection() *schema.Resource {
 
 func resourceVPNConnectionCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     dpdAction := d.Get("dpd_action").(string)
     dpdInterval := d.Get("dpd_interval").(int)
     dpdTimeout := d.Get("dpd_timeout").(int)
@@ -133,9 +129,8 @@ func resourceVPNConnectionCreate(d *schema.ResourceData, meta interface{}) error
     platform := d.Get("platform").(string)
     psk := d.Get("psk").(string)
     vpn := d.Get("vpn").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/%s/connection/", platform, vpn)
 
-    body := ConnectionCreateBody {
+    opts := client.VPNConnectionCreateOpts {
         DPDAction:	dpdAction,
         DPDInterval:	dpdInterval,
         DPDTimeout:	dpdTimeout,
@@ -147,9 +142,7 @@ func resourceVPNConnectionCreate(d *schema.ResourceData, meta interface{}) error
         PSK:		psk,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    _, err := config.doNormalRequest(platform, resourcePath, "POST", buf)
+    err := config.API.VPNServices.CreateConnection(ctx, platform, vpn, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_vpn_connection %s on %s: %v", vpn, platform, err)
@@ -157,16 +150,8 @@ func resourceVPNConnectionCreate(d *schema.ResourceData, meta interface{}) error
 
     d.SetId(fmt.Sprintf("%s-connection", vpn))
 
-    newPath := fmt.Sprintf("api/v4/%s/vpn_services/%s/", platform, vpn)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"CREATING"},
-        Target:     []string{"CREATED"},
-        Refresh:    connectionStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = connectionWaiter(ctx, config, platform, vpn).Wait(
+        []string{"NONE"}, []string{"CREATED"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
             "Error waiting for apigw_vpn_connection %s to become Ready: %v", vpn, err)
@@ -180,59 +165,64 @@ func resourceVPNConnectionCreate(d *schema.ResourceData, meta interface{}) error
 
 func resourceVPNConnectionRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     vpnID := d.Get("vpn").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/%s/", platform, vpnID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
+    vpn, err := config.API.VPNServices.Get(ctx, platform, vpnID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve vpn %s on %s: %v", vpnID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve vpn json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve vpn %s on %s", vpnID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_vpn_connection %s", d.Id())
-    if connection, ok := data["connection"].(map[string]interface{}); ok {
-        d.Set("dpd_action", connection["dpd_action"])
-        d.Set("dpd_interval", connection["dpd_interval"])
-        d.Set("dpd_timeout", connection["dpd_timeout"])
-        d.Set("initiator", connection["initiator"])
-        d.Set("mtu", connection["mtu"])
-        d.Set("peer_id", connection["peer_id"])
-        d.Set("peer_cidrs", connection["peer_cidrs"])
-        d.Set("status", connection["status"])
-    } else {
+    if connection := vpn.Connection; connection != nil {
+        d.Set("dpd_action", connection.DPDAction)
+        d.Set("dpd_interval", connection.DPDInterval)
+        d.Set("dpd_timeout", connection.DPDTimeout)
+        d.Set("initiator", connection.Initiator)
+        d.Set("mtu", connection.MTU)
+        d.Set("peer_address", connection.PeerAddress)
+        d.Set("peer_id", connection.PeerID)
+        d.Set("peer_cidrs", connection.PeerCIDRs)
+        d.Set("status", connection.Status)
+    } else if d.IsNewResource() {
         return fmt.Errorf("VPN connection not found")
+    } else {
+        log.Printf("[WARN] VPN connection %s is gone: removing it from state", d.Id())
+        d.SetId("")
     }
     return nil
 }
 
+// resourceVPNConnectionImport imports a VPN connection by an ID of the form
+// <platform>/<vpn>.
+func resourceVPNConnectionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    values, err := parseImportID(d.Id(), "platform", "vpn")
+    if err != nil {
+        return nil, err
+    }
+
+    d.Set("platform", values[0])
+    d.Set("vpn", values[1])
+    d.SetId(fmt.Sprintf("%s-connection", values[1]))
+    return []*schema.ResourceData{d}, nil
+}
+
 func resourceVPNConnectionDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     vpnID := d.Get("vpn").(string)
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/vpn_services/%s/connection/", platform, vpnID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.VPNServices.DeleteConnection(ctx, platform, vpnID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete vpn connection %s: on %s %v", vpnID, platform, err)
     }
 
-    newPath := fmt.Sprintf("api/v4/%s/vpn_services/%s/", platform, vpnID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"DELETING"},
-        Target:     []string{"DELETED"},
-        Refresh:    connectionStateRefreshForDeletedFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = connectionWaiter(ctx, config, platform, vpnID).Wait(
+        []string{"CREATED"}, []string{"NONE"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf(
             "Error waiting for apigw_vpn_connection %s to become DELETED: %v", vpnID, err)
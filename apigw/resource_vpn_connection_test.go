@@ -0,0 +1,65 @@
+package apigw
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func TestAccVPNConnection_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckVPNConnectionDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVPNConnectionConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_vpn_connection.test", "peer_address", "198.51.100.1"),
+                    resource.TestCheckResourceAttr("apigw_vpn_connection.test", "peer_cidrs.#", "1"),
+                    resource.TestCheckResourceAttr("apigw_vpn_connection.test", "peer_cidrs.0", "192.168.0.0/24"),
+                    resource.TestCheckResourceAttr("apigw_vpn_connection.test", "mtu", "1500"),
+                    resource.TestCheckResourceAttrSet("apigw_vpn_connection.test", "status"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_vpn_connection.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_vpn_connection.test", "platform", "vpn"),
+                ImportStateVerify:	true,
+                // The pre-shared key is not returned by the gateway.
+                ImportStateVerifyIgnore: []string{"psk"},
+            },
+        },
+    })
+}
+
+// testAccCheckVPNConnectionDestroy verifies that the VPN services have no
+// connection or are gone.
+func testAccCheckVPNConnectionDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_vpn_connection",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            vpn, err := api.VPNServices.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.Attributes["vpn"])
+            if found, err := testAccFound(err); !found {
+                return false, err
+            }
+            return vpn.Connection != nil, nil
+        })(s)
+}
+
+func testAccVPNConnectionConfig(name string) string {
+    return testAccVPNConfig(name) + `
+resource "apigw_vpn_connection" "test" {
+  vpn          = apigw_vpn.test.id
+  peer_address = "198.51.100.1"
+  peer_cidrs   = ["192.168.0.0/24"]
+  psk          = "acceptance-test"
+}
+`
+}
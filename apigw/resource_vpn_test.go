@@ -0,0 +1,102 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func init() {
+    resource.AddTestSweepers("apigw_vpn", &resource.Sweeper{
+        Name:	"apigw_vpn",
+        F:	testSweepVPNs,
+    })
+}
+
+func testSweepVPNs(platform string) error {
+    config, err := sharedConfig()
+    if err != nil {
+        return err
+    }
+
+    vpns, err := config.API.VPNServices.List(context.Background(), platform, client.VPNServiceListOpts{})
+    if err != nil {
+        return fmt.Errorf("Unable to list vpn on %s: %v", platform, err)
+    }
+
+    for _, vpn := range vpns {
+        if !sweepable(vpn.Name) {
+            continue
+        }
+        log.Printf("[INFO] Sweeping apigw_vpn %s", vpn.ID)
+        if vpn.Connection != nil {
+            if err := config.API.VPNServices.DeleteConnection(context.Background(), platform, vpn.ID.String()); err != nil {
+                log.Printf("[ERROR] Unable to delete connection of vpn %s on %s: %v", vpn.ID, platform, err)
+            }
+        }
+        if err := config.API.VPNServices.Delete(context.Background(), platform, vpn.ID.String()); err != nil {
+            log.Printf("[ERROR] Unable to delete vpn %s on %s: %v", vpn.ID, platform, err)
+        }
+    }
+    return nil
+}
+
+func TestAccVPN_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheck(t) },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckVPNDestroy,
+        Steps: []resource.TestStep{
+            {
+                Config: testAccVPNConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_vpn.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_vpn.test", "status", "ACTIVE"),
+                    resource.TestCheckResourceAttrSet("apigw_vpn.test", "local_address"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_vpn.test", "ike_policy", "apigw_ike_policy.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_vpn.test", "ipsec_policy", "apigw_ipsec_policy.test", "id"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_vpn.test", "private_network", "apigw_network.test", "id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_vpn.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_vpn.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccCheckVPNDestroy(s *terraform.State) error {
+    return testAccCheckDestroy("apigw_vpn",
+        func(api *client.Client, rs *terraform.ResourceState) (bool, error) {
+            _, err := api.VPNServices.Get(context.Background(), rs.Primary.Attributes["platform"], rs.Primary.ID)
+            return testAccFound(err)
+        })(s)
+}
+
+// testAccVPNConfig is a VPN service of a network, available to other
+// resources as apigw_vpn.test.
+func testAccVPNConfig(name string) string {
+    return testAccNetworkConfig(name) + testAccIKEPolicyConfig(name) + testAccIPSecPolicyConfig(name) +
+        fmt.Sprintf(`
+resource "apigw_vpn" "test" {
+  name            = %q
+  ike_policy      = apigw_ike_policy.test.id
+  ipsec_policy    = apigw_ipsec_policy.test.id
+  private_network = apigw_network.test.id
+}
+`, name)
+}
@@ -1,22 +1,14 @@
 package apigw
 
 import (
-    "bytes"
-    "encoding/json"
     "fmt"
     "log"
     "time"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-)
 
-type WAFCreateBody struct {
-    Desc	string	`json:"desc,omitempty"`
-    Name	string	`json:"name"`
-    Project	string	`json:"project"`
-    Solution	string	`json:"solution"`
-}
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
 
 func resourceWAF() *schema.Resource {
     return &schema.Resource{
@@ -24,6 +16,10 @@ func resourceWAF() *schema.Resource {
         Read:   resourceWAFRead,
         Delete: resourceWAFDelete,
 
+        Importer: &schema.ResourceImporter{
+            State: importStatePlatformIDExtraProperty,
+        },
+
         Timeouts: &schema.ResourceTimeout{
             Create: schema.DefaultTimeout(30 * time.Minute),
             Delete: schema.DefaultTimeout(30 * time.Minute),
@@ -132,58 +128,42 @@ func resourceWAF() *schema.Resource {
 
 func resourceWAFCreate(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutCreate)
+    defer cancel()
     desc := d.Get("desc").(string)
     extra_property := d.Get("extra_property").(map[string]interface{})
-    headers := make(map[string]string)
+    extraProperties := make(map[string]string)
     for key, value := range extra_property {
-        header := fmt.Sprintf("x-extra-property-%s", key)
-        headers[header] = fmt.Sprintf("%v", value)
+        extraProperties[key] = fmt.Sprintf("%v", value)
     }
 
     name := d.Get("name").(string)
     platform := d.Get("platform").(string)
     project := d.Get("project").(string)
     solution := d.Get("solution").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/", platform)
 
-    body := WAFCreateBody {
-        Desc:		desc,
-        Name:		name,
-        Project:	project,
-        Solution:	solution,
+    opts := client.SiteCreateOpts {
+        Desc:			desc,
+        Name:			name,
+        Project:		project,
+        Solution:		solution,
+        ExtraProperties:	extraProperties,
     }
 
-    buf := new(bytes.Buffer)
-    json.NewEncoder(buf).Encode(body)
-    response, err := config.doCreateSiteRequest(platform, resourcePath, "POST", buf, headers)
+    site, err := config.API.Sites.Create(ctx, platform, opts)
 
     if err != nil {
         return fmt.Errorf("Error creating apigw_waf %s on %s: %v", name, platform, err)
     }
 
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    siteID := site.ID.String()
+    d.SetId(siteID)
 
-    if err != nil {
-        return err
-    }
-
-    siteID := int(data["id"].(float64))
-    d.SetId(fmt.Sprintf("%d", siteID))
-
-    newPath := fmt.Sprintf("%s%d/", resourcePath, siteID)
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"Initializing", "Queueing"},
-        Target:     []string{"Ready"},
-        Refresh:    siteStateRefreshFunc(config, platform, newPath),
-        Timeout:    d.Timeout(schema.TimeoutCreate),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = siteWaiter(ctx, config, platform, siteID).Wait(
+        []string{"Initializing", "Queueing"}, []string{"Ready"}, d.Timeout(schema.TimeoutCreate))
     if err != nil {
         return fmt.Errorf(
-            "Error waiting for apigw_waf %d to become Ready: %v", siteID, err)
+            "Error waiting for apigw_waf %s to become Ready: %v", siteID, err)
     }
 
     d.Set("name", name)
@@ -195,54 +175,45 @@ func resourceWAFCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceWAFRead(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutRead)
+    defer cancel()
     siteID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/%s/", platform, siteID)
-    response, err := config.doNormalRequest(platform, resourcePath, "GET", nil)
-
-    if err != nil {
-        return fmt.Errorf("Unable to retrieve waf %s on %s: %v", siteID, platform, err)
-    }
-
-    var data map[string]interface{}
-    err = json.Unmarshal([]byte(response), &data)
+    site, err := config.API.Sites.Get(ctx, platform, siteID)
 
     if err != nil {
-        return fmt.Errorf("Unable to retrieve waf json data: %v", err)
+        return checkDeleted(d, err, fmt.Sprintf("Unable to retrieve waf %s on %s", siteID, platform))
     }
 
     log.Printf("[DEBUG] Retrieved apigw_waf %s", d.Id())
-    d.Set("create_time", data["create_time"])
-    d.Set("desc", data["desc"])
-    d.Set("public_ip", data["ext_net"])
-    serversInfo := flattenSiteServersInfo(data["servers"].([]interface{}))
+    d.Set("name", site.Name)
+    d.Set("project", site.Project.String())
+    d.Set("solution", site.Solution.String())
+    d.Set("create_time", site.CreateTime)
+    d.Set("desc", site.Desc)
+    d.Set("public_ip", site.ExtNet)
+    serversInfo := flattenSiteServersInfo(site.Servers)
     d.Set("servers", serversInfo)
-    d.Set("status", data["status"])
-    d.Set("status_reason", data["status_reason"])
-    d.Set("user", data["user"])
+    d.Set("status", site.Status)
+    d.Set("status_reason", site.StatusReason)
+    d.Set("user", site.User)
     return nil
 }
 
 func resourceWAFDelete(d *schema.ResourceData, meta interface{}) error {
     config := meta.(*PConfig)
+    ctx, cancel := config.operationContext(d, schema.TimeoutDelete)
+    defer cancel()
     siteID := d.Id()
     platform := d.Get("platform").(string)
-    resourcePath := fmt.Sprintf("api/v4/%s/sites/%s/", platform, siteID)
-    _, err := config.doNormalRequest(platform, resourcePath, "DELETE", nil)
+    err := config.API.Sites.Delete(ctx, platform, siteID)
 
     if err != nil {
         return fmt.Errorf("Unable to delete WAF %s: on %s %v", siteID, platform, err)
     }
 
-    stateConf := &resource.StateChangeConf{
-        Pending:    []string{"Deleting"},
-        Target:     []string{"Deleted"},
-        Refresh:    siteStateRefreshForDeletedFunc(config, platform, resourcePath),
-        Timeout:    d.Timeout(schema.TimeoutDelete),
-        Delay:      10 * time.Second,
-    }
-
-    _, err = stateConf.WaitForState()
+    _, err = siteWaiter(ctx, config, platform, siteID).Wait(
+        []string{"Deleting"}, []string{"Deleted"}, d.Timeout(schema.TimeoutDelete))
     if err != nil {
         return fmt.Errorf( 
             "Error waiting for apigw_waf %s to become Deleted: %v", siteID, err)
@@ -0,0 +1,44 @@
+package apigw
+
+import (
+    "fmt"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccWAF_basic(t *testing.T) {
+    name := testAccName(t)
+
+    resource.Test(t, resource.TestCase{
+        PreCheck:	func() { testAccPreCheckEnv(t, "APIGW_TEST_WAF_SOLUTION") },
+        Providers:	testAccProviders,
+        CheckDestroy:	testAccCheckSiteDestroy("apigw_waf"),
+        Steps: []resource.TestStep{
+            {
+                Config: testAccWAFConfig(name),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("apigw_waf.test", "name", name),
+                    resource.TestCheckResourceAttr("apigw_waf.test", "status", "Ready"),
+                    resource.TestCheckResourceAttrPair(
+                        "apigw_waf.test", "solution", "data.apigw_solution.waf", "id"),
+                ),
+            },
+            {
+                ResourceName:		"apigw_waf.test",
+                ImportState:		true,
+                ImportStateIdFunc:	testAccImportStateID("apigw_waf.test", "platform", "id"),
+                ImportStateVerify:	true,
+            },
+        },
+    })
+}
+
+func testAccWAFConfig(name string) string {
+    return testAccSolutionConfig("waf", "APIGW_TEST_WAF_SOLUTION") + fmt.Sprintf(`
+resource "apigw_waf" "test" {
+  name     = %q
+  solution = data.apigw_solution.waf.id
+}
+`, name)
+}
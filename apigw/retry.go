@@ -0,0 +1,139 @@
+package apigw
+
+import (
+    "errors"
+    "log"
+    "math/rand"
+    "net"
+    "net/http"
+    "strconv"
+    "time"
+)
+
+// isIdempotentMethod reports whether repeating a request with the given method
+// has the same effect on the gateway as sending it once.
+func isIdempotentMethod(method string) bool {
+    switch method {
+    case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+        return true
+    }
+
+    return false
+}
+
+// isIdempotentRequest reports whether a request may be repeated, either
+// because of its method or, with RetryIdempotentCreates, because it carries
+// an Idempotency-Key header, which a gateway deduplicating on it answers
+// with the object created by the first POST.
+func (pc *ProviderClient) isIdempotentRequest(method string, headers map[string]string) bool {
+    return isIdempotentMethod(method) || pc.RetryIdempotentCreates && headers["Idempotency-Key"] != ""
+}
+
+// isRetryableStatus reports whether a response code signals a transient
+// condition of the gateway or of the services behind it.
+func isRetryableStatus(code int) bool {
+    switch code {
+    case http.StatusTooManyRequests,
+            http.StatusBadGateway,
+            http.StatusServiceUnavailable,
+            http.StatusGatewayTimeout:
+        return true
+    }
+
+    return false
+}
+
+// requestNotSent reports whether a transport error happened before any byte
+// of the request was written, in which case even a POST is safe to repeat.
+func requestNotSent(err error) bool {
+    var dnsErr *net.DNSError
+    if errors.As(err, &dnsErr) {
+        return true
+    }
+
+    var opErr *net.OpError
+    if errors.As(err, &opErr) {
+        return opErr.Op == "dial"
+    }
+
+    return false
+}
+
+// shouldRetry decides whether an attempt which ended with resp and err may be
+// repeated. Idempotent requests are retried on transport errors and transient
+// response codes; other requests only when they never left the client.
+func shouldRetry(idempotent bool, resp *http.Response, sent bool, err error) bool {
+    if err == nil {
+        return false
+    }
+
+    if !sent {
+        return true
+    }
+
+    if !idempotent {
+        return false
+    }
+
+    if resp == nil {
+        return true
+    }
+
+    return isRetryableStatus(resp.StatusCode)
+}
+
+// retryWait returns how long to sleep before the next attempt. It grows
+// exponentially from RetryWaitMin up to RetryWaitMax with random jitter, and
+// never undercuts a Retry-After header sent by the gateway. A longer
+// Retry-After is cut to RetryWaitMax, so that the gateway cannot stall a run.
+func (pc *ProviderClient) retryWait(attempt int, resp *http.Response) time.Duration {
+    wait := pc.RetryWaitMin
+    for i := 0; i < attempt && wait < pc.RetryWaitMax; i++ {
+        wait *= 2
+    }
+
+    if wait > pc.RetryWaitMax {
+        wait = pc.RetryWaitMax
+    }
+
+    if wait > 0 {
+        wait = wait / 2 + time.Duration(rand.Int63n(int64(wait / 2) + 1))
+    }
+
+    if resp != nil {
+        if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok && retryAfter > wait {
+            wait = retryAfter
+            if wait > pc.RetryWaitMax {
+                log.Printf("[DEBUG] Waiting %s instead of the Retry-After of %s", pc.RetryWaitMax, wait)
+                wait = pc.RetryWaitMax
+            }
+        }
+    }
+
+    return wait
+}
+
+// parseRetryAfter understands both forms of the Retry-After header, a number
+// of seconds and an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+    if value == "" {
+        return 0, false
+    }
+
+    if seconds, err := strconv.Atoi(value); err == nil {
+        if seconds < 0 {
+            return 0, false
+        }
+        return time.Duration(seconds) * time.Second, true
+    }
+
+    if date, err := http.ParseTime(value); err == nil {
+        wait := time.Until(date)
+        if wait < 0 {
+            wait = 0
+        }
+        return wait, true
+    }
+
+    return 0, false
+}
@@ -0,0 +1,129 @@
+package apigw
+
+import (
+    "context"
+    "errors"
+    "net"
+    "net/http"
+    "testing"
+    "time"
+)
+
+func TestShouldRetry(t *testing.T) {
+    response := func(code int) *http.Response {
+        return &http.Response{StatusCode: code}
+    }
+    failed := errors.New("failed")
+    dial := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+    reset := &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}
+
+    cases := []struct {
+        name		string
+        idempotent	bool
+        resp		*http.Response
+        sent		bool
+        err		error
+        retry		bool
+    }{
+        {"success", true, response(http.StatusOK), true, nil, false},
+        {"not sent", false, nil, false, dial, true},
+        {"not sent idempotent", true, nil, false, &net.DNSError{Err: "no such host"}, true},
+        {"transport error", true, nil, true, reset, true},
+        {"transport error of a POST", false, nil, true, reset, false},
+        {"timeout", true, nil, true, context.DeadlineExceeded, true},
+        {"429", true, response(http.StatusTooManyRequests), true, failed, true},
+        {"502", true, response(http.StatusBadGateway), true, failed, true},
+        {"503", true, response(http.StatusServiceUnavailable), true, failed, true},
+        {"504", true, response(http.StatusGatewayTimeout), true, failed, true},
+        {"503 of a POST", false, response(http.StatusServiceUnavailable), true, failed, false},
+        {"400", true, response(http.StatusBadRequest), true, failed, false},
+        {"404", true, response(http.StatusNotFound), true, failed, false},
+        {"409", true, response(http.StatusConflict), true, failed, false},
+        {"500", true, response(http.StatusInternalServerError), true, failed, false},
+    }
+
+    for _, c := range cases {
+        if retry := shouldRetry(c.idempotent, c.resp, c.sent, c.err); retry != c.retry {
+            t.Errorf("%s: shouldRetry = %t, want %t", c.name, retry, c.retry)
+        }
+    }
+}
+
+func TestRequestNotSent(t *testing.T) {
+    cases := []struct {
+        name		string
+        err		error
+        notSent		bool
+    }{
+        {"dns", &net.DNSError{Err: "no such host"}, true},
+        {"dial", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+        {"read", &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, false},
+        {"timeout", context.DeadlineExceeded, false},
+    }
+
+    for _, c := range cases {
+        if notSent := requestNotSent(c.err); notSent != c.notSent {
+            t.Errorf("%s: requestNotSent = %t, want %t", c.name, notSent, c.notSent)
+        }
+    }
+}
+
+func TestParseRetryAfter(t *testing.T) {
+    cases := []struct {
+        name		string
+        value		string
+        min		time.Duration
+        max		time.Duration
+        ok		bool
+    }{
+        {"empty", "", 0, 0, false},
+        {"seconds", "120", 2 * time.Minute, 2 * time.Minute, true},
+        {"zero", "0", 0, 0, true},
+        {"negative", "-1", 0, 0, false},
+        {"date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 55 * time.Second, time.Minute, true},
+        {"past date", "Wed, 21 Oct 2015 07:28:00 GMT", 0, 0, true},
+        {"garbage", "soon", 0, 0, false},
+    }
+
+    for _, c := range cases {
+        wait, ok := parseRetryAfter(c.value)
+        if ok != c.ok || wait < c.min || wait > c.max {
+            t.Errorf("%s: parseRetryAfter(%q) = %s, %t, want %s-%s, %t",
+                c.name, c.value, wait, ok, c.min, c.max, c.ok)
+        }
+    }
+}
+
+func TestRetryWait(t *testing.T) {
+    pc := &ProviderClient{
+        RetryWaitMin:	time.Second,
+        RetryWaitMax:	30 * time.Second,
+    }
+    retryAfter := func(value string) *http.Response {
+        return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+    }
+
+    cases := []struct {
+        name		string
+        attempt		int
+        resp		*http.Response
+        min		time.Duration
+        max		time.Duration
+    }{
+        {"first", 0, nil, 500 * time.Millisecond, time.Second},
+        {"second", 1, nil, time.Second, 2 * time.Second},
+        {"backoff capped", 10, nil, 15 * time.Second, 30 * time.Second},
+        {"without Retry-After", 0, &http.Response{Header: http.Header{}}, 500 * time.Millisecond, time.Second},
+        {"Retry-After", 0, retryAfter("10"), 10 * time.Second, 10 * time.Second},
+        {"shorter Retry-After", 1, retryAfter("0"), time.Second, 2 * time.Second},
+        {"Retry-After capped", 0, retryAfter("3600"), 30 * time.Second, 30 * time.Second},
+        {"Retry-After date capped", 0, retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)),
+            30 * time.Second, 30 * time.Second},
+    }
+
+    for _, c := range cases {
+        if wait := pc.retryWait(c.attempt, c.resp); wait < c.min || wait > c.max {
+            t.Errorf("%s: retryWait = %s, want %s-%s", c.name, wait, c.min, c.max)
+        }
+    }
+}
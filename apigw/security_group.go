@@ -2,24 +2,25 @@ package apigw
 
 import (
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 
-func flattenSecurityGroupRulesInfo(v []interface{}) []interface{} {
+func flattenSecurityGroupRulesInfo(v []client.SecurityGroupRule) []interface{} {
     security_group_rules := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         security_group_rule := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        security_group_rule["id"] = info["id"].(string)
-        security_group_rule["direction"] = info["direction"].(string)
-        security_group_rule["ethertype"] = info["ethertype"].(string)
-        security_group_rule["remote_ip_prefix"] = info["remote_ip_prefix"].(string)
-        security_group_rule["protocol"] = info["protocol"].(string)
-        if port_range_min, ok := info["port_range_min"].(float64); ok {
-            security_group_rule["port_range_min"] = int(port_range_min)
+        security_group_rule["id"] = info.ID.String()
+        security_group_rule["direction"] = info.Direction
+        security_group_rule["ethertype"] = info.Ethertype
+        security_group_rule["remote_ip_prefix"] = info.RemoteIPPrefix
+        security_group_rule["protocol"] = info.Protocol
+        if info.PortRangeMin != nil {
+            security_group_rule["port_range_min"] = *info.PortRangeMin
         }
-        if port_range_max, ok := info["port_range_max"].(float64); ok {
-            security_group_rule["port_range_max"] = int(port_range_max)
+        if info.PortRangeMax != nil {
+            security_group_rule["port_range_max"] = *info.PortRangeMax
         }
         security_group_rules[i] = security_group_rule
     }
@@ -27,7 +28,12 @@ func flattenSecurityGroupRulesInfo(v []interface{}) []interface{} {
 }
 
 
-func foundSecurityGroupRule(v map[string]interface{}, d *schema.ResourceData) bool {
+// foundSecurityGroupRule reports whether the rule v of a security group is
+// the one configured in d, with the defaults of the gateway for unset fields.
+func foundSecurityGroupRule(v *client.SecurityGroupRule, d *schema.ResourceData) bool {
+    if v == nil {
+        return false
+    }
     direction := d.Get("direction").(string)
     protocol := d.Get("protocol").(string)
     remote_ip_prefix := d.Get("remote_ip_prefix").(string)
@@ -39,14 +45,14 @@ func foundSecurityGroupRule(v map[string]interface{}, d *schema.ResourceData) bo
         port_range_max = port_range_min
     }
     return !(
-        (direction != "" && v["direction"] != direction) ||
-        (direction == "" && v["direction"] != "ingress") ||
-        (protocol != "" && v["protocol"] != protocol) ||
-        (protocol == "" && v["protocol"] != "tcp") ||
-        (remote_ip_prefix != "" && v["remote_ip_prefix"] != remote_ip_prefix) ||
-        (remote_ip_prefix == "" && v["remote_ip_prefix"] != "0.0.0.0/0") ||
-        (port_range_min != 0 && int(v["port_range_min"].(float64)) != port_range_min) ||
-        (port_range_min == 0 && v["port_range_min"] != nil) ||
-        (port_range_max != 0 && int(v["port_range_max"].(float64)) != port_range_max) ||
-        (port_range_max == 0 && v["port_range_max"] != nil))
+        (direction != "" && v.Direction != direction) ||
+        (direction == "" && v.Direction != "ingress") ||
+        (protocol != "" && v.Protocol != protocol) ||
+        (protocol == "" && v.Protocol != "tcp") ||
+        (remote_ip_prefix != "" && v.RemoteIPPrefix != remote_ip_prefix) ||
+        (remote_ip_prefix == "" && v.RemoteIPPrefix != "0.0.0.0/0") ||
+        (port_range_min != 0 && (v.PortRangeMin == nil || *v.PortRangeMin != port_range_min)) ||
+        (port_range_min == 0 && v.PortRangeMin != nil) ||
+        (port_range_max != 0 && (v.PortRangeMax == nil || *v.PortRangeMax != port_range_max)) ||
+        (port_range_max == 0 && v.PortRangeMax != nil))
 }
@@ -0,0 +1,102 @@
+package apigw
+
+import (
+    "encoding/json"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func decodeSecurityGroupRule(t *testing.T, data string) *client.SecurityGroupRule {
+    var rule *client.SecurityGroupRule
+    if err := json.Unmarshal([]byte(data), &rule); err != nil {
+        t.Fatal(err)
+    }
+    return rule
+}
+
+func TestFlattenSecurityGroupRulesInfo(t *testing.T) {
+    rules := []client.SecurityGroupRule{
+        *decodeSecurityGroupRule(t, `{"id": 1, "direction": "ingress", "port_range_min": 22, "port_range_max": 22}`),
+        *decodeSecurityGroupRule(t, `{"id": "2", "port_range_min": null}`),
+    }
+
+    got := flattenSecurityGroupRulesInfo(rules)
+    if len(got) != 2 {
+        t.Fatalf("flattenSecurityGroupRulesInfo returned %d rules, want 2", len(got))
+    }
+    if ssh := got[0].(map[string]interface{}); ssh["id"] != "1" || ssh["port_range_min"] != 22 {
+        t.Errorf("rule = %v, want 1 from port 22", ssh)
+    }
+    if _, ok := got[1].(map[string]interface{})["port_range_min"]; ok {
+        t.Errorf("rule = %v, want no port_range_min", got[1])
+    }
+
+    d := schema.TestResourceDataRaw(t, dataSourceSecurityGroup().Schema, map[string]interface{}{})
+    if err := d.Set("security_group_rules", got); err != nil {
+        t.Errorf("Unable to set security_group_rules: %v", err)
+    }
+}
+
+func TestFoundSecurityGroupRule(t *testing.T) {
+    cases := []struct {
+        name	string
+        config	map[string]interface{}
+        rule	string
+        found	bool
+    }{
+        {"defaults", map[string]interface{}{},
+            `{"direction": "ingress", "protocol": "tcp", "remote_ip_prefix": "0.0.0.0/0"}`, true},
+        {"default direction", map[string]interface{}{},
+            `{"direction": "egress", "protocol": "tcp", "remote_ip_prefix": "0.0.0.0/0"}`, false},
+        {"port", map[string]interface{}{"port_range_min": 22},
+            `{"direction": "ingress", "protocol": "tcp", "remote_ip_prefix": "0.0.0.0/0",
+              "port_range_min": 22, "port_range_max": 22}`, true},
+        {"other port", map[string]interface{}{"port_range_min": 22},
+            `{"direction": "ingress", "protocol": "tcp", "remote_ip_prefix": "0.0.0.0/0",
+              "port_range_min": 80, "port_range_max": 80}`, false},
+        {"missing port", map[string]interface{}{"port_range_max": 22},
+            `{"direction": "ingress", "protocol": "tcp", "remote_ip_prefix": "0.0.0.0/0"}`, false},
+        {"unexpected port", map[string]interface{}{},
+            `{"direction": "ingress", "protocol": "tcp", "remote_ip_prefix": "0.0.0.0/0", "port_range_min": 1}`, false},
+        {"protocol", map[string]interface{}{"protocol": "udp", "remote_ip_prefix": "10.0.0.0/8"},
+            `{"direction": "ingress", "protocol": "udp", "remote_ip_prefix": "10.0.0.0/8"}`, true},
+        {"null", map[string]interface{}{}, `null`, false},
+        {"empty", map[string]interface{}{}, `{}`, false},
+    }
+
+    for _, c := range cases {
+        d := schema.TestResourceDataRaw(t, resourceSecurityGroupRule().Schema, c.config)
+        if got := foundSecurityGroupRule(decodeSecurityGroupRule(t, c.rule), d); got != c.found {
+            t.Errorf("%s: foundSecurityGroupRule = %v, want %v", c.name, got, c.found)
+        }
+    }
+}
+
+func FuzzFoundSecurityGroupRule(f *testing.F) {
+    f.Add([]byte(`{"direction": "ingress", "protocol": "tcp", "port_range_min": 22, "port_range_max": 22}`), 22)
+    f.Add([]byte(`{"port_range_min": null, "port_range_max": 0}`), 0)
+    f.Add([]byte(`null`), 80)
+
+    f.Fuzz(func(t *testing.T, data []byte, port int) {
+        var rule *client.SecurityGroupRule
+        if err := json.Unmarshal(data, &rule); err != nil {
+            return
+        }
+
+        d := schema.TestResourceDataRaw(t, resourceSecurityGroupRule().Schema,
+            map[string]interface{}{"port_range_min": port})
+        foundSecurityGroupRule(rule, d)
+
+        var rules []client.SecurityGroupRule
+        if rule != nil {
+            rules = append(rules, *rule)
+        }
+        sg := schema.TestResourceDataRaw(t, dataSourceSecurityGroup().Schema, map[string]interface{}{})
+        if err := sg.Set("security_group_rules", flattenSecurityGroupRulesInfo(rules)); err != nil {
+            t.Errorf("Unable to set security_group_rules of %s: %v", data, err)
+        }
+    })
+}
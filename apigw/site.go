@@ -1,154 +1,144 @@
 package apigw
 
 import (
+    "context"
     "fmt"
-    "encoding/json"
+    "log"
     "strings"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func flattenContainerPortsInfo(v []interface{}) []interface{} {
+func flattenContainerPortsInfo(v []client.ContainerPort) []interface{} {
     portsInfo := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         port := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        port["name"] = info["name"].(string)
-        port["port"] = int(info["port"].(float64))
-        port["protocol"] = info["protocol"].(string)
+        port["name"] = info.Name
+        port["port"] = info.Port
+        port["protocol"] = info.Protocol
         portsInfo[i] = port
     }
     return portsInfo
 }
 
-func flattenContainerVolumesInfo(v []interface{}) []interface{} {
+func flattenContainerVolumesInfo(v []client.ContainerVolume) []interface{} {
     volumesInfo := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         volume := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        volume["mount_path"] = info["mountPath"].(string)
-        volume["path"] = info["path"].(string)
-        volume["read_only"] = info["readOnly"].(bool)
-        volume["type"] = info["type"].(string)
+        volume["mount_path"] = info.MountPath
+        volume["path"] = info.Path
+        volume["read_only"] = info.ReadOnly
+        volume["type"] = info.Type
         volumesInfo[i] = volume
     }
     return volumesInfo
 }
 
-func flattenPodContainerInfo(v []interface{}) []interface{} {
+func flattenPodContainerInfo(v []client.PodContainer) []interface{} {
     containerInfo := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         container := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        imageStringArray := strings.Split(info["image"].(string), "/")
+        imageStringArray := strings.Split(info.Image, "/")
         container["image"] = imageStringArray[len(imageStringArray) - 1]
-        container["name"] = info["name"].(string)
-        container["ports"] = flattenContainerPortsInfo(info["ports"].([]interface{}))
-        container["volumes"] = flattenContainerVolumesInfo(info["volumes"].([]interface{}))
+        container["name"] = info.Name
+        container["ports"] = flattenContainerPortsInfo(info.Ports)
+        container["volumes"] = flattenContainerVolumesInfo(info.Volumes)
         containerInfo[i] = container
     }
     return containerInfo
 }
 
-func flattenServicePortsInfo(v []interface{}) []interface{} {
+func flattenServicePortsInfo(v []client.ServicePort) []interface{} {
     portsInfo := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         port := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        port["port"] = int(info["port"].(float64))
-        port["protocol"] = info["protocol"].(string)
-        port["target_port"] = int(info["target_port"].(float64))
+        port["port"] = info.Port
+        port["protocol"] = info.Protocol
+        port["target_port"] = info.TargetPort
         portsInfo[i] = port
     }
     return portsInfo
 }
 
-func flattenSitePodInfo(v []interface{}) []interface{} {
+func flattenSitePodInfo(v []client.Pod) []interface{} {
     podInfo := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         pod := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        pod["container"] = flattenPodContainerInfo(info["container"].([]interface{}))
-        pod["flavor"] = info["flavor"].(string)
-        pod["message"] = info["message"].(string)
-        pod["name"] = info["name"].(string)
-        pod["reason"] = info["reason"].(string)
-        pod["status"] = info["status"].(string)
+        pod["container"] = flattenPodContainerInfo(info.Containers)
+        pod["flavor"] = info.Flavor
+        pod["message"] = info.Message
+        pod["name"] = info.Name
+        pod["reason"] = info.Reason
+        pod["status"] = info.Status
         podInfo[i] = pod
     }
     return podInfo
 }
 
-func flattenSiteServersInfo(v []interface{}) []interface{} {
+func flattenSiteServersInfo(v []client.SiteServer) []interface{} {
     serversInfo := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         server := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        server["flavor_id"] = fmt.Sprintf("%d", int(info["flavor_id"].(float64)))
-        server["hostname"] = info["hostname"].(string)
-        server["id"] = fmt.Sprintf("%d", int(info["id"].(float64)))
-        server["status"] = info["status"].(string)
+        server["flavor_id"] = info.FlavorID.String()
+        server["hostname"] = info.Hostname
+        server["id"] = info.ID.String()
+        server["status"] = info.Status
         serversInfo[i] = server
     }
     return serversInfo
 }
 
-func flattenSiteServiceInfo(v []interface{}) []interface{} {
+func flattenSiteServiceInfo(v []client.Service) []interface{} {
     serviceInfo := make([]interface{}, len(v))
-    for i, data := range v {
+    for i, info := range v {
         service := make(map[string]interface{})
-        info := data.(map[string]interface{})
-        service["name"] = info["name"].(string)
-        service["net_type"] = info["net_type"].(string)
-        service["ports"] = flattenServicePortsInfo(info["ports"].([]interface{}))
-        service["public_ip"] = info["public_ip"].([]interface{})
+        service["name"] = info.Name
+        service["net_type"] = info.NetType
+        service["ports"] = flattenServicePortsInfo(info.Ports)
+        service["public_ip"] = info.PublicIP
         serviceInfo[i] = service
     }
     return serviceInfo
 }
 
-func siteStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
+// withPodReasons adds the reasons of the failed pods of the container site
+// siteID to err, if the site landed in an error status.
+func withPodReasons(ctx context.Context, config *PConfig, platform string, siteID string, err error) error {
+    statusErr, ok := err.(ErrStatus)
+    if !ok {
+        return err
+    }
 
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
+    container, cerr := config.API.Sites.GetContainer(ctx, platform, siteID)
+    if cerr != nil {
+        log.Printf("[DEBUG] Unable to retrieve pods of apigw_container %s on %s: %v",
+            siteID, platform, cerr)
+        return err
+    }
 
-        if err != nil {
-            return nil, "", err
+    reasons := []string{}
+    if statusErr.Reason != "" {
+        reasons = append(reasons, statusErr.Reason)
+    }
+    for _, pod := range container.Pods {
+        if pod.Reason == "" && pod.Message == "" {
+            continue
         }
-
-        return data, data["status"].(string), nil
+        reasons = append(reasons, fmt.Sprintf(
+            "pod %s is %s: %s %s", pod.Name, pod.Status, pod.Reason, pod.Message))
     }
+    statusErr.Reason = strings.Join(reasons, "\n")
+    return statusErr
 }
 
-func siteStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "Deleted", nil
-            }
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
+// siteWaiter waits for the site siteID on platform.
+func siteWaiter(ctx context.Context, config *PConfig, platform string, siteID string) *waiter {
+    return newWaiter(ctx, config, "site", siteID,
+        func() (interface{}, error) {
+            return config.API.Sites.Get(ctx, platform, siteID)
+        },
+        func(v interface{}) (string, string) {
+            site := v.(*client.Site)
+            return site.Status, site.StatusReason
+        })
 }
@@ -0,0 +1,85 @@
+package apigw
+
+import (
+    "encoding/json"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func TestFlattenContainerPortsInfo(t *testing.T) {
+    cases := []struct {
+        name	string
+        ports	[]client.ContainerPort
+        want	[]interface{}
+    }{
+        {"nil", nil, []interface{}{}},
+        {"ports", []client.ContainerPort{{Name: "http", Port: 80, Protocol: "TCP"}, {Port: 53}},
+            []interface{}{
+                map[string]interface{}{"name": "http", "port": 80, "protocol": "TCP"},
+                map[string]interface{}{"name": "", "port": 53, "protocol": ""},
+            }},
+    }
+
+    for _, c := range cases {
+        if got := flattenContainerPortsInfo(c.ports); !reflect.DeepEqual(got, c.want) {
+            t.Errorf("%s: flattenContainerPortsInfo = %v, want %v", c.name, got, c.want)
+        }
+    }
+}
+
+func TestFlattenSitePodInfo(t *testing.T) {
+    var container client.Container
+    err := json.Unmarshal([]byte(`{"Pod": [{
+        "name": "web-0",
+        "status": "Running",
+        "container": [{"image": "registry.example.com/library/nginx:1.19", "name": "nginx", "ports": null}]
+    }, {
+        "name": "web-1"
+    }]}`), &container)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    pods := flattenSitePodInfo(container.Pods)
+    if len(pods) != 2 {
+        t.Fatalf("flattenSitePodInfo returned %d pods, want 2", len(pods))
+    }
+
+    pod := pods[0].(map[string]interface{})
+    if pod["name"] != "web-0" || pod["status"] != "Running" {
+        t.Errorf("pod = %v, want web-0 Running", pod)
+    }
+    podContainer := pod["container"].([]interface{})[0].(map[string]interface{})
+    if podContainer["image"] != "nginx:1.19" {
+        t.Errorf("image = %v, want nginx:1.19", podContainer["image"])
+    }
+    if ports := podContainer["ports"].([]interface{}); len(ports) != 0 {
+        t.Errorf("ports = %v, want none", ports)
+    }
+
+    if partial := pods[1].(map[string]interface{}); len(partial["container"].([]interface{})) != 0 {
+        t.Errorf("pod = %v, want no containers", partial)
+    }
+}
+
+func FuzzFlattenSitePodInfo(f *testing.F) {
+    f.Add([]byte(`{"Pod": [{"name": "web-0", "container": [{"image": "nginx", "ports": [{"port": 80}]}]}]}`))
+    f.Add([]byte(`{"Pod": [{"container": [{"image": "", "volumes": [{}]}]}, {}]}`))
+    f.Add([]byte(`{"Pod": null, "Service": [{"ports": [{}]}]}`))
+
+    f.Fuzz(func(t *testing.T, data []byte) {
+        var container client.Container
+        if err := json.Unmarshal(data, &container); err != nil {
+            return
+        }
+
+        d := schema.TestResourceDataRaw(t, dataSourceContainer().Schema, map[string]interface{}{})
+        if err := d.Set("pod", flattenSitePodInfo(container.Pods)); err != nil {
+            t.Errorf("Unable to set pod of %s: %v", data, err)
+        }
+    })
+}
@@ -1,53 +1,18 @@
 package apigw
 
 import (
-    "encoding/json"
-
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func snapshotStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
-}
-
-func snapshotStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
+// snapshotWaiter waits for the snapshot snapshotID on platform.
+func snapshotWaiter(ctx context.Context, config *PConfig, platform string, snapshotID string) *waiter {
+    return newWaiter(ctx, config, "snapshot", snapshotID,
+        func() (interface{}, error) {
+            return config.API.Snapshots.Get(ctx, platform, snapshotID)
+        },
+        func(v interface{}) (string, string) {
+            snapshot := v.(*client.Snapshot)
+            return snapshot.Status, snapshot.StatusReason
+        })
 }
@@ -0,0 +1,5 @@
+package apigw
+
+// ProviderVersion is the version of the provider, sent in the User-Agent
+// header. Releases set it from main.version.
+var ProviderVersion = "dev"
@@ -1,71 +1,36 @@
 package apigw
 
 import (
-    "encoding/json"
-    "fmt"
-
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func flattenVolumeHostInfo(v interface{}) map[string]string {
+func flattenVolumeHostInfo(v *client.VolumeHost) map[string]string {
+    if v == nil {
+        return nil
+    }
     host := make(map[string]string)
-    info := v.(map[string]interface{})
-    host["hostname"] = info["hostname"].(string)
-    host["id"] = fmt.Sprintf("%d", int(info["id"].(float64)))
+    host["hostname"] = v.Hostname
+    host["id"] = v.ID.String()
     return host
 }
 
-func flattenVolumeSnapshotsInfo(v []interface{}) []interface{} {
+func flattenVolumeSnapshotsInfo(v []client.ID) []interface{} {
     snapshotsInfo := make([]interface{}, len(v))
-    for i, obj := range v {
-        snapshot := fmt.Sprintf("%d", int(obj.(float64)))
-        snapshotsInfo[i] = snapshot
+    for i, snapshot := range v {
+        snapshotsInfo[i] = snapshot.String()
     }
     return snapshotsInfo
 }
 
-func volumeStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
-}
-
-func volumeStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
+// volumeWaiter waits for the volume volumeID on platform.
+func volumeWaiter(ctx context.Context, config *PConfig, platform string, volumeID string) *waiter {
+    return newWaiter(ctx, config, "volume", volumeID,
+        func() (interface{}, error) {
+            return config.API.Volumes.Get(ctx, platform, volumeID)
+        },
+        func(v interface{}) (string, string) {
+            volume := v.(*client.Volume)
+            return volume.Status, volume.StatusReason
+        })
 }
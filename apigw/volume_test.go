@@ -0,0 +1,52 @@
+package apigw
+
+import (
+    "encoding/json"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
+)
+
+func TestFlattenVolumeHostInfo(t *testing.T) {
+    cases := []struct {
+        name	string
+        data	string
+        want	map[string]string
+    }{
+        {"null", `null`, nil},
+        {"host", `{"id": 7, "hostname": "vcs-7"}`, map[string]string{"id": "7", "hostname": "vcs-7"}},
+        {"embedded id", `{"id": {"id": "7"}}`, map[string]string{"id": "7", "hostname": ""}},
+        {"empty", `{}`, map[string]string{"id": "", "hostname": ""}},
+    }
+
+    for _, c := range cases {
+        var host *client.VolumeHost
+        if err := json.Unmarshal([]byte(c.data), &host); err != nil {
+            t.Fatalf("%s: %v", c.name, err)
+        }
+        if got := flattenVolumeHostInfo(host); !reflect.DeepEqual(got, c.want) {
+            t.Errorf("%s: flattenVolumeHostInfo = %v, want %v", c.name, got, c.want)
+        }
+    }
+}
+
+func FuzzFlattenVolumeHostInfo(f *testing.F) {
+    f.Add([]byte(`{"id": 7, "hostname": "vcs-7"}`))
+    f.Add([]byte(`{"id": null}`))
+    f.Add([]byte(`null`))
+
+    f.Fuzz(func(t *testing.T, data []byte) {
+        var host *client.VolumeHost
+        if err := json.Unmarshal(data, &host); err != nil {
+            return
+        }
+
+        d := schema.TestResourceDataRaw(t, resourceVolume().Schema, map[string]interface{}{})
+        if err := d.Set("attached_host", flattenVolumeHostInfo(host)); err != nil {
+            t.Errorf("Unable to set attached_host of %s: %v", data, err)
+        }
+    })
+}
@@ -1,32 +1,17 @@
 package apigw
 
 import (
-    "encoding/json"
-
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "context"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
-func vpnStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            if _, ok := err.(ErrDefault404); ok {
-                return response, "DELETED", nil
-            }
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        return data, data["status"].(string), nil
-    }
+// vpnWaiter waits for the VPN service vpnID on platform.
+func vpnWaiter(ctx context.Context, config *PConfig, platform string, vpnID string) *waiter {
+    return newWaiter(ctx, config, "VPN service", vpnID,
+        func() (interface{}, error) {
+            return config.API.VPNServices.Get(ctx, platform, vpnID)
+        },
+        func(v interface{}) (string, string) {
+            return v.(*client.VPNService).Status, ""
+        })
 }
@@ -1,69 +1,46 @@
 package apigw
 
 import (
-    "encoding/json"
+    "context"
+    "strings"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+    "apigw_plugin/terraform-provider-apigw/apigw/client"
 )
 
 
-func flattenVPNConnectionInfo(connection interface{}) (VPNConnectionInfo []map[string]interface{}) {
-    if vpn_connection, ok := connection.(map[string]interface{}); ok {
-        VPNConnectionInfo = append(VPNConnectionInfo, vpn_connection)
+func flattenVPNConnectionInfo(connection *client.VPNConnection) (VPNConnectionInfo []map[string]interface{}) {
+    if connection != nil {
+        VPNConnectionInfo = append(VPNConnectionInfo, map[string]interface{}{
+            "dpd_action":	connection.DPDAction,
+            "dpd_interval":	connection.DPDInterval,
+            "dpd_timeout":	connection.DPDTimeout,
+            "initiator":	connection.Initiator,
+            "mtu":		connection.MTU,
+            "peer_address":	connection.PeerAddress,
+            "peer_cidrs":	connection.PeerCIDRs,
+            "peer_id":		connection.PeerID,
+            "status":		connection.Status,
+        })
     }
     return VPNConnectionInfo
 }
 
 
-func connectionStateRefreshFunc(
-        config *PConfig,
-        host string,
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-        if err != nil {
-            return nil, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        connectionData := data["connection"]
-        if connectionData != nil {
-            return connectionData, "CREATED", nil
-        } else {
-            return data, "CREATING", nil
-        }
-    }
-}
-
-func connectionStateRefreshForDeletedFunc(
-        config *PConfig,
-        host string, 
-        resourcePath string) resource.StateRefreshFunc {
-    return func() (interface{}, string, error) {
-        response, err := config.doNormalRequest(host, resourcePath, "GET", nil)
-
-        if err != nil {
-            return response, "", err
-        }
-
-        var data map[string]interface{}
-        err = json.Unmarshal([]byte(response), &data)
-
-        if err != nil {
-            return nil, "", err
-        }
-
-        connectionData := data["connection"]
-        if connectionData == nil {
-            return data, "DELETED", nil
-        } else {
-            return connectionData, "DELETING", nil
-        }
-    }
+// connectionWaiter waits for the connection of the VPN service vpnID on
+// platform. The connection is CREATED while it exists and NONE otherwise.
+func connectionWaiter(ctx context.Context, config *PConfig, platform string, vpnID string) *waiter {
+    return newWaiter(ctx, config, "VPN connection", vpnID,
+        func() (interface{}, error) {
+            return config.API.VPNServices.Get(ctx, platform, vpnID)
+        },
+        func(v interface{}) (string, string) {
+            connection := v.(*client.VPNService).Connection
+            if connection == nil {
+                return "NONE", ""
+            }
+            if strings.EqualFold(connection.Status, "ERROR") {
+                return connection.Status, ""
+            }
+            return "CREATED", ""
+        })
 }
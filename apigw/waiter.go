@@ -0,0 +1,174 @@
+package apigw
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+// statusDeleted is the status of a resource which is gone. Waiting for it
+// turns a 404 into success instead of an error.
+const statusDeleted = "DELETED"
+
+// ErrStatus is returned while waiting for a resource which landed in an
+// error status, i.e. the asynchronous operation on it failed.
+type ErrStatus struct {
+    Resource	string
+    ID		string
+    Status	string
+    // Reason is the status_reason reported by the gateway, if any.
+    Reason	string
+}
+
+func (e ErrStatus) Error() string {
+    msg := fmt.Sprintf("%s %s is in status %s", e.Resource, e.ID, e.Status)
+    if e.Reason != "" {
+        msg += ": " + e.Reason
+    }
+    return msg
+}
+
+// waiter polls a resource until it reaches a target status. Statuses are
+// compared case-insensitively, as sites report e.g. Ready and Error while
+// all other resources report ACTIVE and ERROR.
+type waiter struct {
+    // Resource and ID name the resource in errors and log messages.
+    Resource	string
+    ID		string
+    // Get retrieves the resource.
+    Get		func() (interface{}, error)
+    // Status returns the status and status reason of the resource returned
+    // by Get. An empty status is treated as pending.
+    Status	func(interface{}) (string, string)
+    // Failure lists the statuses which end the wait with an ErrStatus.
+    Failure	[]string
+    // PollInterval is the time between polls. If zero, polls back off from
+    // MinTimeout up to 10 seconds.
+    PollInterval	time.Duration
+    MinTimeout		time.Duration
+    // Context stops the wait when it is done, e.g. when terraform is
+    // interrupted.
+    Context	context.Context
+}
+
+// newWaiter returns a waiter failing on ERROR, polling as configured for the
+// provider until ctx is done.
+func newWaiter(
+        ctx context.Context,
+        config *PConfig,
+        kind string,
+        id string,
+        get func() (interface{}, error),
+        status func(interface{}) (string, string)) *waiter {
+    return &waiter{
+        Resource:	kind,
+        ID:		id,
+        Get:		get,
+        Status:		status,
+        Failure:	[]string{"ERROR"},
+        PollInterval:	config.PollInterval,
+        MinTimeout:	config.PollMinTimeout,
+        Context:	ctx,
+    }
+}
+
+// Wait polls the resource while its status is in pending until it is in
+// target. A resource which is gone has the status DELETED.
+func (w *waiter) Wait(pending, target []string, timeout time.Duration) (interface{}, error) {
+    ctx := w.Context
+    if ctx == nil {
+        ctx = context.Background()
+    }
+
+    pending = normalizeStatuses(append([]string{""}, pending...))
+    target = normalizeStatuses(target)
+    failure := normalizeStatuses(w.Failure)
+    gone := containsStatus(target, statusDeleted)
+
+    last := ""
+    conf := &resource.StateChangeConf{
+        Pending:	pending,
+        Target:		target,
+        Timeout:	timeout,
+        PollInterval:	w.PollInterval,
+        MinTimeout:	w.MinTimeout,
+        Refresh: func() (interface{}, string, error) {
+            if err := ctx.Err(); err != nil {
+                return nil, "", err
+            }
+
+            obj, err := w.Get()
+            status, reason := statusDeleted, ""
+            if err != nil {
+                if !gone || !IsNotFound(err) {
+                    return nil, "", err
+                }
+                obj = err
+            } else {
+                status, reason = w.Status(obj)
+                status = strings.ToUpper(status)
+            }
+
+            if status != last {
+                log.Printf("[DEBUG] %s %s changed status from %q to %q", w.Resource, w.ID, last, status)
+                last = status
+            }
+
+            if containsStatus(failure, status) {
+                return obj, status, ErrStatus{
+                    Resource:	w.Resource,
+                    ID:		w.ID,
+                    Status:	status,
+                    Reason:	reason,
+                }
+            }
+            return obj, status, nil
+        },
+    }
+
+    // StateChangeConf sleeps between polls regardless of ctx, so stop
+    // waiting for it as soon as ctx is done. Its next poll then fails.
+    type result struct {
+        obj	interface{}
+        err	error
+    }
+    done := make(chan result, 1)
+    go func() {
+        obj, err := conf.WaitForState()
+        done <- result{obj, err}
+    }()
+
+    select {
+    case r := <-done:
+        return r.obj, r.err
+    case <-ctx.Done():
+        return nil, fmt.Errorf("Stopped waiting for %s %s: %v", w.Resource, w.ID, ctx.Err())
+    }
+}
+
+// existsStatus is the status of resources without one, which are ACTIVE
+// until they are gone.
+func existsStatus(interface{}) (string, string) {
+    return "ACTIVE", ""
+}
+
+func normalizeStatuses(statuses []string) []string {
+    normalized := make([]string, len(statuses))
+    for i, status := range statuses {
+        normalized[i] = strings.ToUpper(status)
+    }
+    return normalized
+}
+
+func containsStatus(statuses []string, status string) bool {
+    for _, s := range statuses {
+        if s == status {
+            return true
+        }
+    }
+    return false
+}
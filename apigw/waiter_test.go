@@ -0,0 +1,190 @@
+package apigw
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "reflect"
+    "testing"
+    "time"
+)
+
+// stubStatus is a status reported by the Get of a stubWaiter, or the error
+// returned instead.
+type stubStatus struct {
+    status	string
+    reason	string
+    err		error
+}
+
+// stubWaiter returns a waiter whose Get reports statuses one after the
+// other, repeating the last one, and a func returning the number of polls.
+func stubWaiter(failure []string, statuses ...stubStatus) (*waiter, func() int) {
+    polls := 0
+    w := &waiter{
+        Resource:	"network",
+        ID:		"1",
+        Get: func() (interface{}, error) {
+            s := statuses[len(statuses) - 1]
+            if polls < len(statuses) {
+                s = statuses[polls]
+            }
+            polls++
+            if s.err != nil {
+                return nil, s.err
+            }
+            return s, nil
+        },
+        Status: func(v interface{}) (string, string) {
+            s := v.(stubStatus)
+            return s.status, s.reason
+        },
+        Failure:	failure,
+        PollInterval:	time.Millisecond,
+    }
+    return w, func() int { return polls }
+}
+
+func TestWaiterTarget(t *testing.T) {
+    w, polls := stubWaiter([]string{"ERROR"},
+        stubStatus{status: "BUILD"}, stubStatus{status: "BUILD"}, stubStatus{status: "ACTIVE"})
+    obj, err := w.Wait([]string{"BUILD"}, []string{"ACTIVE"}, time.Minute)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if obj.(stubStatus).status != "ACTIVE" || polls() != 3 {
+        t.Errorf("Wait = %v after %d polls, want ACTIVE after 3", obj, polls())
+    }
+}
+
+func TestWaiterCaseInsensitive(t *testing.T) {
+    // Sites report Ready and Error, all other resources READY and ERROR.
+    w, _ := stubWaiter([]string{"ERROR"}, stubStatus{status: "Pending"}, stubStatus{status: "Ready"})
+    if _, err := w.Wait([]string{"PENDING"}, []string{"READY"}, time.Minute); err != nil {
+        t.Errorf("Wait for Ready = %v", err)
+    }
+
+    w, _ = stubWaiter([]string{"ERROR"}, stubStatus{status: "PENDING"}, stubStatus{status: "READY"})
+    if _, err := w.Wait([]string{"Pending"}, []string{"Ready"}, time.Minute); err != nil {
+        t.Errorf("Wait for READY = %v", err)
+    }
+}
+
+func TestWaiterEmptyStatus(t *testing.T) {
+    // An empty status is pending even though it is not listed.
+    w, polls := stubWaiter([]string{"ERROR"}, stubStatus{}, stubStatus{}, stubStatus{status: "ACTIVE"})
+    if _, err := w.Wait([]string{"BUILD"}, []string{"ACTIVE"}, time.Minute); err != nil {
+        t.Fatal(err)
+    }
+    if polls() != 3 {
+        t.Errorf("%d polls, want 3", polls())
+    }
+}
+
+func TestWaiterFailure(t *testing.T) {
+    cases := []struct {
+        name		string
+        failure		[]string
+        status		stubStatus
+        want		error
+    }{
+        {
+            "error",
+            []string{"ERROR"},
+            stubStatus{status: "ERROR", reason: "no capacity"},
+            ErrStatus{Resource: "network", ID: "1", Status: "ERROR", Reason: "no capacity"},
+        },
+        {
+            "lower case error",
+            []string{"ERROR"},
+            stubStatus{status: "error"},
+            ErrStatus{Resource: "network", ID: "1", Status: "ERROR"},
+        },
+        {
+            "additional failure",
+            []string{"ERROR", "Degraded"},
+            stubStatus{status: "DEGRADED", reason: "link down"},
+            ErrStatus{Resource: "network", ID: "1", Status: "DEGRADED", Reason: "link down"},
+        },
+    }
+
+    for _, c := range cases {
+        w, _ := stubWaiter(c.failure, stubStatus{status: "BUILD"}, c.status)
+        _, err := w.Wait([]string{"BUILD"}, []string{"ACTIVE"}, time.Minute)
+        var errStatus ErrStatus
+        if !errors.As(err, &errStatus) || !reflect.DeepEqual(errStatus, c.want) {
+            t.Errorf("%s: Wait = %#v, want %#v", c.name, err, c.want)
+        }
+    }
+
+    // Without a failure set, an ERROR is just an unexpected status.
+    w, _ := stubWaiter(nil, stubStatus{status: "ERROR"})
+    _, err := w.Wait([]string{"BUILD"}, []string{"ACTIVE"}, time.Minute)
+    var errStatus ErrStatus
+    if err == nil || errors.As(err, &errStatus) {
+        t.Errorf("Wait without failures = %#v, want an unexpected state error", err)
+    }
+}
+
+func TestWaiterNotFound(t *testing.T) {
+    notFound := ErrDefault404{ErrUnexpectedResponseCode{Actual: http.StatusNotFound}}
+
+    // A resource which is gone is DELETED when waiting for its delete.
+    w, _ := stubWaiter([]string{"ERROR"}, stubStatus{status: "DELETING"}, stubStatus{err: notFound})
+    if _, err := w.Wait([]string{"DELETING"}, []string{statusDeleted}, time.Minute); err != nil {
+        t.Errorf("Wait for DELETED = %v", err)
+    }
+
+    // Otherwise a 404 ends the wait with it.
+    w, polls := stubWaiter([]string{"ERROR"}, stubStatus{status: "BUILD"}, stubStatus{err: notFound})
+    if _, err := w.Wait([]string{"BUILD"}, []string{"ACTIVE"}, time.Minute); !IsNotFound(err) {
+        t.Errorf("Wait for ACTIVE = %v, want the 404", err)
+    }
+    if polls() != 2 {
+        t.Errorf("%d polls, want 2", polls())
+    }
+
+    // Other errors end a wait for DELETED as well.
+    failed := errors.New("failed")
+    w, _ = stubWaiter([]string{"ERROR"}, stubStatus{err: failed})
+    if _, err := w.Wait([]string{"DELETING"}, []string{statusDeleted}, time.Minute); err != failed {
+        t.Errorf("Wait for DELETED = %v, want %v", err, failed)
+    }
+}
+
+func TestWaiterStopped(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    w := &waiter{
+        Resource:	"network",
+        ID:		"1",
+        Get: func() (interface{}, error) {
+            // Stop while the network is being built, the next poll is a
+            // minute away.
+            cancel()
+            return "BUILD", nil
+        },
+        Status: func(v interface{}) (string, string) {
+            return v.(string), ""
+        },
+        PollInterval:	time.Minute,
+        Context:	ctx,
+    }
+
+    start := time.Now()
+    if _, err := w.Wait([]string{"BUILD"}, []string{"ACTIVE"}, time.Hour); err == nil {
+        t.Fatal("expected the wait to be stopped")
+    }
+    if elapsed := time.Since(start); elapsed > 5 * time.Second {
+        t.Errorf("wait took %s to stop", elapsed)
+    }
+
+    // A wait which is stopped before it begins does not poll.
+    w, polls := stubWaiter([]string{"ERROR"}, stubStatus{status: "ACTIVE"})
+    w.Context = ctx
+    if _, err := w.Wait([]string{"BUILD"}, []string{"ACTIVE"}, time.Hour); err == nil {
+        t.Error("expected the wait to be stopped")
+    }
+    if polls() != 0 {
+        t.Errorf("%d polls of a stopped wait, want 0", polls())
+    }
+}
@@ -0,0 +1,38 @@
+// Command apigw-mock serves an in-memory fake of the APIGW v4 API for local
+// development:
+//
+//    $ apigw-mock -addr 127.0.0.1:8080 -apikey secret
+//    $ export APIGW_URL=http://127.0.0.1:8080/ APIGW_APIKEY=secret
+//    $ terraform apply
+package main
+
+import (
+    "flag"
+    "log"
+    "net/http"
+    "os"
+    "strings"
+
+    "apigw_plugin/terraform-provider-apigw/apigw/mock"
+)
+
+func main() {
+    addr := flag.String("addr", "127.0.0.1:8080", "Address to listen on.")
+    apiKey := flag.String("apikey", os.Getenv("APIGW_APIKEY"), "API key to accept, defaults to $APIGW_APIKEY.")
+    platforms := flag.String("platforms", "", "Comma separated list of valid platforms, all if empty.")
+    polls := flag.Int("polls", 1, "Number of reads an object stays in a transitional status.")
+    flag.Parse()
+
+    if *apiKey == "" {
+        log.Fatal("An API key must be given with -apikey or APIGW_APIKEY")
+    }
+
+    server := mock.New(*apiKey)
+    server.Polls = *polls
+    if *platforms != "" {
+        server.Platforms = strings.Split(*platforms, ",")
+    }
+
+    log.Printf("Serving APIGW mock on http://%s/", *addr)
+    log.Fatal(http.ListenAndServe(*addr, server))
+}
@@ -6,8 +6,11 @@ import (
     "github.com/hashicorp/terraform-plugin-sdk/plugin"
 )
 
+// version is set by goreleaser.
+var version = "dev"
+
 func main() {
+    apigw.ProviderVersion = version
     plugin.Serve(&plugin.ServeOpts{
         ProviderFunc: apigw.Provider})
 }
-